@@ -90,6 +90,30 @@ JavaScript API Structure:
 
   - js_structure: API structure - namespaced|flat|service_based (default: "namespaced")
   - js_namespace: Global JavaScript namespace (default: lowercase package name)
+  - js_target: Where the WASM module is loaded - browser|node (default: "browser").
+    With node, the build script also copies Go's wasm_exec_node.js. Should match the
+    js_target given to protoc-gen-go-wasmjs-ts.
+
+Wire Format:
+
+  - wire_format: Encoding between TypeScript clients and WASM exports - json|binary (default: "json").
+    With binary, requests and responses cross the boundary as protobuf bytes in a Uint8Array
+    instead of JSON strings. Must match the wire_format given to protoc-gen-go-wasmjs-ts.
+
+JSON Encoding (wire_format=json):
+
+  - json_use_proto_names: Use proto field names (snake_case) in requests and responses (default: false).
+    Must match the json_use_proto_names given to protoc-gen-go-wasmjs-ts.
+  - json_emit_unpopulated: Emit fields holding zero values in responses (default: true)
+  - json_use_enum_numbers: Emit enum values as numbers instead of names (default: false)
+  - json_discard_unknown: Ignore unknown fields in requests (default: true)
+  - json_allow_partial: Accept messages with missing required fields (default: true)
+
+json_discard_unknown and json_allow_partial also apply to requests in wire_format=binary,
+except to messages with vtprotobuf methods.
+
+The service_json and method_json annotations override these per service or method, all but
+json_use_proto_names, which generated TypeScript types follow.
 
 Service & Method Selection:
 
@@ -102,6 +126,20 @@ Build Integration:
 
   - wasm_package_suffix: Package suffix for WASM wrapper (default: "wasm")
   - generate_build_script: Generate build.sh script (default: true)
+  - target: Compiler the WASM module is built with - go|tinygo (default: "go").
+    With tinygo, exports and browser clients encode messages with their vtprotobuf
+    MarshalVT/UnmarshalVT methods instead of protobuf reflection, and the build script
+    runs tinygo. The generated code leaves gRPC out: streams use the pkg/wasm stream
+    interfaces, and exports have no server interceptors or call metadata. Requires
+    wire_format=binary and messages generated with protoc-gen-go-vtproto
+    (features=marshal+unmarshal+size); host_exports is not supported.
+  - host_browser_clients: Emit browser clients without the js/wasm build constraint (default: false).
+    Outside js/wasm they call the Go fakes registered on wasm.FakeBrowserChannel, so code
+    using them can be unit tested with go test.
+  - host_exports: Also generate a {Package}ServicesDispatcher that calls the exported methods by
+    their JavaScript names with JSON (or binary) requests, without the js/wasm build constraint
+    (default: false). It shares the exports' parsing, interceptors and error envelope, so
+    contract tests of the JavaScript-facing API run with go test.
 
 # Usage Example
 
@@ -150,17 +188,16 @@ Implement your service with dependency injection:
 	    // Register JavaScript API
 	    exports.RegisterAPI()
 
-	    // Keep WASM running
-	    select {}
+	    // Keep WASM running until the module is unregistered
+	    exports.Wait()
 	}
 
 Build the WASM binary:
 
-	cd gen/wasm/library/v1
-	GOOS=js GOARCH=wasm go build -o library.wasm
+	GOOS=js GOARCH=wasm go build -o library.wasm ./cmd/wasm
 
-	# Or use the generated build script
-	./build.sh
+	# Or use the generated build script with your main package and output directory
+	bash gen/wasm/library/v1/build.sh ./cmd/wasm ./web/public
 
 # JavaScript API Structures
 
@@ -302,10 +339,25 @@ func main() {
 	jsStructure := flagSet.String("js_structure", "namespaced", "JavaScript API structure (namespaced|flat|service_based)")
 	jsNamespace := flagSet.String("js_namespace", "", "Global JavaScript namespace (default: lowercase package name)")
 	moduleName := flagSet.String("module_name", "", "WASM module name (default: package_services)")
+	jsTarget := flagSet.String("js_target", "browser", "Where the WASM module is loaded (browser|node); selects the loader the build script sets up")
+
+	// Wire format
+	wireFormat := flagSet.String("wire_format", "json", "Encoding between TypeScript clients and WASM exports (json|binary)")
+
+	// JSON encoding (json wire format); service_json and method_json annotations override these
+	defaultJSON := builders.DefaultJSONOptions()
+	jsonUseProtoNames := flagSet.Bool("json_use_proto_names", defaultJSON.UseProtoNames, "Use proto field names instead of lowerCamelCase JSON names")
+	jsonEmitUnpopulated := flagSet.Bool("json_emit_unpopulated", defaultJSON.EmitUnpopulated, "Emit fields holding zero values in responses")
+	jsonUseEnumNumbers := flagSet.Bool("json_use_enum_numbers", defaultJSON.UseEnumNumbers, "Emit enum values as numbers instead of names")
+	jsonDiscardUnknown := flagSet.Bool("json_discard_unknown", defaultJSON.DiscardUnknown, "Ignore unknown fields in requests")
+	jsonAllowPartial := flagSet.Bool("json_allow_partial", defaultJSON.AllowPartial, "Accept messages with missing required fields")
 
 	// Build integration
 	wasmPackageSuffix := flagSet.String("wasm_package_suffix", "wasm", "Package suffix for WASM wrapper")
 	generateBuildScript := flagSet.Bool("generate_build_script", true, "Generate build script for WASM compilation")
+	target := flagSet.String("target", "go", "Compiler the WASM module is built with (go|tinygo)")
+	hostBrowserClients := flagSet.Bool("host_browser_clients", false, "Build browser clients outside js/wasm against fake browser channels")
+	hostExports := flagSet.Bool("host_exports", false, "Generate a host-buildable dispatcher of the exported methods for go test")
 
 	protogen.Options{
 		ParamFunc: flagSet.Set,
@@ -328,8 +380,20 @@ func main() {
 			JSStructure:         *jsStructure,
 			JSNamespace:         *jsNamespace,
 			ModuleName:          *moduleName,
+			JSTarget:            *jsTarget,
+			WireFormat:          *wireFormat,
+			JSON: builders.JSONOptions{
+				UseProtoNames:   *jsonUseProtoNames,
+				EmitUnpopulated: *jsonEmitUnpopulated,
+				UseEnumNumbers:  *jsonUseEnumNumbers,
+				DiscardUnknown:  *jsonDiscardUnknown,
+				AllowPartial:    *jsonAllowPartial,
+			},
 			WasmPackageSuffix:   *wasmPackageSuffix,
 			GenerateBuildScript: *generateBuildScript,
+			Target:              *target,
+			HostBrowserClients:  *hostBrowserClients,
+			HostExports:         *hostExports,
 		}
 
 		// Create filter criteria from configuration
@@ -108,6 +108,18 @@ JavaScript API Structure:
 
   - js_structure: API structure - namespaced|flat|service_based (default: "namespaced")
   - js_namespace: Global JavaScript namespace (default: lowercase package name)
+  - js_target: Where the generated bundle loads the WASM module - browser|node (default: "browser").
+    With node, the bundle reads the WASM file from disk with nodeWasmLoader (from
+    @protoc-gen-go-wasmjs/runtime/node), for server-side rendering, CLI tools and tests.
+
+Wire Format:
+
+  - wire_format: Encoding between TypeScript clients and WASM exports - json|binary (default: "json").
+    With binary, the generated bundle encodes requests and decodes responses as protobuf bytes
+    using the generated schemas. Must match the wire_format given to protoc-gen-go-wasmjs-go.
+  - json_use_proto_names: Name fields of generated interfaces, models and schemas after proto
+    field names (snake_case) instead of JSON names (default: false). Must match the
+    json_use_proto_names given to protoc-gen-go-wasmjs-go.
 
 Content Filtering:
 
@@ -115,6 +127,12 @@ Content Filtering:
   - generate_types: Generate TypeScript interfaces/models (default: true)
   - generate_factories: Generate TypeScript factory classes (default: true)
 
+Hosting:
+
+  - generate_worker: Generate worker.ts next to index.ts, a Web Worker entry point that hosts
+    the WASM module off the main thread (default: false). Load the module with
+    bundle.loadWasmInWorker(worker, wasmPath) instead of bundle.loadWasm(wasmPath).
+
 Service & Method Selection:
 
   - services: Comma-separated list of services to generate clients for (default: all)
@@ -428,15 +446,23 @@ func main() {
 	// JavaScript API structure
 	jsStructure := flagSet.String("js_structure", "namespaced", "JavaScript API structure (namespaced|flat|service_based)")
 	jsNamespace := flagSet.String("js_namespace", "", "Global JavaScript namespace (default: lowercase package name)")
+	jsTarget := flagSet.String("js_target", "browser", "Where the generated bundle loads the WASM module (browser|node)")
 	
 	// TypeScript-specific options
 	moduleName := flagSet.String("module_name", "", "TypeScript module name (default: package_services)")
 
+	// Wire format
+	wireFormat := flagSet.String("wire_format", "json", "Encoding between TypeScript clients and WASM exports (json|binary)")
+	jsonUseProtoNames := flagSet.Bool("json_use_proto_names", false, "Use proto field names instead of lowerCamelCase JSON names in generated types")
+
 	// Content filtering
 	generateClients := flagSet.Bool("generate_clients", true, "Generate TypeScript client classes for services")
 	generateTypes := flagSet.Bool("generate_types", true, "Generate TypeScript interfaces and models for messages/enums")
 	generateFactories := flagSet.Bool("generate_factories", true, "Generate TypeScript factory classes for creating message objects")
 
+	// Hosting
+	generateWorker := flagSet.Bool("generate_worker", false, "Generate a Web Worker entry point (worker.ts) that hosts the WASM module off the main thread")
+
 	protogen.Options{
 		ParamFunc: flagSet.Set,
 	}.Run(func(gen *protogen.Plugin) error {
@@ -446,10 +472,14 @@ func main() {
 			TSExportPath:      *tsExportPath,
 			JSStructure:       *jsStructure,
 			JSNamespace:       *jsNamespace,
+			JSTarget:          *jsTarget,
 			ModuleName:        *moduleName,
+			WireFormat:        *wireFormat,
+			JSON:              builders.JSONOptions{UseProtoNames: *jsonUseProtoNames},
 			GenerateClients:   *generateClients,
 			GenerateTypes:     *generateTypes,
 			GenerateFactories: *generateFactories,
+			GenerateWorker:    *generateWorker,
 		}
 
 		// Create filter criteria from configuration
@@ -200,8 +200,8 @@ Implement the service in Go with dependency injection:
 	    // Register JavaScript API
 	    exports.RegisterAPI()
 
-	    // Keep WASM running
-	    select {}
+	    // Keep WASM running until the module is unregistered
+	    exports.Wait()
 	}
 
 Build the WASM binary:
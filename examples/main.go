@@ -191,6 +191,6 @@ func main() {
 
 	fmt.Println("Example WASM module ready!")
 
-	// Keep the WASM module running
-	select {}
+	// Keep the WASM module running until it is unregistered
+	presenterExports.Wait()
 }
@@ -15,8 +15,10 @@
 package builders
 
 import (
+	"fmt"
 	"strings"
 
+	"google.golang.org/grpc/codes"
 	"google.golang.org/protobuf/compiler/protogen"
 
 	"github.com/panyam/protoc-gen-go-wasmjs/pkg/core"
@@ -45,16 +47,38 @@ type GoTemplateData struct {
 	// JavaScript API configuration
 	JSNamespace  string // Global namespace (e.g., "library_v1")
 	APIStructure string // namespaced|flat|service_based
+	WireFormat   string // json|binary
+	JSTarget     string // browser|node (selects the loader the build script sets up)
+	Target       string // go|tinygo (tinygo avoids protobuf reflection and builds with tinygo)
+
+	// HostBrowserClients omits the js/wasm build constraint from browser clients
+	HostBrowserClients bool
+
+	// HostExports generates a dispatcher of the exported methods that builds outside js/wasm,
+	// and omits the js/wasm build constraint from the service interfaces it needs
+	HostExports bool
 
 	// Import management
-	Imports    []ImportInfo      // Go package imports
-	PackageMap map[string]string // Import path to alias mapping
+	Imports              []ImportInfo      // Go package imports
+	ServiceImports       []ImportInfo      // Imports referenced by service request/response types
+	StreamImports        []ImportInfo      // Imports referenced by stream wrapper request/response types
+	BrowserClientImports []ImportInfo      // Imports referenced by browser client request/response types
+	PackageMap           map[string]string // Import path to alias mapping
+
+	// Request and response types, each listed once, that TinyGo builds require
+	// vtprotobuf methods on
+	ServiceMessageTypes       []string
+	BrowserClientMessageTypes []string
 
 	// Flags
 	HasMessages        bool // Whether any messages exist
 	HasEnums           bool // Whether any enums exist
 	HasServices        bool // Whether any services to implement exist
 	HasBrowserClients  bool // Whether any browser clients exist
+	HasServerStreaming bool // Whether any service method is server streaming
+	HasClientStreaming bool // Whether any service method is client or bidirectional streaming
+	HasBrowserRetries  bool // Whether any browser client method has a retry policy
+	HasBrowserStreams  bool // Whether any browser client method is server streaming
 }
 
 // GoDataBuilder builds template data structures specifically for Go WASM generation.
@@ -135,6 +159,20 @@ func (gb *GoDataBuilder) BuildTemplateData(
 	moduleName := gb.getModuleName(packageInfo.Name, config)
 	jsNamespace := gb.getJSNamespace(packageInfo.Name, config)
 
+	// Each generated file only imports the packages its types reference,
+	// since Go rejects unused imports
+	imports := context.GetImports()
+	requestAndResponse := func(m MethodData) []string { return []string{m.RequestType, m.ResponseType} }
+	streamTypes := func(m MethodData) []string {
+		if m.IsClientStreaming {
+			return []string{m.RequestType, m.ResponseType}
+		}
+		if m.IsServerStreaming {
+			return []string{m.ResponseType}
+		}
+		return nil
+	}
+
 	return &GoTemplateData{
 		PackageName:        packageInfo.Name,
 		SourcePath:         gb.getPrimarySourcePath(packageInfo.Files),
@@ -146,15 +184,106 @@ func (gb *GoDataBuilder) BuildTemplateData(
 		BrowserClients:     browserClients,
 		JSNamespace:        jsNamespace,
 		APIStructure:       config.JSStructure,
-		Imports:            context.GetImports(),
-		PackageMap:         context.ImportMap,
+		WireFormat:         config.WireFormat,
+		JSTarget:           config.JSTarget,
+		Target:             config.Target,
+		HostBrowserClients: config.HostBrowserClients,
+		HostExports:        config.HostExports,
+		Imports:              imports,
+		ServiceImports:       importsForTypes(imports, serviceImplementations, requestAndResponse),
+		StreamImports:        importsForTypes(imports, serviceImplementations, streamTypes),
+		BrowserClientImports: importsForTypes(imports, browserClients, requestAndResponse),
+		PackageMap:           context.ImportMap,
+		ServiceMessageTypes:       messageTypes(serviceImplementations, requestAndResponse),
+		BrowserClientMessageTypes: messageTypes(browserClients, requestAndResponse),
 		HasMessages:        len(messages) > 0,
 		HasEnums:           len(enums) > 0,
 		HasServices:        len(serviceImplementations) > 0,
 		HasBrowserClients:  len(browserClients) > 0,
+		HasServerStreaming: hasServerStreaming(serviceImplementations),
+		HasClientStreaming: hasClientStreaming(serviceImplementations),
+		HasBrowserRetries:  hasRetryPolicies(browserClients),
+		HasBrowserStreams:  hasServerStreaming(browserClients),
 	}, nil
 }
 
+// importsForTypes returns the imports whose alias qualifies one of the Go types
+// selected from the given services' methods.
+func importsForTypes(imports []ImportInfo, services []ServiceData, typesOf func(MethodData) []string) []ImportInfo {
+	used := make(map[string]bool)
+	for _, service := range services {
+		for _, method := range service.Methods {
+			for _, goType := range typesOf(method) {
+				if idx := strings.Index(goType, "."); idx > 0 {
+					used[goType[:idx]] = true
+				}
+			}
+		}
+	}
+
+	var result []ImportInfo
+	for _, imp := range imports {
+		if used[imp.Alias] {
+			result = append(result, imp)
+		}
+	}
+	return result
+}
+
+// messageTypes returns the Go types selected from the given services' methods,
+// each listed once in order of first use.
+func messageTypes(services []ServiceData, typesOf func(MethodData) []string) []string {
+	seen := make(map[string]bool)
+	var result []string
+	for _, service := range services {
+		for _, method := range service.Methods {
+			for _, goType := range typesOf(method) {
+				if !seen[goType] {
+					seen[goType] = true
+					result = append(result, goType)
+				}
+			}
+		}
+	}
+	return result
+}
+
+// hasServerStreaming reports whether any method of the given services is server streaming.
+func hasServerStreaming(services []ServiceData) bool {
+	for _, service := range services {
+		for _, method := range service.Methods {
+			if method.IsServerStreaming {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// hasRetryPolicies reports whether any method of the given services has a retry policy.
+func hasRetryPolicies(services []ServiceData) bool {
+	for _, service := range services {
+		for _, method := range service.Methods {
+			if method.Retry != nil {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// hasClientStreaming reports whether any method of the given services is client or bidirectional streaming.
+func hasClientStreaming(services []ServiceData) bool {
+	for _, service := range services {
+		for _, method := range service.Methods {
+			if method.IsClientStreaming {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 // collectMessages collects all messages from the package files.
 // Messages are always generated regardless of service presence.
 func (gb *GoDataBuilder) collectMessages(
@@ -311,6 +440,14 @@ func (gb *GoDataBuilder) buildServiceData(
 		}
 
 		methodData := gb.buildMethodData(method, serviceName, methodResult, context)
+		// Retries apply to unary calls; streams end when the browser ends them
+		if serviceResult.IsBrowserProvided && !methodResult.IsServerStreaming {
+			retry, err := gb.methodRetryPolicy(method)
+			if err != nil {
+				return nil, err
+			}
+			methodData.Retry = retry
+		}
 		methods = append(methods, methodData)
 	}
 
@@ -405,13 +542,81 @@ func (gb *GoDataBuilder) buildMethodData(
 		GoFuncName:        goFuncName,
 		ShouldGenerate:    true, // This method passed filtering, so it should be generated
 		Comment:           strings.TrimSpace(string(method.Comments.Leading)),
+		FullMethod:        fmt.Sprintf("/%s/%s", method.Parent.Desc.FullName(), method.Desc.Name()),
 		RequestType:       requestType,
 		ResponseType:      responseType,
 		RequestTSType:     string(method.Input.GoIdent.GoName),
 		ResponseTSType:    string(method.Output.GoIdent.GoName),
+		RequestProtoType:  string(method.Input.Desc.FullName()),
+		ResponseProtoType: string(method.Output.Desc.FullName()),
 		IsAsync:           methodResult.IsAsync,
 		IsServerStreaming: methodResult.IsServerStreaming,
+		IsClientStreaming: methodResult.IsClientStreaming,
+		TimeoutMillis:     gb.methodTimeout(method, methodResult.IsServerStreaming, methodResult.IsClientStreaming),
+		JSON:              gb.methodJSONOptions(method, context.Config),
+	}
+}
+
+// methodTimeout determines the default context timeout of an exported method.
+// Timeout annotations win; otherwise server streams get a longer default than unary calls,
+// and client and bidirectional streams, which JavaScript ends, get none (0).
+func (gb *GoDataBuilder) methodTimeout(method *protogen.Method, isServerStreaming, isClientStreaming bool) uint32 {
+	if timeoutMs, ok := gb.analyzer.GetMethodTimeout(method); ok {
+		return timeoutMs
+	}
+	if isClientStreaming {
+		return 0
+	}
+	if isServerStreaming {
+		return DefaultStreamingTimeoutMillis
+	}
+	return DefaultMethodTimeoutMillis
+}
+
+// methodJSONOptions determines the JSON encoding options of an exported method.
+// Fields set by service_json or method_json annotations override the plugin options.
+func (gb *GoDataBuilder) methodJSONOptions(method *protogen.Method, config *GenerationConfig) JSONOptions {
+	opts := config.JSON
+	annotated := gb.analyzer.GetMethodJSONOptions(method)
+	if annotated.EmitUnpopulated != nil {
+		opts.EmitUnpopulated = annotated.GetEmitUnpopulated()
+	}
+	if annotated.UseEnumNumbers != nil {
+		opts.UseEnumNumbers = annotated.GetUseEnumNumbers()
+	}
+	if annotated.DiscardUnknown != nil {
+		opts.DiscardUnknown = annotated.GetDiscardUnknown()
+	}
+	if annotated.AllowPartial != nil {
+		opts.AllowPartial = annotated.GetAllowPartial()
+	}
+	return opts
+}
+
+// methodRetryPolicy determines the retry policy of a browser-provided method from the
+// method_retry and service_retry annotations. Returns nil when neither is set.
+func (gb *GoDataBuilder) methodRetryPolicy(method *protogen.Method) (*RetryPolicyData, error) {
+	policy := gb.analyzer.GetMethodRetryPolicy(method)
+	if policy == nil {
+		return nil, nil
+	}
+
+	retry := &RetryPolicyData{
+		MaxAttempts:          policy.GetMaxAttempts(),
+		InitialBackoffMillis: policy.GetInitialBackoffMs(),
+		MaxBackoffMillis:     policy.GetMaxBackoffMs(),
+		BackoffMultiplier:    policy.GetBackoffMultiplier(),
+		AttemptTimeoutMillis: policy.GetAttemptTimeoutMs(),
+	}
+	for _, name := range policy.GetRetryableCodes() {
+		// Accept the names used by gRPC service configs (e.g., "UNAVAILABLE")
+		var code codes.Code
+		if err := code.UnmarshalJSON([]byte(fmt.Sprintf("%q", name))); err != nil {
+			return nil, fmt.Errorf("method %s: invalid retryable code %q", method.Desc.FullName(), name)
+		}
+		retry.RetryableCodes = append(retry.RetryableCodes, code.String())
 	}
+	return retry, nil
 }
 
 // getModuleName determines the WASM module name from package and configuration.
@@ -0,0 +1,154 @@
+// Copyright 2025 Sri Panyam
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package builders
+
+import (
+	"testing"
+
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/pluginpb"
+
+	"github.com/panyam/protoc-gen-go-wasmjs/pkg/core"
+	"github.com/panyam/protoc-gen-go-wasmjs/pkg/filters"
+	wasmjsv1 "github.com/panyam/protoc-gen-go-wasmjs/proto/gen/go/wasmjs/v1"
+)
+
+// newJSONAnnotatedFile creates a proto file with one service whose service and method
+// carry the given JSON annotations (nil for none).
+func newJSONAnnotatedFile(t *testing.T, serviceJSON, methodJSON *wasmjsv1.JsonOptions) (*protogen.Plugin, *protogen.File) {
+	t.Helper()
+
+	serviceOptions := &descriptorpb.ServiceOptions{}
+	if serviceJSON != nil {
+		proto.SetExtension(serviceOptions, wasmjsv1.E_ServiceJson, serviceJSON)
+	}
+	methodOptions := &descriptorpb.MethodOptions{}
+	if methodJSON != nil {
+		proto.SetExtension(methodOptions, wasmjsv1.E_MethodJson, methodJSON)
+	}
+
+	file := &descriptorpb.FileDescriptorProto{
+		Name:        proto.String("test/v1/test.proto"),
+		Package:     proto.String("test.v1"),
+		Syntax:      proto.String("proto3"),
+		Options:     &descriptorpb.FileOptions{GoPackage: proto.String("example.com/test/v1;testv1")},
+		MessageType: []*descriptorpb.DescriptorProto{{Name: proto.String("Request")}},
+		Service: []*descriptorpb.ServiceDescriptorProto{{
+			Name:    proto.String("TestService"),
+			Options: serviceOptions,
+			Method: []*descriptorpb.MethodDescriptorProto{{
+				Name:       proto.String("Get"),
+				InputType:  proto.String(".test.v1.Request"),
+				OutputType: proto.String(".test.v1.Request"),
+				Options:    methodOptions,
+			}},
+		}},
+	}
+
+	plugin, err := protogen.Options{}.New(&pluginpb.CodeGeneratorRequest{
+		FileToGenerate: []string{file.GetName()},
+		ProtoFile:      []*descriptorpb.FileDescriptorProto{file},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create plugin: %v", err)
+	}
+	return plugin, plugin.Files[0]
+}
+
+// TestGoDataBuilder_JSONAnnotations tests that JSON annotations override the plugin options.
+func TestGoDataBuilder_JSONAnnotations(t *testing.T) {
+	tests := []struct {
+		name        string
+		serviceJSON *wasmjsv1.JsonOptions
+		methodJSON  *wasmjsv1.JsonOptions
+		want        JSONOptions
+	}{
+		{"no annotations", nil, nil, JSONOptions{EmitUnpopulated: true}},
+		{"method overrides service",
+			&wasmjsv1.JsonOptions{UseEnumNumbers: proto.Bool(true), AllowPartial: proto.Bool(true)},
+			&wasmjsv1.JsonOptions{AllowPartial: proto.Bool(false), EmitUnpopulated: proto.Bool(false)},
+			JSONOptions{UseEnumNumbers: true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plugin, file := newJSONAnnotatedFile(t, tt.serviceJSON, tt.methodJSON)
+			analyzer := core.NewProtoAnalyzer()
+			builder := NewGoDataBuilder(analyzer, core.NewPathCalculator(), core.NewNameConverter(),
+				filters.NewServiceFilter(analyzer), filters.NewMethodFilter(analyzer),
+				filters.NewMessageCollector(analyzer), filters.NewEnumCollector(analyzer))
+			config := &GenerationConfig{JSON: JSONOptions{EmitUnpopulated: true}}
+			context := NewBuildContext(plugin, config, &PackageInfo{Name: "test.v1", Path: "test/v1"})
+
+			service, err := builder.buildServiceData(file.Services[0], file, filters.ServiceFilterResult{}, filters.NewFilterCriteria(), context)
+			if err != nil {
+				t.Fatalf("buildServiceData() error = %v", err)
+			}
+			if got := service.Methods[0].JSON; got != tt.want {
+				t.Errorf("method JSON options = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestGoDataBuilder_RetryPolicy tests that method_retry annotations carry over to the
+// browser client's retry policy, including the per-attempt timeout.
+func TestGoDataBuilder_RetryPolicy(t *testing.T) {
+	methodOptions := &descriptorpb.MethodOptions{}
+	proto.SetExtension(methodOptions, wasmjsv1.E_MethodRetry, &wasmjsv1.RetryPolicy{
+		MaxAttempts:      3,
+		RetryableCodes:   []string{"UNAVAILABLE"},
+		AttemptTimeoutMs: 2500,
+	})
+	file := &descriptorpb.FileDescriptorProto{
+		Name:        proto.String("test/v1/test.proto"),
+		Package:     proto.String("test.v1"),
+		Syntax:      proto.String("proto3"),
+		Options:     &descriptorpb.FileOptions{GoPackage: proto.String("example.com/test/v1;testv1")},
+		MessageType: []*descriptorpb.DescriptorProto{{Name: proto.String("Request")}},
+		Service: []*descriptorpb.ServiceDescriptorProto{{
+			Name: proto.String("BrowserAPI"),
+			Method: []*descriptorpb.MethodDescriptorProto{{
+				Name:       proto.String("Fetch"),
+				InputType:  proto.String(".test.v1.Request"),
+				OutputType: proto.String(".test.v1.Request"),
+				Options:    methodOptions,
+			}},
+		}},
+	}
+	plugin, err := protogen.Options{}.New(&pluginpb.CodeGeneratorRequest{
+		FileToGenerate: []string{file.GetName()},
+		ProtoFile:      []*descriptorpb.FileDescriptorProto{file},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create plugin: %v", err)
+	}
+
+	analyzer := core.NewProtoAnalyzer()
+	builder := NewGoDataBuilder(analyzer, core.NewPathCalculator(), core.NewNameConverter(),
+		filters.NewServiceFilter(analyzer), filters.NewMethodFilter(analyzer),
+		filters.NewMessageCollector(analyzer), filters.NewEnumCollector(analyzer))
+	retry, err := builder.methodRetryPolicy(plugin.Files[0].Services[0].Methods[0])
+	if err != nil {
+		t.Fatalf("methodRetryPolicy() error = %v", err)
+	}
+	want := RetryPolicyData{MaxAttempts: 3, RetryableCodes: []string{"Unavailable"}, AttemptTimeoutMillis: 2500}
+	if retry == nil || retry.MaxAttempts != want.MaxAttempts || retry.AttemptTimeoutMillis != want.AttemptTimeoutMillis ||
+		len(retry.RetryableCodes) != 1 || retry.RetryableCodes[0] != want.RetryableCodes[0] {
+		t.Errorf("retry policy = %+v, want %+v", retry, want)
+	}
+}
@@ -18,6 +18,33 @@ import (
 	"google.golang.org/protobuf/compiler/protogen"
 )
 
+// Default timeouts of exported methods without timeout annotations. Client and
+// bidirectional streams have none: they stay open as long as JavaScript keeps sending.
+const (
+	DefaultMethodTimeoutMillis    = 10000 // Unary and async methods
+	DefaultStreamingTimeoutMillis = 30000 // Server streaming methods
+)
+
+// JSONOptions controls how the json wire format converts messages, mirroring protojson's
+// MarshalOptions (first three fields) and UnmarshalOptions (last two).
+type JSONOptions struct {
+	UseProtoNames   bool // Use proto field names (snake_case) instead of lowerCamelCase JSON names
+	EmitUnpopulated bool // Emit fields holding zero values instead of omitting them
+	UseEnumNumbers  bool // Emit enum values as numbers instead of their names
+	DiscardUnknown  bool // Ignore unknown request fields instead of failing
+	AllowPartial    bool // Accept messages with missing required fields
+}
+
+// DefaultJSONOptions returns the JSON options used by unary and streaming methods alike
+// when neither plugin options nor annotations say otherwise.
+func DefaultJSONOptions() JSONOptions {
+	return JSONOptions{
+		EmitUnpopulated: true, // Avoid undefined fields in JavaScript
+		DiscardUnknown:  true,
+		AllowPartial:    true,
+	}
+}
+
 // GenerationConfig holds configuration options common to both Go and TypeScript generators.
 // This represents the subset of configuration that affects template data building.
 type GenerationConfig struct {
@@ -29,15 +56,26 @@ type GenerationConfig struct {
 	JSStructure string // namespaced|flat|service_based
 	JSNamespace string // Global JavaScript namespace
 	ModuleName  string // WASM module name
+	JSTarget    string // browser|node (where the generated bundle loads the WASM module)
+
+	// Wire format between TypeScript clients and WASM exports
+	WireFormat string // json|binary (binary passes protobuf bytes as Uint8Array)
+
+	// JSON encoding defaults for the json wire format (overridable per service/method)
+	JSON JSONOptions
 
 	// Build integration
 	WasmPackageSuffix   string // Package suffix for WASM wrapper
 	GenerateBuildScript bool   // Whether to generate build scripts
+	Target              string // go|tinygo (the compiler the WASM module is built with)
+	HostBrowserClients  bool   // Whether browser clients build outside js/wasm (against wasm.FakeBrowserChannel)
+	HostExports         bool   // Whether to generate a host-buildable dispatcher of the exported methods
 	
 	// TypeScript generation control
 	GenerateClients   bool // Whether to generate TypeScript clients
 	GenerateTypes     bool // Whether to generate TypeScript types
 	GenerateFactories bool // Whether to generate TypeScript factory classes
+	GenerateWorker    bool // Whether to generate a Web Worker entry point hosting the WASM module
 }
 
 // ImportInfo represents a Go package import with alias for template generation.
@@ -97,6 +135,7 @@ type MethodData struct {
 	GoFuncName     string // Go function name for WASM wrapper (e.g., "libraryServiceFindBooks")
 	ShouldGenerate bool   // Whether to generate this method based on filters
 	Comment        string // Method comment from protobuf
+	FullMethod     string // gRPC full method name (e.g., "/library.v1.LibraryService/FindBooks")
 
 	// Method types (Go)
 	RequestType  string // Fully qualified Go request type (e.g., "libraryv1.FindBooksRequest")
@@ -106,9 +145,34 @@ type MethodData struct {
 	RequestTSType  string // TypeScript request type name (e.g., "FindBooksRequest")
 	ResponseTSType string // TypeScript response type name (e.g., "FindBooksResponse")
 
+	// Method types (proto)
+	RequestProtoType  string // Fully qualified proto request type (e.g., "library.v1.FindBooksRequest")
+	ResponseProtoType string // Fully qualified proto response type (e.g., "library.v1.FindBooksResponse")
+
 	// Method behavior
 	IsAsync           bool // Whether method requires async/callback handling
 	IsServerStreaming bool // Whether method uses server-side streaming
+	IsClientStreaming bool // Whether method uses client-side streaming (bidirectional when IsServerStreaming is also set)
+
+	// Call context
+	TimeoutMillis uint32 // Default timeout of the exported method's context in milliseconds (0 = no timeout)
+
+	// JSON encoding of requests and responses with the json wire format
+	JSON JSONOptions // Plugin options overridden by service_json and method_json annotations
+
+	// Retries of calls to browser-provided methods
+	Retry *RetryPolicyData // Set by method_retry or service_retry annotations (nil = no retries)
+}
+
+// RetryPolicyData is the retry policy of a browser-provided method. Zero fields
+// leave the runtime defaults in place.
+type RetryPolicyData struct {
+	MaxAttempts          uint32   // Total number of attempts including the first
+	InitialBackoffMillis uint32   // Delay before the first retry
+	MaxBackoffMillis     uint32   // Maximum delay between attempts
+	BackoffMultiplier    float64  // Factor the delay grows by after every retry
+	RetryableCodes       []string // Names of grpc/codes constants (e.g., "Unavailable")
+	AttemptTimeoutMillis uint32   // Time each attempt waits for JavaScript
 }
 
 // PackageInfo represents metadata about a protobuf package for generation.
@@ -74,6 +74,8 @@ type TSTemplateData struct {
 	// Client generation specific
 	APIStructure string              // API structure (namespaced|flat|service_based)
 	JSNamespace  string              // JavaScript namespace
+	WireFormat   string              // Wire format between clients and WASM exports (json|binary)
+	JSTarget     string              // Where the bundle loads the WASM module (browser|node)
 	Dependencies []FactoryDependency // Factory dependencies for cross-package refs
 }
 
@@ -124,6 +126,13 @@ type TSFieldInfo struct {
 	MessagePackage string // Package where the message type is defined (e.g., "utils.v1"), extracted from descriptor
 	IsNestedType   bool   // Whether the message type is a nested message
 	Comment        string // Field comment
+
+	// Wire-level details used by the binary codec
+	ProtoKind           string // Proto kind for scalar and enum fields (e.g., "int32", "sint64", "enum")
+	IsMap               bool   // Whether this is a map field
+	MapKeyKind          string // Proto kind of map keys
+	MapValueKind        string // Proto kind of map values ("message" for message values)
+	MapValueMessageType string // Fully qualified message type of map values, if they are messages
 }
 
 // TSEnumInfo extends basic enum info with TypeScript-specific fields
@@ -245,6 +254,7 @@ func (tb *TSDataBuilder) BuildServiceClientData(
 		ImportGroups: importGroups,
 		APIStructure: config.JSStructure,
 		JSNamespace:  tb.getJSNamespace(packageInfo.Name, config),
+		WireFormat:   config.WireFormat,
 	}, nil
 }
 
@@ -464,7 +474,7 @@ func (tb *TSDataBuilder) BuildTypeData(
 	}
 
 	// Transform to TypeScript-specific structures
-	tsMessages := tb.transformMessages(messageResult.Items, packageInfo.Files)
+	tsMessages := tb.transformMessages(messageResult.Items, packageInfo.Files, config)
 	tsEnums := tb.transformEnums(enumResult.Items)
 
 	// Build external imports for cross-package references
@@ -654,10 +664,14 @@ func (tb *TSDataBuilder) buildMethodDataForTS(
 		JSName:            jsName,
 		ShouldGenerate:    true, // Method passed filtering, should be generated
 		Comment:           strings.TrimSpace(string(method.Comments.Leading)),
+		FullMethod:        fmt.Sprintf("/%s/%s", method.Parent.Desc.FullName(), method.Desc.Name()),
 		RequestTSType:     string(method.Input.GoIdent.GoName),
 		ResponseTSType:    string(method.Output.GoIdent.GoName),
+		RequestProtoType:  string(method.Input.Desc.FullName()),
+		ResponseProtoType: string(method.Output.Desc.FullName()),
 		IsAsync:           methodResult.IsAsync,
 		IsServerStreaming: methodResult.IsServerStreaming,
+		IsClientStreaming: methodResult.IsClientStreaming,
 	}
 }
 
@@ -842,8 +856,8 @@ func (tb *TSDataBuilder) getJSNamespace(packageName string, config *GenerationCo
 	if config.JSNamespace != "" {
 		return config.JSNamespace
 	}
-	// Default to package name with underscores
-	return strings.ReplaceAll(packageName, ".", "_")
+	// Default to the namespace the Go generator registers the package under
+	return tb.nameConv.ToJSNamespace(packageName)
 }
 
 // getPrimarySourcePath gets the primary source file path from the package files.
@@ -855,7 +869,8 @@ func (tb *TSDataBuilder) getPrimarySourcePath(files []*protogen.File) string {
 }
 
 // transformMessages converts basic MessageInfo to TypeScript-enriched structures.
-func (tb *TSDataBuilder) transformMessages(messages []filters.MessageInfo, protoFiles []*protogen.File) []TSMessageInfo {
+// Field names follow the JSON encoding configured by the json_use_proto_names plugin option.
+func (tb *TSDataBuilder) transformMessages(messages []filters.MessageInfo, protoFiles []*protogen.File, config *GenerationConfig) []TSMessageInfo {
 	result := make([]TSMessageInfo, 0, len(messages))
 
 	// Create a map for quick lookup of protogen.Message by fully qualified name
@@ -881,7 +896,7 @@ func (tb *TSDataBuilder) transformMessages(messages []filters.MessageInfo, proto
 			ProtoFile:          msg.ProtoFile,
 			Comment:            msg.Comment,
 			MethodName:         "new" + tsName, // Factory method name uses flattened name
-			Fields:             tb.extractFieldInfo(protoMessage),
+			Fields:             tb.extractFieldInfo(protoMessage, config.JSON.UseProtoNames),
 			IsNested:           msg.IsNested,
 			IsMapEntry:         msg.IsMapEntry,
 			OneofGroups:        tb.extractOneofGroups(protoMessage),
@@ -926,8 +941,10 @@ func (tb *TSDataBuilder) addNestedMessages(messageMap map[string]*protogen.Messa
 	}
 }
 
-// extractFieldInfo extracts field information from a protogen.Message
-func (tb *TSDataBuilder) extractFieldInfo(protoMessage *protogen.Message) []TSFieldInfo {
+// extractFieldInfo extracts field information from a protogen.Message.
+// With useProtoNames, TypeScript field names are the proto names (snake_case) instead of
+// JSON names, matching responses encoded with UseProtoNames.
+func (tb *TSDataBuilder) extractFieldInfo(protoMessage *protogen.Message, useProtoNames bool) []TSFieldInfo {
 	if protoMessage == nil {
 		return []TSFieldInfo{}
 	}
@@ -946,6 +963,10 @@ func (tb *TSDataBuilder) extractFieldInfo(protoMessage *protogen.Message) []TSFi
 			Comment:      strings.TrimSpace(string(field.Comments.Leading)),
 		}
 		
+		if useProtoNames {
+			fieldInfo.TSName = fieldInfo.Name
+		}
+
 		// Handle oneof fields
 		if field.Oneof != nil {
 			fieldInfo.IsOneof = true
@@ -954,6 +975,9 @@ func (tb *TSDataBuilder) extractFieldInfo(protoMessage *protogen.Message) []TSFi
 		
 		// Determine field type and TypeScript type
 		kind := field.Desc.Kind()
+		if kind != protoreflect.MessageKind && kind != protoreflect.GroupKind {
+			fieldInfo.ProtoKind = kind.String()
+		}
 		switch kind.String() {
 		case "string":
 			fieldInfo.TSType = "string"
@@ -979,10 +1003,15 @@ func (tb *TSDataBuilder) extractFieldInfo(protoMessage *protogen.Message) []TSFi
 						
 						keyType := tb.protoKindToTSType(keyField.Desc.Kind())
 						valueType := tb.protoKindToTSType(valueField.Desc.Kind())
+
+						fieldInfo.IsMap = true
+						fieldInfo.MapKeyKind = keyField.Desc.Kind().String()
+						fieldInfo.MapValueKind = valueField.Desc.Kind().String()
 						
 						// Handle message value types
 						if valueField.Desc.Kind().String() == "message" && valueField.Message != nil {
 							valueType = string(valueField.Message.Desc.Name())
+							fieldInfo.MapValueMessageType = string(valueField.Message.Desc.FullName())
 						}
 						
 						fieldInfo.TSType = fmt.Sprintf("Record<%s, %s>", keyType, valueType)
@@ -1143,7 +1172,7 @@ func (tb *TSDataBuilder) BuildFactoryData(
 	config *GenerationConfig,
 ) (*TSTemplateData, error) {
 	// Transform imported messages to TypeScript structures
-	tsMessages := tb.transformMessages(importedMessages, packageInfo.Files)
+	tsMessages := tb.transformMessages(importedMessages, packageInfo.Files, config)
 
 	// Get the factory file's output directory
 	factoryFilePath := string(factoryFile.Desc.Path())
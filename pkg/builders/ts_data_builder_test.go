@@ -19,6 +19,10 @@ import (
 
 	"github.com/panyam/protoc-gen-go-wasmjs/pkg/core"
 	"github.com/panyam/protoc-gen-go-wasmjs/pkg/filters"
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/pluginpb"
 )
 
 func TestExtractPackageFromTypeName(t *testing.T) {
@@ -198,3 +202,61 @@ func TestCalculateCrossPackageImportPath(t *testing.T) {
 		})
 	}
 }
+
+// TestExtractFieldInfo_FieldNames tests that TypeScript field names follow the JSON
+// encoding: lowerCamelCase JSON names by default, proto names with json_use_proto_names.
+func TestExtractFieldInfo_FieldNames(t *testing.T) {
+	analyzer := core.NewProtoAnalyzer()
+	builder := NewTSDataBuilder(
+		analyzer,
+		core.NewPathCalculator(),
+		core.NewNameConverter(),
+		filters.NewServiceFilter(analyzer),
+		filters.NewMethodFilter(analyzer),
+		filters.NewMessageCollector(analyzer),
+		filters.NewEnumCollector(analyzer),
+	)
+
+	file := &descriptorpb.FileDescriptorProto{
+		Name:    proto.String("test/v1/test.proto"),
+		Package: proto.String("test.v1"),
+		Syntax:  proto.String("proto3"),
+		Options: &descriptorpb.FileOptions{GoPackage: proto.String("example.com/test/v1;testv1")},
+		MessageType: []*descriptorpb.DescriptorProto{{
+			Name: proto.String("Book"),
+			Field: []*descriptorpb.FieldDescriptorProto{{
+				Name:     proto.String("author_name"),
+				JsonName: proto.String("authorName"),
+				Number:   proto.Int32(1),
+				Label:    descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+				Type:     descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum(),
+			}},
+		}},
+	}
+	plugin, err := protogen.Options{}.New(&pluginpb.CodeGeneratorRequest{
+		FileToGenerate: []string{file.GetName()},
+		ProtoFile:      []*descriptorpb.FileDescriptorProto{file},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create plugin: %v", err)
+	}
+	message := plugin.Files[0].Messages[0]
+
+	tests := []struct {
+		name          string
+		useProtoNames bool
+		expectedName  string
+	}{
+		{"JSON names by default", false, "authorName"},
+		{"proto names with json_use_proto_names", true, "author_name"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fields := builder.extractFieldInfo(message, tt.useProtoNames)
+			if len(fields) != 1 || fields[0].TSName != tt.expectedName {
+				t.Errorf("extractFieldInfo() field names = %+v, want %s", fields, tt.expectedName)
+			}
+		})
+	}
+}
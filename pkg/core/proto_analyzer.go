@@ -193,6 +193,78 @@ func (pa *ProtoAnalyzer) IsAsyncMethod(method *protogen.Method) bool {
 	return false
 }
 
+// GetMethodTimeout resolves the timeout of a WASM-exported method from wasmjs annotations.
+// A method-level method_timeout takes precedence over the service-level service_timeout.
+// Returns the timeout in milliseconds (0 means no timeout) and whether any annotation applied;
+// when none applies the caller should use its default timeout.
+func (pa *ProtoAnalyzer) GetMethodTimeout(method *protogen.Method) (timeoutMs uint32, ok bool) {
+	if method.Desc.Options() != nil {
+		if timeoutOpts := proto.GetExtension(method.Desc.Options(), wasmjsv1.E_MethodTimeout); timeoutOpts != nil {
+			if timeoutMs, ok := pa.timeoutFromOptions(timeoutOpts); ok {
+				return timeoutMs, true
+			}
+		}
+	}
+	if method.Parent != nil && method.Parent.Desc.Options() != nil {
+		if timeoutOpts := proto.GetExtension(method.Parent.Desc.Options(), wasmjsv1.E_ServiceTimeout); timeoutOpts != nil {
+			if timeoutMs, ok := pa.timeoutFromOptions(timeoutOpts); ok {
+				return timeoutMs, true
+			}
+		}
+	}
+	return 0, false
+}
+
+// timeoutFromOptions extracts the timeout from a TimeoutOptions extension value.
+func (pa *ProtoAnalyzer) timeoutFromOptions(value any) (uint32, bool) {
+	opts, ok := value.(*wasmjsv1.TimeoutOptions)
+	if !ok || opts == nil {
+		return 0, false
+	}
+	if opts.GetNoTimeout() {
+		return 0, true
+	}
+	if opts.GetTimeoutMs() > 0 {
+		return opts.GetTimeoutMs(), true
+	}
+	return 0, false
+}
+
+// GetMethodJSONOptions resolves the JSON encoding options of a WASM-exported method from
+// wasmjs annotations. Fields set by method_json override those set by service_json.
+// Fields set by neither annotation are left unset so the caller can apply its defaults.
+func (pa *ProtoAnalyzer) GetMethodJSONOptions(method *protogen.Method) *wasmjsv1.JsonOptions {
+	resolved := &wasmjsv1.JsonOptions{}
+	if method.Parent != nil && method.Parent.Desc.Options() != nil {
+		if jsonOpts, ok := proto.GetExtension(method.Parent.Desc.Options(), wasmjsv1.E_ServiceJson).(*wasmjsv1.JsonOptions); ok && jsonOpts != nil {
+			proto.Merge(resolved, jsonOpts)
+		}
+	}
+	if method.Desc.Options() != nil {
+		if jsonOpts, ok := proto.GetExtension(method.Desc.Options(), wasmjsv1.E_MethodJson).(*wasmjsv1.JsonOptions); ok && jsonOpts != nil {
+			proto.Merge(resolved, jsonOpts)
+		}
+	}
+	return resolved
+}
+
+// GetMethodRetryPolicy resolves the retry policy of a browser-provided method from wasmjs
+// annotations. A method-level method_retry replaces the service-level service_retry.
+// Returns nil when neither annotation is set.
+func (pa *ProtoAnalyzer) GetMethodRetryPolicy(method *protogen.Method) *wasmjsv1.RetryPolicy {
+	if method.Desc.Options() != nil {
+		if retry, ok := proto.GetExtension(method.Desc.Options(), wasmjsv1.E_MethodRetry).(*wasmjsv1.RetryPolicy); ok && retry != nil {
+			return retry
+		}
+	}
+	if method.Parent != nil && method.Parent.Desc.Options() != nil {
+		if retry, ok := proto.GetExtension(method.Parent.Desc.Options(), wasmjsv1.E_ServiceRetry).(*wasmjsv1.RetryPolicy); ok && retry != nil {
+			return retry
+		}
+	}
+	return nil
+}
+
 // IsMethodExcluded checks if a method is marked for exclusion from WASM generation.
 // Excluded methods won't appear in the generated JavaScript API.
 func (pa *ProtoAnalyzer) IsMethodExcluded(method *protogen.Method) bool {
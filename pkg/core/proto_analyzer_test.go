@@ -15,7 +15,15 @@
 package core
 
 import (
+	"fmt"
 	"testing"
+
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/pluginpb"
+
+	wasmjsv1 "github.com/panyam/protoc-gen-go-wasmjs/proto/gen/go/wasmjs/v1"
 )
 
 // TestProtoAnalyzer_ExtractPackageName tests the extraction of package names from
@@ -256,3 +264,162 @@ func TestProtoAnalyzer_IsBrowserProvidedService(t *testing.T) {
 // - GetOneofGroups: Essential for proper oneof handling in TypeScript
 // - IsNestedMessage: Affects import generation and type references
 */
+
+// newTestService builds a protogen service with one method per entry in methodOptions,
+// named Method0, Method1, ... so annotation lookups can be tested without protoc.
+func newTestService(t *testing.T, serviceOptions *descriptorpb.ServiceOptions, methodOptions ...*descriptorpb.MethodOptions) *protogen.Service {
+	t.Helper()
+
+	service := &descriptorpb.ServiceDescriptorProto{
+		Name:    proto.String("TestService"),
+		Options: serviceOptions,
+	}
+	for i, opts := range methodOptions {
+		service.Method = append(service.Method, &descriptorpb.MethodDescriptorProto{
+			Name:       proto.String(fmt.Sprintf("Method%d", i)),
+			InputType:  proto.String(".test.v1.Request"),
+			OutputType: proto.String(".test.v1.Request"),
+			Options:    opts,
+		})
+	}
+
+	file := &descriptorpb.FileDescriptorProto{
+		Name:        proto.String("test/v1/test.proto"),
+		Package:     proto.String("test.v1"),
+		Syntax:      proto.String("proto3"),
+		Options:     &descriptorpb.FileOptions{GoPackage: proto.String("example.com/test/v1;testv1")},
+		MessageType: []*descriptorpb.DescriptorProto{{Name: proto.String("Request")}},
+		Service:     []*descriptorpb.ServiceDescriptorProto{service},
+	}
+
+	plugin, err := protogen.Options{}.New(&pluginpb.CodeGeneratorRequest{
+		FileToGenerate: []string{file.GetName()},
+		ProtoFile:      []*descriptorpb.FileDescriptorProto{file},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create plugin: %v", err)
+	}
+	return plugin.Files[0].Services[0]
+}
+
+// TestProtoAnalyzer_GetMethodTimeout tests timeout resolution from method_timeout
+// and service_timeout annotations. Generated exports use this instead of fixed timeouts,
+// so long-running methods are not killed early.
+func TestProtoAnalyzer_GetMethodTimeout(t *testing.T) {
+	analyzer := NewProtoAnalyzer()
+
+	methodTimeout := func(opts *wasmjsv1.TimeoutOptions) *descriptorpb.MethodOptions {
+		methodOptions := &descriptorpb.MethodOptions{}
+		proto.SetExtension(methodOptions, wasmjsv1.E_MethodTimeout, opts)
+		return methodOptions
+	}
+	serviceOptions := &descriptorpb.ServiceOptions{}
+	proto.SetExtension(serviceOptions, wasmjsv1.E_ServiceTimeout, &wasmjsv1.TimeoutOptions{TimeoutMs: 60000})
+
+	tests := []struct {
+		name            string
+		serviceOptions  *descriptorpb.ServiceOptions
+		methodOptions   *descriptorpb.MethodOptions
+		expectedTimeout uint32
+		expectedOk      bool
+	}{
+		{"no annotations", nil, nil, 0, false},
+		{"method timeout", nil, methodTimeout(&wasmjsv1.TimeoutOptions{TimeoutMs: 120000}), 120000, true},
+		{"method no_timeout", nil, methodTimeout(&wasmjsv1.TimeoutOptions{NoTimeout: true}), 0, true},
+		{"service timeout applies to methods", serviceOptions, nil, 60000, true},
+		{"method timeout overrides service timeout", serviceOptions, methodTimeout(&wasmjsv1.TimeoutOptions{TimeoutMs: 500}), 500, true},
+		{"method no_timeout overrides service timeout", serviceOptions, methodTimeout(&wasmjsv1.TimeoutOptions{NoTimeout: true}), 0, true},
+		{"empty method timeout falls back to service", serviceOptions, methodTimeout(&wasmjsv1.TimeoutOptions{}), 60000, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := newTestService(t, tt.serviceOptions, tt.methodOptions)
+			timeoutMs, ok := analyzer.GetMethodTimeout(service.Methods[0])
+
+			if ok != tt.expectedOk || timeoutMs != tt.expectedTimeout {
+				t.Errorf("GetMethodTimeout() = (%d, %v), expected (%d, %v)", timeoutMs, ok, tt.expectedTimeout, tt.expectedOk)
+			}
+		})
+	}
+}
+
+// TestProtoAnalyzer_GetMethodJSONOptions tests JSON option resolution from method_json
+// and service_json annotations, field by field, leaving unannotated fields unset.
+func TestProtoAnalyzer_GetMethodJSONOptions(t *testing.T) {
+	analyzer := NewProtoAnalyzer()
+
+	methodJSON := func(opts *wasmjsv1.JsonOptions) *descriptorpb.MethodOptions {
+		methodOptions := &descriptorpb.MethodOptions{}
+		proto.SetExtension(methodOptions, wasmjsv1.E_MethodJson, opts)
+		return methodOptions
+	}
+	serviceOptions := &descriptorpb.ServiceOptions{}
+	proto.SetExtension(serviceOptions, wasmjsv1.E_ServiceJson, &wasmjsv1.JsonOptions{
+		EmitUnpopulated: proto.Bool(true),
+		UseEnumNumbers:  proto.Bool(true),
+	})
+
+	tests := []struct {
+		name           string
+		serviceOptions *descriptorpb.ServiceOptions
+		methodOptions  *descriptorpb.MethodOptions
+		expected       *wasmjsv1.JsonOptions
+	}{
+		{"no annotations", nil, nil, &wasmjsv1.JsonOptions{}},
+		{"method options", nil, methodJSON(&wasmjsv1.JsonOptions{EmitUnpopulated: proto.Bool(false)}),
+			&wasmjsv1.JsonOptions{EmitUnpopulated: proto.Bool(false)}},
+		{"service options apply to methods", serviceOptions, nil,
+			&wasmjsv1.JsonOptions{EmitUnpopulated: proto.Bool(true), UseEnumNumbers: proto.Bool(true)}},
+		{"method fields override service fields", serviceOptions, methodJSON(&wasmjsv1.JsonOptions{UseEnumNumbers: proto.Bool(false), AllowPartial: proto.Bool(false)}),
+			&wasmjsv1.JsonOptions{EmitUnpopulated: proto.Bool(true), UseEnumNumbers: proto.Bool(false), AllowPartial: proto.Bool(false)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := newTestService(t, tt.serviceOptions, tt.methodOptions)
+			resolved := analyzer.GetMethodJSONOptions(service.Methods[0])
+
+			if !proto.Equal(resolved, tt.expected) {
+				t.Errorf("GetMethodJSONOptions() = %v, expected %v", resolved, tt.expected)
+			}
+		})
+	}
+}
+
+// TestProtoAnalyzer_GetMethodRetryPolicy tests retry policy resolution from method_retry
+// and service_retry annotations, where the method policy replaces the service policy.
+func TestProtoAnalyzer_GetMethodRetryPolicy(t *testing.T) {
+	analyzer := NewProtoAnalyzer()
+
+	servicePolicy := &wasmjsv1.RetryPolicy{MaxAttempts: 3}
+	methodPolicy := &wasmjsv1.RetryPolicy{MaxAttempts: 5, RetryableCodes: []string{"UNAVAILABLE"}}
+
+	serviceOptions := &descriptorpb.ServiceOptions{}
+	proto.SetExtension(serviceOptions, wasmjsv1.E_ServiceRetry, servicePolicy)
+	methodOptions := &descriptorpb.MethodOptions{}
+	proto.SetExtension(methodOptions, wasmjsv1.E_MethodRetry, methodPolicy)
+
+	tests := []struct {
+		name           string
+		serviceOptions *descriptorpb.ServiceOptions
+		methodOptions  *descriptorpb.MethodOptions
+		expected       *wasmjsv1.RetryPolicy
+	}{
+		{"no annotations", nil, nil, nil},
+		{"method policy", nil, methodOptions, methodPolicy},
+		{"service policy applies to methods", serviceOptions, nil, servicePolicy},
+		{"method policy replaces service policy", serviceOptions, methodOptions, methodPolicy},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := newTestService(t, tt.serviceOptions, tt.methodOptions)
+			resolved := analyzer.GetMethodRetryPolicy(service.Methods[0])
+
+			if !proto.Equal(resolved, tt.expected) {
+				t.Errorf("GetMethodRetryPolicy() = %v, expected %v", resolved, tt.expected)
+			}
+		})
+	}
+}
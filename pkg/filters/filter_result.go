@@ -53,6 +53,7 @@ type MethodFilterResult struct {
 	CustomJSName      string // Custom JavaScript method name (if any)
 	IsAsync           bool   // Whether method requires async/callback handling
 	IsServerStreaming bool   // Whether method uses server-side streaming
+	IsClientStreaming bool   // Whether method uses client-side streaming (bidirectional when both are set)
 }
 
 // PackageFilterResult contains the result of package filtering.
@@ -40,7 +40,7 @@ func NewMethodFilter(analyzer *core.ProtoAnalyzer) *MethodFilter {
 // ShouldIncludeMethod determines if a method should be included in generation.
 // This applies filtering criteria in order of precedence:
 // 1. Annotation-based exclusion (highest priority)
-// 2. Streaming method limitations (client streaming not supported)
+// 2. Streaming method limitations (client streaming not supported for browser-provided services)
 // 3. Explicit exclude patterns
 // 4. Explicit include patterns (if configured)
 // 5. Default inclusion
@@ -56,10 +56,10 @@ func (mf *MethodFilter) ShouldIncludeMethod(method *protogen.Method, criteria *F
 		}
 	}
 
-	// Check streaming limitations - client streaming not supported
-	if method.Desc.IsStreamingClient() {
+	// Check streaming limitations - browser-provided services can't receive client streams
+	if method.Desc.IsStreamingClient() && mf.analyzer.IsBrowserProvidedService(method.Parent) {
 		return MethodFilterResult{
-			FilterResult: Excluded("client streaming methods not supported"),
+			FilterResult: Excluded("client streaming methods not supported for browser-provided services"),
 		}
 	}
 
@@ -67,6 +67,7 @@ func (mf *MethodFilter) ShouldIncludeMethod(method *protogen.Method, criteria *F
 	customJSName := mf.analyzer.GetCustomMethodName(method)
 	isAsync := mf.analyzer.IsAsyncMethod(method)
 	isServerStreaming := method.Desc.IsStreamingServer()
+	isClientStreaming := method.Desc.IsStreamingClient()
 
 	// Apply exclude patterns
 	if criteria.HasMethodExcludes() {
@@ -88,6 +89,7 @@ func (mf *MethodFilter) ShouldIncludeMethod(method *protogen.Method, criteria *F
 					CustomJSName:      customJSName,
 					IsAsync:           isAsync,
 					IsServerStreaming: isServerStreaming,
+					IsClientStreaming: isClientStreaming,
 				}
 			}
 		}
@@ -103,6 +105,7 @@ func (mf *MethodFilter) ShouldIncludeMethod(method *protogen.Method, criteria *F
 		CustomJSName:      customJSName,
 		IsAsync:           isAsync,
 		IsServerStreaming: isServerStreaming,
+		IsClientStreaming: isClientStreaming,
 	}
 }
 
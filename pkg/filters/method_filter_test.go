@@ -206,7 +206,8 @@ tests that use actual protogen objects. Here's what would be tested and why:
 //
 // Test cases would include:
 // - Methods with wasm_method_exclude annotation (should be excluded)
-// - Client streaming methods (should be excluded - not supported)
+// - Client streaming methods of browser-provided services (should be excluded - not supported)
+// - Client and bidirectional streaming methods (should be included with client streaming metadata)
 // - Methods matching exclude patterns (should be excluded)
 // - Methods matching include patterns when includes are configured (should be included)
 // - Methods not matching include patterns when includes are configured (should be excluded)
@@ -250,7 +251,7 @@ func TestMethodFilter_FilterMethods(t *testing.T) {
 // - Service with at least one included method (should return true)
 // - Service with all methods excluded (should return false)
 // - Empty service (should return false)
-// - Service with only client streaming methods (should return false)
+// - Browser-provided service with only client streaming methods (should return false)
 //
 // Why important: Prevents generation of empty service wrappers when all
 // methods are filtered out, reducing generated code size and complexity.
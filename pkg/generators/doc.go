@@ -176,11 +176,14 @@ Generators accept GenerationConfig with these key options:
   - TSExportPath/WasmExportPath: Output directories
   - JSStructure: API structure (namespaced|flat|service_based)
   - JSNamespace: Global JavaScript namespace
+  - JSTarget: Where the WASM module is loaded (browser|node)
   - ModuleName: Module name for generated code
   - GenerateClients: Generate service clients (TS only)
   - GenerateTypes: Generate interfaces/models (TS only)
   - GenerateFactories: Generate factory classes (TS only)
+  - GenerateWorker: Generate a Web Worker entry point (TS only)
   - GenerateBuildScript: Generate build scripts (Go only)
+  - Target: Compiler the WASM module is built with (go|tinygo, Go only)
 
 # Filtering
 
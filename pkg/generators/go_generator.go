@@ -185,6 +185,14 @@ func (gg *GoGenerator) renderFilesDirectly(filePlan *builders.FilePlan, data *bu
 			}
 			log.Printf("BROWSER_CLIENTS: Browser clients rendered successfully")
 
+		case "dispatcher":
+			log.Printf("DISPATCHER: Attempting to render dispatcher...")
+			if err := gg.renderer.RenderDispatcherDirect(generatedFile, data); err != nil {
+				log.Printf("DISPATCHER: ERROR rendering dispatcher: %v", err)
+				return fmt.Errorf("failed to render dispatcher file %s: %w", spec.Filename, err)
+			}
+			log.Printf("DISPATCHER: Dispatcher rendered successfully")
+
 		case "example":
 			log.Printf("MAIN: Attempting to render main file...")
 			if err := gg.renderer.RenderMainExampleDirect(generatedFile, data); err != nil {
@@ -215,7 +223,7 @@ func (gg *GoGenerator) planGoFiles(data *builders.GoTemplateData, config *builde
 	var specs []builders.FileSpec
 
 	// Split WASM generation into 4 files for better modularity:
-	// 1. Service Interfaces - Clean interfaces without gRPC dependencies
+	// 1. Service Interfaces - Interfaces the WASM exports call (gRPC-free for target=tinygo)
 	// 2. Converters - syscall/js converters (createJSResponse) and stream wrappers
 	// 3. Exports - Exports struct, RegisterAPI, method wrappers
 	// 4. Browser clients - Browser service client implementations
@@ -286,6 +294,21 @@ func (gg *GoGenerator) planGoFiles(data *builders.GoTemplateData, config *builde
 		})
 	}
 
+	// Generate the host-buildable dispatcher of the exports (only if requested)
+	if config.HostExports && len(data.Services) > 0 {
+		dispatcherFilename := filepath.Join(packagePath, baseName+"_dispatcher.go")
+		log.Printf("Planning dispatcher file: %s", dispatcherFilename)
+		specs = append(specs, builders.FileSpec{
+			Name:     "dispatcher",
+			Filename: dispatcherFilename,
+			Type:     "dispatcher",
+			Required: false,
+			ContentHints: builders.ContentHints{
+				HasServices: true,
+			},
+		})
+	}
+
 	// Always generate main example (helps users understand integration)
 	if false {
 		mainFilename := gg.calculateMainFilename(data.PackageName, config)
@@ -301,8 +324,8 @@ func (gg *GoGenerator) planGoFiles(data *builders.GoTemplateData, config *builde
 	}
 
 	// Generate build script if enabled
-	if false && config.GenerateBuildScript {
-		buildFilename := gg.calculateBuildScriptFilename(config)
+	if config.GenerateBuildScript {
+		buildFilename := filepath.Join(packagePath, "build.sh")
 		specs = append(specs, builders.FileSpec{
 			Name:     "build",
 			Filename: buildFilename,
@@ -336,11 +359,6 @@ func (gg *GoGenerator) calculateMainFilename(packageName string, config *builder
 	return filepath.Join(packagePath, "main.go.example")
 }
 
-// calculateBuildScriptFilename determines the output filename for the build script.
-func (gg *GoGenerator) calculateBuildScriptFilename(config *builders.GenerationConfig) string {
-	return filepath.Join(config.WasmExportPath, "build.sh")
-}
-
 // calculateOutputPath determines the output directory path for generated files.
 // It uses the go_package path (if available) to avoid collisions when multiple proto files
 // have the same proto package but different go_package options.
@@ -417,5 +435,42 @@ func (gg *GoGenerator) ValidateConfig(config *builders.GenerationConfig) error {
 		return fmt.Errorf("invalid JSStructure: %s (supported: namespaced, flat, service_based)", config.JSStructure)
 	}
 
+	// Set default WireFormat if not specified
+	if config.WireFormat == "" {
+		config.WireFormat = "json" // Default
+	}
+
+	if config.WireFormat != "json" && config.WireFormat != "binary" {
+		return fmt.Errorf("invalid WireFormat: %s (supported: json, binary)", config.WireFormat)
+	}
+
+	// Set default JSTarget if not specified
+	if config.JSTarget == "" {
+		config.JSTarget = "browser" // Default
+	}
+
+	if config.JSTarget != "browser" && config.JSTarget != "node" {
+		return fmt.Errorf("invalid JSTarget: %s (supported: browser, node)", config.JSTarget)
+	}
+
+	// Set default Target if not specified
+	if config.Target == "" {
+		config.Target = "go" // Default
+	}
+
+	if config.Target != "go" && config.Target != "tinygo" {
+		return fmt.Errorf("invalid Target: %s (supported: go, tinygo)", config.Target)
+	}
+
+	// TinyGo builds exchange protobuf bytes, which need no JSON encoding in Go
+	if config.Target == "tinygo" && config.WireFormat != "binary" {
+		return fmt.Errorf("target=tinygo requires wire_format=binary")
+	}
+
+	// The dispatcher runs the exports' gRPC server interceptors, which TinyGo builds leave out
+	if config.Target == "tinygo" && config.HostExports {
+		return fmt.Errorf("host_exports is not supported with target=tinygo")
+	}
+
 	return nil
 }
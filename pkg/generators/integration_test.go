@@ -15,10 +15,19 @@
 package generators
 
 import (
+	"sort"
+	"strings"
 	"testing"
 
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/pluginpb"
+
 	"github.com/panyam/protoc-gen-go-wasmjs/pkg/builders"
 	"github.com/panyam/protoc-gen-go-wasmjs/pkg/filters"
+	wasmjsv1 "github.com/panyam/protoc-gen-go-wasmjs/proto/gen/go/wasmjs/v1"
 )
 
 // TestGoGenerator_Creation tests Go generator initialization.
@@ -160,6 +169,59 @@ func TestGoGenerator_ConfigValidation(t *testing.T) {
 			},
 			expectError: false,
 			reason:      "Missing JS structure should get default value",
+		}, {
+			name: "binary wire format",
+			config: &builders.GenerationConfig{
+				WasmExportPath: "./gen/wasm",
+				JSStructure:    "namespaced",
+				WireFormat:     "binary",
+			},
+			expectError: false,
+			reason:      "Binary wire format should be accepted",
+		},
+		{
+			name: "invalid wire format",
+			config: &builders.GenerationConfig{
+				WasmExportPath: "./gen/wasm",
+				JSStructure:    "namespaced",
+				WireFormat:     "xml", // Invalid
+			},
+			expectError: true,
+			reason:      "Unsupported wire format should be rejected",
+		},
+		{
+			name: "tinygo target with binary wire format",
+			config: &builders.GenerationConfig{
+				WasmExportPath: "./gen/wasm",
+				JSStructure:    "namespaced",
+				WireFormat:     "binary",
+				Target:         "tinygo",
+			},
+			expectError: false,
+			reason:      "TinyGo builds exchange protobuf bytes",
+		},
+		{
+			name: "tinygo target with json wire format",
+			config: &builders.GenerationConfig{
+				WasmExportPath: "./gen/wasm",
+				JSStructure:    "namespaced",
+				WireFormat:     "json",
+				Target:         "tinygo",
+			},
+			expectError: true,
+			reason:      "TinyGo builds need the binary wire format, which needs no JSON encoding in Go",
+		},
+		{
+			name: "tinygo target with host exports",
+			config: &builders.GenerationConfig{
+				WasmExportPath: "./gen/wasm",
+				JSStructure:    "namespaced",
+				WireFormat:     "binary",
+				Target:         "tinygo",
+				HostExports:    true,
+			},
+			expectError: true,
+			reason:      "The dispatcher runs gRPC server interceptors, which TinyGo builds leave out",
 		},
 	}
 
@@ -210,6 +272,23 @@ func TestTSGenerator_ConfigValidation(t *testing.T) {
 			},
 			expectError: true,
 			reason:      "Empty TypeScript export path should be rejected",
+		}, {
+			name: "binary wire format",
+			config: &builders.GenerationConfig{
+				TSExportPath: "./gen/ts",
+				WireFormat:   "binary",
+			},
+			expectError: false,
+			reason:      "Binary wire format should be accepted",
+		},
+		{
+			name: "invalid wire format",
+			config: &builders.GenerationConfig{
+				TSExportPath: "./gen/ts",
+				WireFormat:   "xml", // Invalid
+			},
+			expectError: true,
+			reason:      "Unsupported wire format should be rejected",
 		},
 	}
 
@@ -321,3 +400,258 @@ func TestFilterCriteria_Integration(t *testing.T) {
 		})
 	}
 }
+
+// TestGoGenerator_BuildScript tests that build.sh is generated next to the package's
+// files for each build target, and left out when generate_build_script is false.
+func TestGoGenerator_BuildScript(t *testing.T) {
+	tests := []struct {
+		name        string
+		config      *builders.GenerationConfig
+		contains    []string // Expected in build.sh; nil when it should not be generated
+		notContains []string
+	}{
+		{
+			name: "go target",
+			config: &builders.GenerationConfig{
+				WasmExportPath:      ".",
+				ModuleName:          "echo_wasm",
+				GenerateBuildScript: true,
+			},
+			contains:    []string{`go build -o "$OUT_DIR/echo_wasm.wasm" "$MAIN_PKG"`, "export GOARCH=wasm"},
+			notContains: []string{"tinygo"},
+		},
+		{
+			name: "tinygo target",
+			config: &builders.GenerationConfig{
+				WasmExportPath:      ".",
+				ModuleName:          "echo_wasm",
+				WireFormat:          "binary",
+				Target:              "tinygo",
+				GenerateBuildScript: true,
+			},
+			contains:    []string{`tinygo build -o "$OUT_DIR/echo_wasm.wasm" -target wasm -no-debug "$MAIN_PKG"`, "tinygo env TINYGOROOT"},
+			notContains: []string{"export GOARCH=wasm"},
+		},
+		{
+			name: "disabled",
+			config: &builders.GenerationConfig{
+				WasmExportPath:      ".",
+				ModuleName:          "echo_wasm",
+				GenerateBuildScript: false,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			files := generateGoFiles(t, "example.com/echo/gen/go/echo/v1;echov1", tt.config)
+
+			script, ok := files["echo/v1/build.sh"]
+			if tt.contains == nil {
+				if ok {
+					t.Errorf("build.sh generated with generate_build_script=false")
+				}
+				return
+			}
+			if !ok {
+				t.Fatalf("build.sh not generated next to the package files, got %v", fileNames(files))
+			}
+			for _, want := range tt.contains {
+				if !strings.Contains(script, want) {
+					t.Errorf("build.sh does not contain %q:\n%s", want, script)
+				}
+			}
+			for _, unwanted := range tt.notContains {
+				if strings.Contains(script, unwanted) {
+					t.Errorf("build.sh contains %q:\n%s", unwanted, script)
+				}
+			}
+		})
+	}
+}
+
+// TestGoGenerator_DefaultTimeouts tests the call timeouts of methods without timeout
+// annotations: client and bidirectional streams, which JavaScript ends, have none.
+func TestGoGenerator_DefaultTimeouts(t *testing.T) {
+	files := generateGoFiles(t, "example.com/echo/gen/go/echo/v1;echov1", &builders.GenerationConfig{WasmExportPath: "."})
+	exports := files["echo/v1/echo_v1_exports.wasm.go"]
+
+	for method, timeout := range map[string]string{
+		"Echo":   "10000*time.Millisecond",
+		"Repeat": "30000*time.Millisecond",
+		"Join":   "0*time.Millisecond",
+		"Chat":   "0*time.Millisecond",
+	} {
+		want := `"/echo.v1.EchoService/` + method + `", ` + timeout + ")"
+		if !strings.Contains(exports, want) {
+			t.Errorf("Exports do not contain %s", want)
+		}
+	}
+}
+
+// TestTSGenerator_BundleJSNamespace tests that the bundle looks the WASM module up under the
+// namespace the Go generator registers it under, whether or not js_namespace is set.
+func TestTSGenerator_BundleJSNamespace(t *testing.T) {
+	tests := []struct {
+		name        string
+		jsNamespace string
+		want        string
+	}{
+		{name: "unset defaults to the package namespace", want: "echo_v1"},
+		{name: "explicit", jsNamespace: "echoApp", want: "echoApp"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			const goPackage = "example.com/echo/gen/go/echo/v1;echov1"
+			goFiles := generateGoFiles(t, goPackage, &builders.GenerationConfig{
+				WasmExportPath: ".",
+				JSNamespace:    tt.jsNamespace,
+			})
+			if exports := goFiles["echo/v1/echo_v1_exports.wasm.go"]; !strings.Contains(exports, "__wasmUnregister_"+tt.want) {
+				t.Fatalf("Go exports do not register under %q, got files %v", tt.want, fileNames(goFiles))
+			}
+
+			plugin, err := protogen.Options{}.New(echoPluginRequest(goPackage, ""))
+			if err != nil {
+				t.Fatalf("Failed to create plugin: %v", err)
+			}
+			filterCriteria, err := filters.ParseFromConfig("", "", "", "")
+			if err != nil {
+				t.Fatalf("ParseFromConfig() error = %v", err)
+			}
+			config := &builders.GenerationConfig{TSExportPath: ".", JSNamespace: tt.jsNamespace}
+			generator := NewTSGenerator(plugin)
+			if err := generator.ValidateConfig(config); err != nil {
+				t.Fatalf("ValidateConfig() error = %v", err)
+			}
+			if err := generator.Generate(config, filterCriteria); err != nil {
+				t.Fatalf("Generate() error = %v", err)
+			}
+
+			var bundle string
+			for _, file := range plugin.Response().File {
+				if file.GetName() == "index.ts" {
+					bundle = file.GetContent()
+				}
+			}
+			if want := "jsNamespace: '" + tt.want + "'"; !strings.Contains(bundle, want) {
+				t.Errorf("Bundle does not contain %q:\n%s", want, bundle)
+			}
+		})
+	}
+}
+
+// echoProtoFile describes echo/v1/echo.proto: an EchoService with a method of each
+// streaming kind and a browser-provided BrowserAPI with a unary and a streaming method.
+func echoProtoFile(goPackage string) *descriptorpb.FileDescriptorProto {
+	browserOptions := &descriptorpb.ServiceOptions{}
+	proto.SetExtension(browserOptions, wasmjsv1.E_BrowserProvided, true)
+
+	message := func(name string) *descriptorpb.DescriptorProto {
+		return &descriptorpb.DescriptorProto{
+			Name: proto.String(name),
+			Field: []*descriptorpb.FieldDescriptorProto{{
+				Name:     proto.String("text"),
+				JsonName: proto.String("text"),
+				Number:   proto.Int32(1),
+				Type:     descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum(),
+				Label:    descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+			}},
+		}
+	}
+	method := func(name string, clientStreaming, serverStreaming bool) *descriptorpb.MethodDescriptorProto {
+		return &descriptorpb.MethodDescriptorProto{
+			Name:            proto.String(name),
+			InputType:       proto.String(".echo.v1.EchoRequest"),
+			OutputType:      proto.String(".echo.v1.EchoResponse"),
+			ClientStreaming: proto.Bool(clientStreaming),
+			ServerStreaming: proto.Bool(serverStreaming),
+		}
+	}
+
+	return &descriptorpb.FileDescriptorProto{
+		Name:        proto.String("echo/v1/echo.proto"),
+		Package:     proto.String("echo.v1"),
+		Syntax:      proto.String("proto3"),
+		Dependency:  []string{"wasmjs/v1/annotations.proto"},
+		Options:     &descriptorpb.FileOptions{GoPackage: proto.String(goPackage)},
+		MessageType: []*descriptorpb.DescriptorProto{message("EchoRequest"), message("EchoResponse")},
+		Service: []*descriptorpb.ServiceDescriptorProto{
+			{
+				Name: proto.String("EchoService"),
+				Method: []*descriptorpb.MethodDescriptorProto{
+					method("Echo", false, false),
+					method("Repeat", false, true),
+					method("Join", true, false),
+					method("Chat", true, true),
+				},
+			},
+			{
+				Name:    proto.String("BrowserAPI"),
+				Options: browserOptions,
+				Method: []*descriptorpb.MethodDescriptorProto{
+					method("Get", false, false),
+					method("Watch", false, true),
+				},
+			},
+		},
+	}
+}
+
+// echoPluginRequest returns the request protoc would send for echo/v1/echo.proto.
+func echoPluginRequest(goPackage, parameter string) *pluginpb.CodeGeneratorRequest {
+	file := echoProtoFile(goPackage)
+	return &pluginpb.CodeGeneratorRequest{
+		FileToGenerate: []string{file.GetName()},
+		Parameter:      proto.String(parameter),
+		ProtoFile: []*descriptorpb.FileDescriptorProto{
+			protodesc.ToFileDescriptorProto(descriptorpb.File_google_protobuf_descriptor_proto),
+			protodesc.ToFileDescriptorProto(wasmjsv1.File_wasmjs_v1_annotations_proto),
+			file,
+		},
+	}
+}
+
+// generateGoFiles runs the Go generator on echo/v1/echo.proto in-process and returns
+// the generated files by name.
+func generateGoFiles(t *testing.T, goPackage string, config *builders.GenerationConfig) map[string]string {
+	t.Helper()
+
+	plugin, err := protogen.Options{}.New(echoPluginRequest(goPackage, ""))
+	if err != nil {
+		t.Fatalf("Failed to create plugin: %v", err)
+	}
+	filterCriteria, err := filters.ParseFromConfig("", "", "", "")
+	if err != nil {
+		t.Fatalf("ParseFromConfig() error = %v", err)
+	}
+
+	generator := NewGoGenerator(plugin)
+	if err := generator.ValidateConfig(config); err != nil {
+		t.Fatalf("ValidateConfig() error = %v", err)
+	}
+	if err := generator.Generate(config, filterCriteria); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	response := plugin.Response()
+	if response.Error != nil {
+		t.Fatalf("Generation failed: %s", response.GetError())
+	}
+	files := make(map[string]string)
+	for _, file := range response.File {
+		files[file.GetName()] = file.GetContent()
+	}
+	return files
+}
+
+// fileNames returns the sorted names of generated files, for failure messages.
+func fileNames(files map[string]string) []string {
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
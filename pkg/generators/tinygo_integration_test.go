@@ -0,0 +1,275 @@
+// Copyright 2025 Sri Panyam
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package generators
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	gengo "google.golang.org/protobuf/cmd/protoc-gen-go/internal_gengo"
+	"google.golang.org/protobuf/compiler/protogen"
+
+	"github.com/panyam/protoc-gen-go-wasmjs/pkg/builders"
+)
+
+// tinyGoMain implements the EchoService generated with target=tinygo against the
+// gRPC-free stream interfaces of pkg/wasm, forwarding to the browser-provided BrowserAPI.
+const tinyGoMain = `//go:build js && wasm
+
+package main
+
+import (
+	"context"
+	"io"
+
+	echov1 "example.com/echo/gen/go/echo/v1"
+	echowasm "example.com/echo/gen/wasm/echo/v1"
+	"github.com/panyam/protoc-gen-go-wasmjs/pkg/wasm"
+)
+
+type echoService struct {
+	browser *echowasm.BrowserAPIClient
+}
+
+func (s *echoService) Echo(ctx context.Context, req *echov1.EchoRequest) (*echov1.EchoResponse, error) {
+	return s.browser.Get(ctx, req)
+}
+
+func (s *echoService) Repeat(req *echov1.EchoRequest, stream wasm.ServerStreamingServer[echov1.EchoResponse]) error {
+	updates, err := s.browser.Watch(stream.Context(), req)
+	if err != nil {
+		return err
+	}
+	for {
+		update, err := updates.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err := stream.Send(update); err != nil {
+			return err
+		}
+	}
+}
+
+func (s *echoService) Join(stream wasm.ClientStreamingServer[echov1.EchoRequest, echov1.EchoResponse]) error {
+	joined := ""
+	for {
+		req, err := stream.Recv()
+		if err == io.EOF {
+			return stream.SendAndClose(&echov1.EchoResponse{Text: joined})
+		}
+		if err != nil {
+			return err
+		}
+		joined += req.GetText()
+	}
+}
+
+func (s *echoService) Chat(stream wasm.BidiStreamingServer[echov1.EchoRequest, echov1.EchoResponse]) error {
+	for {
+		req, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err := stream.Send(&echov1.EchoResponse{Text: req.GetText()}); err != nil {
+			return err
+		}
+	}
+}
+
+func main() {
+	browser := echowasm.NewBrowserAPIClient()
+	exports := &echowasm.Echo_v1ServicesExports{
+		EchoService: &echoService{browser: browser},
+		BrowserAPI:  browser,
+	}
+	exports.RegisterAPI()
+	exports.Wait()
+}
+`
+
+// echoVTProto stands in for protoc-gen-go-vtproto's output for the echo messages (it is
+// not available to tests), encoding their text field with protowire, without reflection.
+const echoVTProto = `package echov1
+
+import "google.golang.org/protobuf/encoding/protowire"
+
+func (m *EchoRequest) MarshalVT() ([]byte, error) { return marshalTextVT(m.Text), nil }
+
+func (m *EchoRequest) UnmarshalVT(data []byte) error { return unmarshalTextVT(data, &m.Text) }
+
+func (m *EchoResponse) MarshalVT() ([]byte, error) { return marshalTextVT(m.Text), nil }
+
+func (m *EchoResponse) UnmarshalVT(data []byte) error { return unmarshalTextVT(data, &m.Text) }
+
+func marshalTextVT(text string) []byte {
+	if text == "" {
+		return nil
+	}
+	return protowire.AppendString(protowire.AppendTag(nil, 1, protowire.BytesType), text)
+}
+
+func unmarshalTextVT(data []byte, text *string) error {
+	for len(data) > 0 {
+		num, typ, n := protowire.ConsumeTag(data)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		data = data[n:]
+		if num == 1 && typ == protowire.BytesType {
+			*text, n = protowire.ConsumeString(data)
+		} else {
+			n = protowire.ConsumeFieldValue(num, typ, data)
+		}
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		data = data[n:]
+	}
+	return nil
+}
+`
+
+// TestGoGenerator_TinyGoBuild generates a module with target=tinygo and checks that it
+// builds for js/wasm without gRPC under the tinygo build tag. When TinyGo is installed
+// the module is also built with the generated build.sh, i.e. with tinygo build -target wasm.
+func TestGoGenerator_TinyGoBuild(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping TinyGo build in short mode")
+	}
+
+	moduleDir := t.TempDir()
+	const goPackage = "example.com/echo/gen/go/echo/v1;echov1"
+
+	// Messages, as protoc-gen-go would generate them
+	plugin, err := protogen.Options{}.New(echoPluginRequest(goPackage, "paths=source_relative"))
+	if err != nil {
+		t.Fatalf("Failed to create plugin: %v", err)
+	}
+	for _, file := range plugin.Files {
+		if file.Generate {
+			gengo.GenerateFile(plugin, file)
+		}
+	}
+	for _, file := range plugin.Response().File {
+		writeModuleFile(t, moduleDir, filepath.Join("gen/go", file.GetName()), file.GetContent())
+	}
+	vtProtoFile := filepath.Join("gen/go/echo/v1", "echo_vtproto.pb.go")
+	writeModuleFile(t, moduleDir, vtProtoFile, echoVTProto)
+
+	// WASM wrappers and build script
+	files := generateGoFiles(t, goPackage, &builders.GenerationConfig{
+		WasmExportPath:      ".",
+		ModuleName:          "echo_wasm",
+		WireFormat:          "binary",
+		Target:              "tinygo",
+		GenerateBuildScript: true,
+	})
+	for name, content := range files {
+		writeModuleFile(t, moduleDir, filepath.Join("gen/wasm", name), content)
+	}
+	writeModuleFile(t, moduleDir, "main.go", tinyGoMain)
+
+	// The module resolves pkg/wasm from this checkout, with the repository's requirements
+	repoRoot, err := filepath.Abs("../..")
+	if err != nil {
+		t.Fatalf("Failed to find repository root: %v", err)
+	}
+	goMod, err := os.ReadFile(filepath.Join(repoRoot, "go.mod"))
+	if err != nil {
+		t.Fatalf("Failed to read go.mod: %v", err)
+	}
+	goSum, err := os.ReadFile(filepath.Join(repoRoot, "go.sum"))
+	if err != nil {
+		t.Fatalf("Failed to read go.sum: %v", err)
+	}
+	modFile := strings.Replace(string(goMod), "module github.com/panyam/protoc-gen-go-wasmjs", "module example.com/echo", 1) +
+		"\nrequire github.com/panyam/protoc-gen-go-wasmjs v0.0.0\n" +
+		"\nreplace github.com/panyam/protoc-gen-go-wasmjs => " + repoRoot + "\n"
+	writeModuleFile(t, moduleDir, "go.mod", modFile)
+	writeModuleFile(t, moduleDir, "go.sum", string(goSum))
+
+	runGo := func(args ...string) (string, error) {
+		cmd := exec.Command("go", args...)
+		cmd.Dir = moduleDir
+		cmd.Env = append(os.Environ(), "GOOS=js", "GOARCH=wasm", "GOFLAGS=-mod=mod")
+		output, err := cmd.CombinedOutput()
+		return string(output), err
+	}
+	goCommand := func(args ...string) string {
+		t.Helper()
+		output, err := runGo(args...)
+		if err != nil {
+			t.Fatalf("go %s failed: %v\n%s", strings.Join(args, " "), err, output)
+		}
+		return output
+	}
+
+	// Type-check the generated code and main package as TinyGo sees them
+	goCommand("vet", "-tags", "tinygo", "./...")
+
+	// No part of gRPC (codes and status included), nor net/http with it, may reach TinyGo builds
+	for _, dep := range strings.Fields(goCommand("list", "-deps", "-tags", "tinygo", ".")) {
+		if dep == "google.golang.org/grpc" || strings.HasPrefix(dep, "google.golang.org/grpc/") ||
+			strings.HasPrefix(dep, "google.golang.org/genproto/") ||
+			dep == "net/http" || strings.HasPrefix(dep, "golang.org/x/net/") {
+			t.Errorf("TinyGo build depends on %s", dep)
+		}
+	}
+
+	// Messages without vtprotobuf methods would silently fall back to protobuf reflection,
+	// so the generated code must not build without them
+	if err := os.Remove(filepath.Join(moduleDir, vtProtoFile)); err != nil {
+		t.Fatalf("Failed to remove %s: %v", vtProtoFile, err)
+	}
+	if output, err := runGo("vet", "-tags", "tinygo", "./..."); err == nil || !strings.Contains(output, "missing method MarshalVT") {
+		t.Errorf("Expected messages without vtprotobuf methods to be rejected, got err %v\n%s", err, output)
+	}
+	writeModuleFile(t, moduleDir, vtProtoFile, echoVTProto)
+
+	if _, err := exec.LookPath("tinygo"); err != nil {
+		t.Log("tinygo not found in PATH; skipping tinygo build")
+		return
+	}
+	cmd := exec.Command("bash", "gen/wasm/echo/v1/build.sh", ".", "dist")
+	cmd.Dir = moduleDir
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("build.sh failed: %v\n%s", err, output)
+	}
+	if _, err := os.Stat(filepath.Join(moduleDir, "dist", "echo_wasm.wasm")); err != nil {
+		t.Errorf("build.sh did not produce echo_wasm.wasm: %v", err)
+	}
+}
+
+// writeModuleFile writes content to name under dir, creating parent directories.
+func writeModuleFile(t *testing.T, dir, name, content string) {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatalf("Failed to create directory for %s: %v", name, err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("Failed to write %s: %v", name, err)
+	}
+}
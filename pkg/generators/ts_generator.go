@@ -18,6 +18,7 @@ import (
 	"fmt"
 	"log"
 	"path/filepath"
+	"sort"
 	"strings"
 
 	"google.golang.org/protobuf/compiler/protogen"
@@ -324,6 +325,16 @@ func (tg *TSGenerator) planFilesFromCatalog(catalog *ArtifactCatalog, factories
 		},
 	})
 
+	// Plan the module-level worker entry point next to the bundle
+	if config.GenerateWorker {
+		specs = append(specs, builders.FileSpec{
+			Name:     "worker",
+			Filename: "worker.ts",
+			Type:     "worker",
+			Required: true,
+		})
+	}
+
 	// Plan type files per package
 	// Track which packages we've already planned to avoid duplicates
 	processedPackages := make(map[string]bool)
@@ -437,6 +448,18 @@ func (tg *TSGenerator) renderFilesFromCatalog(
 		}
 	}
 
+	// Render module-level worker entry point
+	if workerFile := fileSet.GetFile("worker"); workerFile != nil {
+		workerData, err := tg.buildBundleDataFromCatalog(catalog, config)
+		if err != nil {
+			return fmt.Errorf("failed to build worker data: %w", err)
+		}
+
+		if err := tg.renderer.RenderWorker(workerFile, workerData); err != nil {
+			return fmt.Errorf("failed to render worker: %w", err)
+		}
+	}
+
 	// Render type files
 	// Track which packages we've already rendered type files for to avoid duplicates
 	renderedPackages := make(map[string]*builders.TSTemplateData)
@@ -643,17 +666,27 @@ func (tg *TSGenerator) renderFilesFromCatalog(
 
 // buildBundleDataFromCatalog creates bundle template data with just module configuration.
 // The simplified bundle only needs module config - no service information needed.
+// With the binary wire format the bundle also imports every package's schema registry,
+// since schemas are what the runtime uses to encode requests and decode responses.
 func (tg *TSGenerator) buildBundleDataFromCatalog(catalog *ArtifactCatalog, config *builders.GenerationConfig) (*builders.TSTemplateData, error) {
+	var schemaImports []builders.SchemaImport
+	if config.WireFormat == "binary" {
+		schemaImports = tg.buildBundleSchemaImports(catalog, config)
+	}
+
 	// Build minimal bundle template data - just module configuration
 	return &builders.TSTemplateData{
-		PackageName:  "module",                           // Module-level bundle
-		PackagePath:  ".",                                // Root level path
-		ModuleName:   tg.getModuleName("", config),       // Module-level name
-		APIStructure: config.JSStructure,                 // Pass-through configuration
-		JSNamespace:  config.JSNamespace,                 // Pass-through configuration
-		Services:     []builders.ServiceData{},           // No services needed for simple bundle
-		Messages:     []builders.TSMessageInfo{},         // No messages needed
-		Enums:        []builders.TSEnumInfo{},             // No enums needed
+		PackageName:   "module",                           // Module-level bundle
+		PackagePath:   ".",                                // Root level path
+		ModuleName:    tg.getModuleName("", config),       // Module-level name
+		APIStructure:  config.JSStructure,                 // Pass-through configuration
+		JSNamespace:   tg.getJSNamespace(catalog, config), // Must match the Go generator's
+		WireFormat:    config.WireFormat,                  // Pass-through configuration
+		JSTarget:      config.JSTarget,                    // Pass-through configuration
+		SchemaImports: schemaImports,                      // Only populated for the binary wire format
+		Services:      []builders.ServiceData{},           // No services needed for simple bundle
+		Messages:      []builders.TSMessageInfo{},         // No messages needed
+		Enums:         []builders.TSEnumInfo{},             // No enums needed
 		// Minimal flags to satisfy validation
 		HasBrowserServices: false,
 		HasBrowserClients:  false,
@@ -662,6 +695,38 @@ func (tg *TSGenerator) buildBundleDataFromCatalog(catalog *ArtifactCatalog, conf
 	}, nil
 }
 
+// buildBundleSchemaImports collects one schema registry import per package with messages.
+// Import paths are relative to the module-level bundle (index.ts at the output root).
+func (tg *TSGenerator) buildBundleSchemaImports(catalog *ArtifactCatalog, config *builders.GenerationConfig) []builders.SchemaImport {
+	aliasReplacer := strings.NewReplacer("/", "_", ".", "_", "-", "_")
+	seen := make(map[string]bool)
+
+	var schemaImports []builders.SchemaImport
+	for _, msgArtifact := range catalog.Messages {
+		packageInfo := msgArtifact.Package
+		if seen[packageInfo.Name] {
+			continue
+		}
+		seen[packageInfo.Name] = true
+
+		schemasFilename := tg.calculateSchemasFilename(packageInfo, config)
+		importPath := "./" + filepath.ToSlash(strings.TrimSuffix(schemasFilename, ".ts"))
+		baseName := strings.ReplaceAll(packageInfo.Name, ".", "_")
+
+		schemaImports = append(schemaImports, builders.SchemaImport{
+			RegistryName: tg.nameConv.ToCamelCase(baseName) + "SchemaRegistry",
+			Alias:        aliasReplacer.Replace(filepath.ToSlash(filepath.Dir(schemasFilename))) + "Schemas",
+			ImportPath:   importPath,
+		})
+	}
+
+	// Sort schema imports by import path for deterministic output
+	sort.Slice(schemaImports, func(i, j int) bool {
+		return schemaImports[i].ImportPath < schemaImports[j].ImportPath
+	})
+	return schemaImports
+}
+
 // generatePackageFiles handles complete file generation for a package using file planning.
 // This is the new approach where the generator controls all file creation and naming.
 func (tg *TSGenerator) generatePackageFiles(
@@ -1159,6 +1224,21 @@ func (tg *TSGenerator) getModuleName(packageName string, config *builders.Genera
 	return tg.nameConv.ToModuleName(packageName)
 }
 
+// getJSNamespace determines the JavaScript namespace the bundle's WASM module registers under.
+// Like the Go generator, it defaults to the namespace of the services' package; when services
+// come from several packages, set js_namespace so both generators agree.
+func (tg *TSGenerator) getJSNamespace(catalog *ArtifactCatalog, config *builders.GenerationConfig) string {
+	if config.JSNamespace != "" {
+		return config.JSNamespace
+	}
+	for _, services := range [][]ServiceArtifact{catalog.Services, catalog.BrowserServices} {
+		if len(services) > 0 {
+			return tg.nameConv.ToJSNamespace(services[0].Package.Name)
+		}
+	}
+	return ""
+}
+
 // ValidateConfig validates the configuration for TypeScript generation.
 func (tg *TSGenerator) ValidateConfig(config *builders.GenerationConfig) error {
 	if config.TSExportPath == "" {
@@ -1181,5 +1261,27 @@ func (tg *TSGenerator) ValidateConfig(config *builders.GenerationConfig) error {
 		return fmt.Errorf("invalid JSStructure: %s (supported: namespaced, flat, service_based)", config.JSStructure)
 	}
 
+	// Set default WireFormat if not specified
+	if config.WireFormat == "" {
+		config.WireFormat = "json" // Default
+	}
+
+	if config.WireFormat != "json" && config.WireFormat != "binary" {
+		return fmt.Errorf("invalid WireFormat: %s (supported: json, binary)", config.WireFormat)
+	}
+
+	// Set default JSTarget if not specified
+	if config.JSTarget == "" {
+		config.JSTarget = "browser" // Default
+	}
+
+	if config.JSTarget != "browser" && config.JSTarget != "node" {
+		return fmt.Errorf("invalid JSTarget: %s (supported: browser, node)", config.JSTarget)
+	}
+
+	if config.GenerateWorker && config.JSTarget == "node" {
+		return fmt.Errorf("generate_worker is not supported with js_target=node")
+	}
+
 	return nil
 }
@@ -136,6 +136,29 @@ func (gr *GoRenderer) RenderBrowserClientsDirect(file *protogen.GeneratedFile, d
 	return nil
 }
 
+// RenderDispatcherDirect renders the host-buildable dispatcher of the exported methods directly to GeneratedFile.
+func (gr *GoRenderer) RenderDispatcherDirect(file *protogen.GeneratedFile, data *builders.GoTemplateData) error {
+	if file == nil {
+		return fmt.Errorf("GeneratedFile cannot be nil")
+	}
+	if data == nil {
+		return nil // No data to render
+	}
+
+	// Validate Go template data before rendering
+	if err := gr.ValidateGoTemplateData(data); err != nil {
+		return fmt.Errorf("invalid dispatcher data: %w", err)
+	}
+
+	// Execute template and fail early on any errors
+	if err := ExecuteTemplateToFile("dispatcher", GoDispatcherTemplate, data, file); err != nil {
+		return fmt.Errorf("dispatcher template execution failed: %w", err)
+	}
+
+	log.Printf("DISPATCHER: Template rendered successfully")
+	return nil
+}
+
 // RenderServiceInterfacesDirect renders service interfaces directly to GeneratedFile using old generator pattern.
 func (gr *GoRenderer) RenderServiceInterfacesDirect(file *protogen.GeneratedFile, data *builders.GoTemplateData) error {
 	if file == nil {
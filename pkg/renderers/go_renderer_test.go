@@ -0,0 +1,86 @@
+// Copyright 2025 Sri Panyam
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package renderers
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/panyam/protoc-gen-go-wasmjs/pkg/builders"
+)
+
+// TestGoRenderer_RenderBuildScript tests the build script generated for each build target
+// and JavaScript runtime.
+func TestGoRenderer_RenderBuildScript(t *testing.T) {
+	tests := []struct {
+		name       string
+		data       *builders.GoTemplateData
+		contains   []string
+		notContain []string
+	}{
+		{
+			name: "browser",
+			data: &builders.GoTemplateData{PackageName: "library.v1", ModuleName: "library_v1", JSTarget: "browser"},
+			contains: []string{
+				`MAIN_PKG="${1:?`,
+				`go build -o "$OUT_DIR/library_v1.wasm" "$MAIN_PKG"`,
+				`cp "$WASM_EXEC_DIR/wasm_exec.js" "$OUT_DIR/"`,
+				"To use in a web application:",
+			},
+			notContain: []string{"wasm_exec_node.js", "tinygo"},
+		},
+		{
+			name: "node",
+			data: &builders.GoTemplateData{PackageName: "library.v1", ModuleName: "library_v1", JSTarget: "node"},
+			contains: []string{
+				`go build -o "$OUT_DIR/library_v1.wasm" "$MAIN_PKG"`,
+				`cp "$WASM_EXEC_DIR/wasm_exec_node.js" "$OUT_DIR/"`,
+				"To use in Node.js:",
+				"await bundle.loadWasm('./library_v1.wasm');",
+			},
+			notContain: []string{"To use in a web application:"},
+		},
+		{
+			name: "tinygo",
+			data: &builders.GoTemplateData{PackageName: "library.v1", ModuleName: "library_v1", JSTarget: "browser", Target: "tinygo"},
+			contains: []string{
+				`tinygo build -o "$OUT_DIR/library_v1.wasm" -target wasm -no-debug "$MAIN_PKG"`,
+				`WASM_EXEC_DIR="$(tinygo env TINYGOROOT)/targets"`,
+				`cp "$WASM_EXEC_DIR/wasm_exec.js" "$OUT_DIR/"`,
+			},
+			notContain: []string{"export GOOS=js", "go env GOROOT"},
+		},
+	}
+
+	renderer := NewGoRenderer()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			script, err := renderer.RenderBuildScript(tt.data)
+			if err != nil {
+				t.Fatalf("RenderBuildScript() error = %v", err)
+			}
+			for _, want := range tt.contains {
+				if !strings.Contains(script, want) {
+					t.Errorf("build script does not contain %q:\n%s", want, script)
+				}
+			}
+			for _, unwanted := range tt.notContain {
+				if strings.Contains(script, unwanted) {
+					t.Errorf("build script contains %q:\n%s", unwanted, script)
+				}
+			}
+		})
+	}
+}
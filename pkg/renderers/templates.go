@@ -32,6 +32,9 @@ var GoBrowserClientsTemplate string
 //go:embed templates/wasm_service_interfaces.go.tmpl
 var GoServiceInterfacesTemplate string
 
+//go:embed templates/wasm_dispatcher.go.tmpl
+var GoDispatcherTemplate string
+
 //go:embed templates/wasm.go.tmpl
 var GoWasmTemplate string
 
@@ -72,6 +75,9 @@ var TSBundleTemplate string
 //go:embed templates/browser_service.ts.tmpl
 var TSBrowserServiceTemplate string
 
+//go:embed templates/worker.ts.tmpl
+var TSWorkerTemplate string
+
 // Removed: TSDeserializerSchemasTemplate (now imported from @protoc-gen-go-wasmjs/runtime)
 
 // Removed: TSClientTemplate (unused dead code)
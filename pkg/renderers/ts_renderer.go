@@ -175,6 +175,20 @@ func (tr *TSRenderer) RenderBundle(file *protogen.GeneratedFile, data *builders.
 	return tr.RenderToFile(file, TSBundleTemplate, data)
 }
 
+// RenderWorker generates the Web Worker entry point hosting the module's WASM exports.
+// It uses the bundle's template data, since the worker needs the same module configuration.
+func (tr *TSRenderer) RenderWorker(file *protogen.GeneratedFile, data *builders.TSTemplateData) error {
+	if data == nil {
+		return nil
+	}
+
+	if err := tr.ValidateBundleTemplateData(data); err != nil {
+		return fmt.Errorf("invalid worker data: %w", err)
+	}
+
+	return tr.RenderToFile(file, TSWorkerTemplate, data)
+}
+
 // ValidateBundleTemplateData validates TSTemplateData specifically for bundle rendering.
 // Bundle validation is less strict since bundles don't use method data.
 func (tr *TSRenderer) ValidateBundleTemplateData(data *builders.TSTemplateData) error {
@@ -0,0 +1,42 @@
+// Package wasm provides runtime support for protoc-gen-go-wasmjs generated code.
+package wasm
+
+import "google.golang.org/protobuf/proto"
+
+// VTMessage is a message generated with protoc-gen-go-vtproto (features=marshal+unmarshal+size),
+// which MarshalBinary and UnmarshalBinary encode without protobuf reflection. Code generated
+// with target=tinygo requires its request and response messages to implement it.
+type VTMessage interface {
+	proto.Message
+	MarshalVT() ([]byte, error)
+	UnmarshalVT(data []byte) error
+}
+
+// vtMarshaler and vtUnmarshaler are the halves of VTMessage MarshalBinary and UnmarshalBinary use.
+type vtMarshaler interface {
+	MarshalVT() ([]byte, error)
+}
+
+type vtUnmarshaler interface {
+	UnmarshalVT(data []byte) error
+}
+
+// MarshalBinary serializes m to protobuf bytes with its vtprotobuf MarshalVT method,
+// which avoids protobuf reflection (as TinyGo builds need), falling back to proto.Marshal.
+func MarshalBinary(m proto.Message) ([]byte, error) {
+	if vt, ok := m.(vtMarshaler); ok {
+		return vt.MarshalVT()
+	}
+	return proto.Marshal(m)
+}
+
+// UnmarshalBinary parses protobuf bytes into m with its vtprotobuf UnmarshalVT method,
+// falling back to proto.Unmarshal with opts. opts do not apply to UnmarshalVT, which
+// would need protobuf reflection to honour them: it keeps unknown fields (handlers never
+// see them) and always rejects messages missing proto2 required fields.
+func UnmarshalBinary(data []byte, m proto.Message, opts UnmarshalOptions) error {
+	if vt, ok := m.(vtUnmarshaler); ok {
+		return vt.UnmarshalVT(data)
+	}
+	return proto.UnmarshalOptions{DiscardUnknown: opts.DiscardUnknown, AllowPartial: opts.AllowPartial}.Unmarshal(data, m)
+}
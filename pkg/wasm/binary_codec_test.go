@@ -0,0 +1,78 @@
+// Package wasm provides runtime support for protoc-gen-go-wasmjs generated code.
+package wasm
+
+import (
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+// vtStringValue stands in for a message generated with protoc-gen-go-vtproto
+type vtStringValue struct {
+	*wrapperspb.StringValue
+	marshalled, unmarshalled bool
+}
+
+func (v *vtStringValue) MarshalVT() ([]byte, error) {
+	v.marshalled = true
+	return proto.Marshal(v.StringValue)
+}
+
+func (v *vtStringValue) UnmarshalVT(data []byte) error {
+	v.unmarshalled = true
+	return proto.Unmarshal(data, v.StringValue)
+}
+
+// TestBinaryCodec tests encoding messages with and without vtprotobuf methods
+func TestBinaryCodec(t *testing.T) {
+	t.Run("Uses vtprotobuf methods when available", func(t *testing.T) {
+		msg := &vtStringValue{StringValue: wrapperspb.String("hello")}
+		data, err := MarshalBinary(msg)
+		if err != nil || !msg.marshalled {
+			t.Fatalf("Expected MarshalVT to be used, got err %v", err)
+		}
+
+		decoded := &vtStringValue{StringValue: &wrapperspb.StringValue{}}
+		if err := UnmarshalBinary(data, decoded, UnmarshalOptions{DiscardUnknown: true, AllowPartial: true}); err != nil || !decoded.unmarshalled {
+			t.Fatalf("Expected UnmarshalVT to be used, got err %v", err)
+		}
+		if decoded.GetValue() != "hello" {
+			t.Errorf("Expected 'hello', got %q", decoded.GetValue())
+		}
+	})
+
+	t.Run("Falls back to proto reflection", func(t *testing.T) {
+		data, err := MarshalBinary(wrapperspb.String("hello"))
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		decoded := &wrapperspb.StringValue{}
+		if err := UnmarshalBinary(data, decoded, UnmarshalOptions{DiscardUnknown: true, AllowPartial: true}); err != nil || decoded.GetValue() != "hello" {
+			t.Errorf("Expected 'hello', got %q (err: %v)", decoded.GetValue(), err)
+		}
+	})
+	t.Run("Honours DiscardUnknown without vtprotobuf methods", func(t *testing.T) {
+		data := append([]byte{0x0a, 0x02, 'h', 'i'}, 0x10, 0x01) // value "hi" and unknown field 2
+		for _, discard := range []bool{true, false} {
+			decoded := &wrapperspb.StringValue{}
+			if err := UnmarshalBinary(data, decoded, UnmarshalOptions{DiscardUnknown: discard, AllowPartial: true}); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if kept := len(decoded.ProtoReflect().GetUnknown()) > 0; kept == discard {
+				t.Errorf("DiscardUnknown=%v: unknown fields kept = %v", discard, kept)
+			}
+		}
+	})
+
+	t.Run("Leaves unknown fields to UnmarshalVT", func(t *testing.T) {
+		data := append([]byte{0x0a, 0x02, 'h', 'i'}, 0x10, 0x01)
+		decoded := &vtStringValue{StringValue: &wrapperspb.StringValue{}}
+		if err := UnmarshalBinary(data, decoded, UnmarshalOptions{DiscardUnknown: true}); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if decoded.GetValue() != "hi" || len(decoded.ProtoReflect().GetUnknown()) == 0 {
+			t.Errorf("Expected UnmarshalVT's result unchanged, got %q with unknown fields %v", decoded.GetValue(), decoded.ProtoReflect().GetUnknown())
+		}
+	})
+}
@@ -65,12 +65,20 @@ type BrowserCall struct {
 	// This is sent to JavaScript as a JSON string after deserialization.
 	Request []byte
 
+	// RequestType and ResponseType are the full proto names of the messages of a call
+	// made with protobuf bytes (see BrowserMethod.Binary); empty for JSON calls.
+	// JavaScript receives Request as a Uint8Array to decode as RequestType, and
+	// delivers the response encoded as ResponseType.
+	RequestType  string
+	ResponseType string
+
 	// ResponseCh is the channel where the response will be delivered.
 	// Buffered channel with capacity 1 to prevent blocking.
 	ResponseCh chan *CallResponse
 
 	// Timeout is the maximum duration to wait for a response.
 	// If the timeout expires, the call is canceled and an error is returned.
+	// Zero means no timeout (used by streams, which end with their context).
 	Timeout time.Duration
 
 	// StartTime records when this call was initiated.
@@ -81,6 +89,14 @@ type BrowserCall struct {
 	// Async methods use callbacks to prevent main thread deadlocks.
 	// Set to true for methods with (wasmjs.v1.async_method) = { is_async: true }.
 	IsAsync bool
+
+	// Context is the Go context of the caller.
+	// Calls whose context is done before JavaScript picks them up are skipped.
+	Context context.Context
+
+	// Stream receives the messages of a server-streaming call; nil for unary calls.
+	// JavaScript ends the stream through __wasmDeliverBrowserResponse.
+	Stream *BrowserStream
 }
 
 // CallResponse represents the response from a browser service call.
@@ -104,9 +120,12 @@ type CallResponse struct {
 // It provides the communication bridge between WASM and JavaScript, handling
 // call queuing, timeout management, and response delivery.
 //
-// The channel is implemented as a singleton and initialized automatically on first use.
-// It registers global JavaScript functions that JavaScript code polls to receive calls
-// and deliver responses.
+// Each WASM module gets its own channel, named after its JS namespace (see
+// GetNamedBrowserChannel), so several generated modules can share a page. A channel is
+// initialized automatically on first use and registers global JavaScript functions,
+// suffixed with "_<name>", that JavaScript code uses to receive calls and deliver
+// responses. Instead of polling, JavaScript registers a dispatcher function
+// that the channel invokes whenever a call is queued.
 //
 // Thread Safety:
 //
@@ -115,14 +134,20 @@ type CallResponse struct {
 //
 // JavaScript Integration:
 //
-//	The channel exposes these global functions:
-//	  - __wasmGetNextBrowserCall(): Returns next pending call or null
-//	  - __wasmDeliverBrowserResponse(id, data, error): Delivers response or error
+//	The channel exposes these global functions (shown for the unnamed channel;
+//	a channel named "myApp" exposes __wasmGetNextBrowserCall_myApp and so on):
+//	  - __wasmGetNextBrowserCall(): Returns next pending call or null without blocking
+//	  - __wasmDeliverBrowserResponse(id, data, error): Delivers response or error, or ends a stream
+//	  - __wasmDeliverBrowserStreamMessage(id, data): Delivers a message of a server-streaming call
+//
+//	and invokes these global functions, if JavaScript has set them:
+//	  - __wasmBrowserCallDispatcher(): Drains pending calls with __wasmGetNextBrowserCall
+//	  - __wasmBrowserCallCancelled(id, reason): Aborts a call whose Go context was cancelled or that timed out
 //
 // Usage Example:
 //
-//	// Get singleton instance
-//	channel := wasm.GetBrowserChannel()
+//	// Get the channel of the module whose JS namespace is "myApp"
+//	channel := wasm.GetNamedBrowserChannel("myApp")
 //
 //	// Create and execute call
 //	call := &wasm.BrowserCall{
@@ -134,15 +159,22 @@ type CallResponse struct {
 //	}
 //	response, err := channel.CallBrowserService(ctx, call)
 type BrowserServiceChannel struct {
+	// name suffixes the JavaScript globals of the channel; empty for the unnamed channel.
+	name string
+
 	// callQueue buffers pending calls waiting to be picked up by JavaScript.
-	// Buffered channel with capacity 100 to handle bursts of calls.
-	callQueue chan *BrowserCall
+	// Bounded (DefaultCallQueueCapacity unless changed with SetQueueOptions) to
+	// apply backpressure when JavaScript falls behind.
+	callQueue *CallQueue[*BrowserCall]
 
 	// pendingCalls tracks calls that have been sent to JavaScript but not yet responded.
 	// Maps call ID to PendingCall struct for timeout management.
 	pendingCalls map[string]*PendingCall
 
-	// mu protects concurrent access to pendingCalls map.
+	// interceptors run around every call made through Invoke or NewStream, outermost first.
+	interceptors []BrowserClientInterceptor
+
+	// mu protects concurrent access to pendingCalls map and interceptors.
 	mu sync.RWMutex
 
 	// nextCallID is atomically incremented to generate unique call IDs.
@@ -151,6 +183,13 @@ type BrowserServiceChannel struct {
 	// initialized indicates if Initialize() has been called.
 	// Prevents double initialization of JavaScript callbacks.
 	initialized bool
+
+	// jsFuncs holds the JavaScript callbacks registered by Initialize, released by Close.
+	jsFuncs []js.Func
+
+	// closed is closed by Close; waiting callers and the timeout processor stop on it.
+	closed    chan struct{}
+	closeOnce sync.Once
 }
 
 // PendingCall tracks an in-flight browser service call.
@@ -171,32 +210,100 @@ type PendingCall struct {
 	RefCount int32
 }
 
-// Global singleton browser channel instance.
-// Initialized lazily on first call to GetBrowserChannel().
+// Ensure BrowserServiceChannel implements BrowserChannel
+var _ BrowserChannel = (*BrowserServiceChannel)(nil)
+
+// Browser channel instances keyed by name.
+// Created lazily by GetNamedBrowserChannel() and removed by CloseNamedBrowserChannel().
 var (
-	browserChannel     *BrowserServiceChannel
-	browserChannelOnce sync.Once
+	browserChannels  = make(map[string]*BrowserServiceChannel)
+	browserChannelMu sync.Mutex
 )
 
-// GetBrowserChannel returns the singleton BrowserServiceChannel instance.
-// The channel is initialized automatically on first call using sync.Once,
-// ensuring thread-safe singleton initialization.
-//
-// The returned channel is ready to use and has registered all JavaScript callbacks.
+// GetBrowserChannel returns the unnamed BrowserServiceChannel, whose JavaScript globals
+// carry no suffix. Generated code uses GetNamedBrowserChannel instead.
 //
 // Example:
 //
 //	channel := wasm.GetBrowserChannel()
 //	// channel is ready to accept browser service calls
 func GetBrowserChannel() *BrowserServiceChannel {
-	browserChannelOnce.Do(func() {
-		browserChannel = &BrowserServiceChannel{
-			callQueue:    make(chan *BrowserCall, 100),
+	return GetNamedBrowserChannel("")
+}
+
+// GetNamedBrowserChannel returns the BrowserServiceChannel with the given name, creating
+// and initializing it on first call (and on the first call after CloseNamedBrowserChannel).
+// Its JavaScript globals are suffixed with "_" + name, so modules using different names
+// do not overwrite each other's entry points. Generated code passes the module's JS namespace.
+//
+// Example:
+//
+//	channel := wasm.GetNamedBrowserChannel("myApp")
+//	// JavaScript drains it with __wasmGetNextBrowserCall_myApp
+func GetNamedBrowserChannel(name string) *BrowserServiceChannel {
+	browserChannelMu.Lock()
+	defer browserChannelMu.Unlock()
+	channel, exists := browserChannels[name]
+	if !exists {
+		channel = &BrowserServiceChannel{
+			name:         name,
+			callQueue:    NewCallQueue[*BrowserCall](QueueOptions{}),
 			pendingCalls: make(map[string]*PendingCall),
+			closed:       make(chan struct{}),
 		}
-		browserChannel.Initialize()
+		channel.Initialize()
+		browserChannels[name] = channel
+	}
+	return channel
+}
+
+// CloseBrowserChannel closes the unnamed channel (see CloseNamedBrowserChannel).
+func CloseBrowserChannel() {
+	CloseNamedBrowserChannel("")
+}
+
+// CloseNamedBrowserChannel closes the channel with the given name, if one was created, and
+// forgets it so the next GetNamedBrowserChannel call creates a fresh one. Generated exports
+// call it when they are unregistered.
+func CloseNamedBrowserChannel(name string) {
+	browserChannelMu.Lock()
+	channel := browserChannels[name]
+	delete(browserChannels, name)
+	browserChannelMu.Unlock()
+
+	if channel != nil {
+		channel.Close()
+	}
+}
+
+// Name returns the name of the channel; empty for the unnamed channel.
+func (bc *BrowserServiceChannel) Name() string {
+	return bc.name
+}
+
+// jsGlobal returns the name of a JavaScript global of the channel, suffixed with its name.
+func (bc *BrowserServiceChannel) jsGlobal(base string) string {
+	if bc.name == "" {
+		return base
+	}
+	return base + "_" + bc.name
+}
+
+// Close removes the channel's JavaScript globals, releases its callbacks, stops its
+// timeout processor and fails queued and in-flight calls with ErrBrowserChannelClosed.
+// Calling it more than once has no effect.
+func (bc *BrowserServiceChannel) Close() {
+	bc.closeOnce.Do(func() {
+		close(bc.closed)
+
+		js.Global().Delete(bc.jsGlobal("__wasmGetNextBrowserCall"))
+		js.Global().Delete(bc.jsGlobal("__wasmDeliverBrowserResponse"))
+		js.Global().Delete(bc.jsGlobal("__wasmDeliverBrowserStreamMessage"))
+		for _, f := range bc.jsFuncs {
+			f.Release()
+		}
+		bc.jsFuncs = nil
 	})
-	return browserChannel
 }
 
 // Initialize sets up the browser channel and registers JS callbacks
@@ -207,26 +314,39 @@ func (bc *BrowserServiceChannel) Initialize() {
 	bc.initialized = true
 
 	// Register JS function to get next browser call
-	js.Global().Set("__wasmGetNextBrowserCall", js.FuncOf(func(this js.Value, args []js.Value) any {
-		select {
-		case call := <-bc.callQueue:
+	getNextBrowserCall := js.FuncOf(func(this js.Value, args []js.Value) any {
+		for {
+			call, ok := bc.callQueue.Pop()
+			if !ok {
+				// Non-blocking check, return null if no calls pending
+				return js.Null()
+			}
+			// Skip calls the Go caller gave up on while they were queued
+			if (call.Context != nil && call.Context.Err() != nil) || (call.Timeout > 0 && time.Since(call.StartTime) > call.Timeout) {
+				continue
+			}
 			bc.registerPendingCall(call)
 
 			// Return call details to JavaScript
-			return map[string]any{
-				"id":      call.ID,
-				"service": call.Service,
-				"method":  call.Method,
-				"request": string(call.Request),
+			details := map[string]any{
+				"id":        call.ID,
+				"service":   call.Service,
+				"method":    call.Method,
+				"request":   string(call.Request),
+				"streaming": call.Stream != nil,
+			}
+			if call.RequestType != "" {
+				details["request"] = BytesToJS(call.Request)
+				details["requestType"] = call.RequestType
+				details["responseType"] = call.ResponseType
 			}
-		case <-time.After(10 * time.Millisecond):
-			// Non-blocking check, return null if no calls pending
-			return js.Null()
+			return details
 		}
-	}))
+	})
+	js.Global().Set(bc.jsGlobal("__wasmGetNextBrowserCall"), getNextBrowserCall)
 
 	// Register JS function to deliver browser call response
-	js.Global().Set("__wasmDeliverBrowserResponse", js.FuncOf(func(this js.Value, args []js.Value) any {
+	deliverBrowserResponse := js.FuncOf(func(this js.Value, args []js.Value) any {
 		if len(args) < 3 {
 			return false
 		}
@@ -239,6 +359,7 @@ func (bc *BrowserServiceChannel) Initialize() {
 		pending, exists := bc.pendingCalls[callID]
 		bc.mu.RUnlock()
 
+		// The call was cancelled or timed out; JavaScript reports the late delivery
 		if !exists {
 			return false
 		}
@@ -248,7 +369,12 @@ func (bc *BrowserServiceChannel) Initialize() {
 		if !errorMsg.IsNull() && !errorMsg.IsUndefined() {
 			response.Error = errors.New(errorMsg.String())
 		} else if !responseData.IsNull() && !responseData.IsUndefined() {
-			response.Data = []byte(responseData.String())
+			response.Data = browserPayload(responseData)
+		}
+
+		// Streams end when JavaScript delivers their response
+		if pending.Call.Stream != nil {
+			pending.Call.Stream.Finish(response.Error)
 		}
 
 		// Send response to waiting goroutine
@@ -261,12 +387,41 @@ func (bc *BrowserServiceChannel) Initialize() {
 		// Cleanup
 		bc.cleanupCall(callID)
 		return true
-	}))
+	})
+	js.Global().Set(bc.jsGlobal("__wasmDeliverBrowserResponse"), deliverBrowserResponse)
+
+	// Register JS function to deliver a message of a server-streaming call
+	deliverBrowserStreamMessage := js.FuncOf(func(this js.Value, args []js.Value) any {
+		if len(args) < 2 {
+			return false
+		}
+
+		bc.mu.RLock()
+		pending, exists := bc.pendingCalls[args[0].String()]
+		bc.mu.RUnlock()
+
+		// The stream was cancelled or has ended; JavaScript stops producing messages
+		if !exists || pending.Call.Stream == nil {
+			return false
+		}
+		return pending.Call.Stream.Push(browserPayload(args[1]))
+	})
+	js.Global().Set(bc.jsGlobal("__wasmDeliverBrowserStreamMessage"), deliverBrowserStreamMessage)
+	bc.jsFuncs = []js.Func{getNextBrowserCall, deliverBrowserResponse, deliverBrowserStreamMessage}
 
 	// Start background processor for timeouts
 	go bc.processTimeouts()
 }
 
+// browserPayload returns the bytes of a response or stream message delivered by JavaScript:
+// protobuf bytes for binary calls, a JSON string otherwise.
+func browserPayload(value js.Value) []byte {
+	if IsJSBytes(value) {
+		return BytesFromJS(value)
+	}
+	return []byte(value.String())
+}
+
 // NextCallID generates a unique call ID
 func (bc *BrowserServiceChannel) NextCallID() string {
 	id := atomic.AddUint64(&bc.nextCallID, 1)
@@ -285,46 +440,89 @@ func (bc *BrowserServiceChannel) QueueCallAsync(ctx context.Context, service, me
 
 // queueCallInternal is the internal implementation for queuing calls
 func (bc *BrowserServiceChannel) queueCallInternal(ctx context.Context, service, method string, request []byte, timeout time.Duration, isAsync bool) ([]byte, error) {
+	return bc.queueBrowserCall(ctx, &BrowserCall{
+		Service: service,
+		Method:  method,
+		Request: request,
+		IsAsync: isAsync,
+	}, timeout)
+}
+
+// queueBrowserCall queues a call described by its Service, Method, Request, IsAsync and
+// message types, filling in the rest, and waits for its response.
+func (bc *BrowserServiceChannel) queueBrowserCall(ctx context.Context, call *BrowserCall, timeout time.Duration) ([]byte, error) {
 	callID := bc.NextCallID()
 	responseCh := make(chan *CallResponse, 1)
 
-	call := &BrowserCall{
-		ID:         callID,
-		Service:    service,
-		Method:     method,
-		Request:    request,
-		ResponseCh: responseCh,
-		Timeout:    timeout,
-		StartTime:  time.Now(),
-		IsAsync:    isAsync,
-	}
+	call.ID = callID
+	call.ResponseCh = responseCh
+	call.Timeout = timeout
+	call.StartTime = time.Now()
+	call.Context = ctx
 
 	// Queue the call
 	select {
-	case bc.callQueue <- call:
-	// case <-ctx.Done():
-	// I dont think we should be checking for Done as themoment the wasm call (from browser) returns,
-	// this ctx returns, where as we are meant to be running in the background
-	// fmt.Println("Found Error in call queueing: ", ctx.Err())
-	// return nil, ctx.Err()
-	case <-time.After(timeout):
-		return nil, fmt.Errorf("timeout queuing browser call")
+	case <-bc.closed:
+		return nil, ErrBrowserChannelClosed
+	default:
+	}
+	dropped, hasDropped, err := bc.callQueue.Push(ctx, call, bc.closed, time.After(timeout))
+	switch {
+	case errors.Is(err, errQueueStopped):
+		return nil, ErrBrowserChannelClosed
+	case errors.Is(err, errQueueTimeout):
+		return nil, fmt.Errorf("%w while queuing", ErrBrowserCallTimeout)
+	case err != nil:
+		return nil, err
+	}
+	if hasDropped {
+		bc.failDroppedCall(dropped)
 	}
+	bc.notifyDispatcher()
 
 	// Wait for response
 	select {
 	case resp := <-responseCh:
+		if resp == nil {
+			// Channel closed by handleTimeout
+			return nil, fmt.Errorf("%w after %v", ErrBrowserCallTimeout, timeout)
+		}
 		if resp.Error != nil {
 			return nil, resp.Error
 		}
 		return resp.Data, nil
-	// case <-ctx.Done():
-	// fmt.Println("Resp was 'done'  How - may be because the brower call returned but we should keep oging?")
-	// bc.cleanupCall(callID)
-	// return nil, ctx.Err()
-	case <-time.After(timeout):
+	case <-bc.closed:
 		bc.cleanupCall(callID)
-		return nil, fmt.Errorf("browser call timeout after %v", timeout)
+		return nil, ErrBrowserChannelClosed
+	case <-ctx.Done():
+		bc.cancelCall(callID, ctx.Err().Error())
+		return nil, ctx.Err()
+	case <-time.After(timeout):
+		bc.cancelCall(callID, "browser call timeout")
+		return nil, fmt.Errorf("%w after %v", ErrBrowserCallTimeout, timeout)
+	}
+}
+
+// failDroppedCall fails a call evicted from the full queue with ErrBrowserCallDropped.
+func (bc *BrowserServiceChannel) failDroppedCall(call *BrowserCall) {
+	if call.Stream != nil {
+		call.Stream.Finish(ErrBrowserCallDropped)
+		return
+	}
+	// Fail the evicted call's waiting goroutine; its response channel is buffered
+	select {
+	case call.ResponseCh <- &CallResponse{Error: ErrBrowserCallDropped}:
+	default:
+	}
+}
+
+// notifyDispatcher tells the JavaScript dispatcher, if one is registered, that calls are waiting.
+// The dispatcher is looked up on every call so JavaScript can register it before or after
+// the WASM module starts.
+func (bc *BrowserServiceChannel) notifyDispatcher() {
+	dispatcher := js.Global().Get(bc.jsGlobal("__wasmBrowserCallDispatcher"))
+	if dispatcher.Type() == js.TypeFunction {
+		dispatcher.Invoke()
 	}
 }
 
@@ -333,9 +531,12 @@ func (bc *BrowserServiceChannel) registerPendingCall(call *BrowserCall) {
 	bc.mu.Lock()
 	defer bc.mu.Unlock()
 
-	timer := time.AfterFunc(call.Timeout, func() {
-		bc.handleTimeout(call.ID)
-	})
+	var timer *time.Timer
+	if call.Timeout > 0 {
+		timer = time.AfterFunc(call.Timeout, func() {
+			bc.handleTimeout(call.ID)
+		})
+	}
 
 	bc.pendingCalls[call.ID] = &PendingCall{
 		Call:     call,
@@ -355,10 +556,15 @@ func (bc *BrowserServiceChannel) handleTimeout(callID string) {
 	delete(bc.pendingCalls, callID)
 	bc.mu.Unlock()
 
+	if pending.Timer != nil {
+		pending.Timer.Stop()
+	}
+	bc.notifyCancelled(callID, "browser call timeout")
+
 	// Send timeout error
 	select {
 	case pending.Call.ResponseCh <- &CallResponse{
-		Error: fmt.Errorf("browser call timeout"),
+		Error: ErrBrowserCallTimeout,
 	}:
 	default:
 	}
@@ -367,6 +573,27 @@ func (bc *BrowserServiceChannel) handleTimeout(callID string) {
 	close(pending.Call.ResponseCh)
 }
 
+// cancelCall cleans up a call the Go caller stopped waiting for and tells
+// JavaScript to abort it if it was already picked up.
+func (bc *BrowserServiceChannel) cancelCall(callID string, reason string) {
+	bc.mu.RLock()
+	_, exists := bc.pendingCalls[callID]
+	bc.mu.RUnlock()
+
+	bc.cleanupCall(callID)
+	if exists {
+		bc.notifyCancelled(callID, reason)
+	}
+}
+
+// notifyCancelled tells JavaScript, if it registered a cancellation handler, to abort a call.
+func (bc *BrowserServiceChannel) notifyCancelled(callID string, reason string) {
+	onCancelled := js.Global().Get(bc.jsGlobal("__wasmBrowserCallCancelled"))
+	if onCancelled.Type() == js.TypeFunction {
+		onCancelled.Invoke(callID, reason)
+	}
+}
+
 // cleanupCall cleans up a completed or cancelled call
 func (bc *BrowserServiceChannel) cleanupCall(callID string) {
 	bc.mu.Lock()
@@ -394,13 +621,19 @@ func (bc *BrowserServiceChannel) processTimeouts() {
 	ticker := time.NewTicker(100 * time.Millisecond)
 	defer ticker.Stop()
 
-	for range ticker.C {
+	for {
+		select {
+		case <-bc.closed:
+			return
+		case <-ticker.C:
+		}
+
 		now := time.Now()
 		bc.mu.Lock()
 
 		var timedOut []string
 		for id, pending := range bc.pendingCalls {
-			if now.Sub(pending.Call.StartTime) > pending.Call.Timeout {
+			if pending.Call.Timeout > 0 && now.Sub(pending.Call.StartTime) > pending.Call.Timeout {
 				timedOut = append(timedOut, id)
 			}
 		}
@@ -420,81 +653,155 @@ func (bc *BrowserServiceChannel) GetPendingCallCount() int {
 	return len(bc.pendingCalls)
 }
 
-// CallBrowserService is a generic helper for calling synchronous browser services
-// The browser method should return a value directly (not a Promise)
-func CallBrowserService[TReq any, TResp any](channel *BrowserServiceChannel, ctx context.Context, serviceName, methodName string, req TReq) (TResp, error) {
-	var resp TResp
+// SetQueueOptions changes the capacity and overflow policy of the channel's call queue.
+// Calls already queued are kept.
+//
+// Example:
+//
+//	wasm.GetNamedBrowserChannel("myApp").SetQueueOptions(wasm.QueueOptions{
+//	    Capacity:       20,
+//	    OverflowPolicy: wasm.QueueOverflowFailFast, // Fail with RESOURCE_EXHAUSTED when full
+//	})
+func (bc *BrowserServiceChannel) SetQueueOptions(options QueueOptions) {
+	bc.callQueue.SetOptions(options)
+}
 
-	// If TResp is a pointer type, we need to create a new instance
-	// This is necessary for protobuf message types which are pointers
-	respType := reflect.TypeOf(resp)
-	fmt.Printf("DEBUG: CallBrowserService - Initial resp type=%T, kind=%v\n", resp, respType.Kind())
-	if respType.Kind() == reflect.Ptr {
-		// Create a new instance of the underlying type
-		respValue := reflect.New(respType.Elem())
-		resp = respValue.Interface().(TResp)
-		fmt.Printf("DEBUG: CallBrowserService - Created new instance, resp type=%T\n", resp)
-	}
+// QueueStats returns the state of the channel's call queue, the number of pending calls
+// and counts of enqueued, blocked, rejected and dropped calls.
+func (bc *BrowserServiceChannel) QueueStats() QueueStats {
+	stats := bc.callQueue.Stats()
+	stats.Pending = bc.GetPendingCallCount()
+	return stats
+}
 
-	// Marshal the request using the global marshaller
-	marshaller := GetGlobalMarshaller()
+// UseInterceptors adds client interceptors that run around every browser service
+// call made through this channel. Channel interceptors run before (outside) the
+// interceptors of individual generated clients.
+func (bc *BrowserServiceChannel) UseInterceptors(interceptors ...BrowserClientInterceptor) {
+	bc.mu.Lock()
+	defer bc.mu.Unlock()
+	bc.interceptors = append(bc.interceptors, interceptors...)
+}
 
-	// Use reflection to get the proto message interface
-	reqMsg, ok := any(req).(proto.Message)
-	if !ok {
-		return resp, fmt.Errorf("request is not a proto message")
-	}
+// Invoke calls a browser-provided service method through the channel interceptors
+// followed by the given client interceptors, filling reply with the response.
+func (bc *BrowserServiceChannel) Invoke(ctx context.Context, method BrowserMethod, req, reply proto.Message, interceptors ...BrowserClientInterceptor) error {
+	bc.mu.RLock()
+	channelInterceptors := bc.interceptors
+	bc.mu.RUnlock()
+
+	return invokeBrowserMethod(ctx, method, req, reply, channelInterceptors, interceptors, func(ctx context.Context, req, reply proto.Message) error {
+		return bc.callBrowser(ctx, method, req, reply)
+	})
+}
+
+// NewStream opens a server-streaming call to a browser-provided service method through
+// the channel interceptors followed by the given client interceptors, sending req to the
+// JavaScript implementation. Messages are received from the returned stream until
+// JavaScript ends it; cancelling ctx aborts the implementation.
+func (bc *BrowserServiceChannel) NewStream(ctx context.Context, method BrowserMethod, req proto.Message, interceptors ...BrowserClientInterceptor) (*BrowserStream, error) {
+	bc.mu.RLock()
+	channelInterceptors := bc.interceptors
+	bc.mu.RUnlock()
 
-	requestData, err := marshaller.Marshal(reqMsg, MarshalOptions{
-		UseProtoNames:   false,
-		EmitUnpopulated: true,
-		UseEnumNumbers:  false,
+	return openBrowserStream(ctx, method, req, channelInterceptors, interceptors, func(ctx context.Context, req proto.Message) (*BrowserStream, error) {
+		return bc.openStream(ctx, method, req)
 	})
+}
+
+// openStream queues the call of a server-streaming method for JavaScript.
+func (bc *BrowserServiceChannel) openStream(ctx context.Context, method BrowserMethod, req proto.Message) (*BrowserStream, error) {
+	requestData, err := method.marshal(req)
 	if err != nil {
-		return resp, fmt.Errorf("failed to marshal request: %w", err)
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
 	}
 
-	// Call browser service through the channel
-	fmt.Println("DEBUG: ABOUT TO QUEUE BROWSER CALL: ", serviceName, methodName, time.Now())
-	responseData, err := channel.QueueCall(ctx, serviceName, methodName, requestData, 30*time.Second)
-	if err != nil {
-		fmt.Println("DEBUG: QueueCall FAILED: ", err, time.Now())
-		return resp, err
+	stream := NewBrowserStream(ctx)
+	stream.method = method
+	call := &BrowserCall{
+		ID:           bc.NextCallID(),
+		Service:      method.Service,
+		Method:       method.Method,
+		Request:      requestData,
+		ResponseCh:   make(chan *CallResponse, 1),
+		StartTime:    time.Now(),
+		IsAsync:      method.IsAsync,
+		Context:      ctx,
+		Stream:       stream,
+		RequestType:  method.requestType(),
+		ResponseType: method.ResponseType,
 	}
-	fmt.Printf("DEBUG: QueueCall succeeded, got response data (len=%d): %s\n", len(responseData), string(responseData))
 
-	// Unmarshal the response
-	// Check if resp is already a proto.Message (if it's a pointer type)
-	// or if we need to take its address (if it's a value type)
-	var respMsg proto.Message
-	var isProtoMsg bool
+	// Streams wait for queue space until ctx is done; they have no timeout
+	dropped, hasDropped, err := bc.callQueue.Push(ctx, call, bc.closed, nil)
+	switch {
+	case errors.Is(err, errQueueStopped):
+		return nil, ErrBrowserChannelClosed
+	case err != nil:
+		return nil, err
+	}
+	if hasDropped {
+		bc.failDroppedCall(dropped)
+	}
+
+	// End the stream when the caller gives up on it or the channel closes
+	go func() {
+		select {
+		case <-stream.Done():
+		case <-ctx.Done():
+			bc.cancelCall(call.ID, ctx.Err().Error())
+			stream.Finish(ctx.Err())
+		case <-bc.closed:
+			bc.cleanupCall(call.ID)
+			stream.Finish(ErrBrowserChannelClosed)
+		}
+	}()
 
-	// Try resp directly first (for pointer types like *PromptResponse)
-	if respMsg, isProtoMsg = any(resp).(proto.Message); !isProtoMsg {
-		// Try &resp (for value types)
-		respMsg, isProtoMsg = any(&resp).(proto.Message)
+	bc.notifyDispatcher()
+	return stream, nil
+}
+
+// callBrowser marshals req, queues the call for JavaScript and unmarshals the response into reply.
+func (bc *BrowserServiceChannel) callBrowser(ctx context.Context, method BrowserMethod, req, reply proto.Message) error {
+	requestData, err := method.marshal(req)
+	if err != nil {
+		return fmt.Errorf("failed to marshal request: %w", err)
 	}
 
-	if !isProtoMsg {
-		fmt.Printf("DEBUG: respType=%T, resp=%+v\n", resp, resp)
-		fmt.Printf("DEBUG: responseData=%s\n", string(responseData))
-		return resp, fmt.Errorf("response is not a proto message (type: %T)", resp)
+	responseData, err := bc.queueBrowserCall(ctx, &BrowserCall{
+		Service:      method.Service,
+		Method:       method.Method,
+		Request:      requestData,
+		IsAsync:      method.IsAsync,
+		RequestType:  method.requestType(),
+		ResponseType: method.ResponseType,
+	}, method.attemptTimeout())
+	if err != nil {
+		return err
 	}
 
-	if err := marshaller.Unmarshal(responseData, respMsg, UnmarshalOptions{
-		DiscardUnknown: true,
-		AllowPartial:   true,
-	}); err != nil {
-		return resp, fmt.Errorf("failed to unmarshal response: %w", err)
+	if err := method.unmarshal(responseData, reply); err != nil {
+		return fmt.Errorf("failed to unmarshal response: %w", err)
 	}
+	return nil
+}
 
-	return resp, nil
+// CallBrowserService is a generic helper for calling synchronous browser services
+// The browser method should return a value directly (not a Promise)
+func CallBrowserService[TReq any, TResp any](channel *BrowserServiceChannel, ctx context.Context, serviceName, methodName string, req TReq) (TResp, error) {
+	return callBrowserService[TReq, TResp](channel, ctx, serviceName, methodName, req, false)
 }
 
 // CallBrowserServiceAsync is a generic helper for calling async browser services
 // The browser method returns a Promise and we need to handle it with a callback
 // This is necessary for browser APIs that are inherently async (fetch, IndexedDB, etc.)
 func CallBrowserServiceAsync[TReq any, TResp any](channel *BrowserServiceChannel, ctx context.Context, serviceName, methodName string, req TReq) (TResp, error) {
+	return callBrowserService[TReq, TResp](channel, ctx, serviceName, methodName, req, true)
+}
+
+// callBrowserService implements CallBrowserService and CallBrowserServiceAsync.
+// Calls go through the channel interceptors with FullMethod set to "/service/method".
+func callBrowserService[TReq any, TResp any](channel *BrowserServiceChannel, ctx context.Context, serviceName, methodName string, req TReq, isAsync bool) (TResp, error) {
 	var resp TResp
 
 	// If TResp is a pointer type, we need to create a new instance
@@ -506,44 +813,25 @@ func CallBrowserServiceAsync[TReq any, TResp any](channel *BrowserServiceChannel
 		resp = respValue.Interface().(TResp)
 	}
 
-	// For async methods, we need to tell the browser side to handle it as a Promise
-	// We'll add a special flag in the call to indicate async handling
-
-	// Marshal the request using the global marshaller
-	marshaller := GetGlobalMarshaller()
-
 	reqMsg, ok := any(req).(proto.Message)
 	if !ok {
 		return resp, fmt.Errorf("request is not a proto message")
 	}
 
-	requestData, err := marshaller.Marshal(reqMsg, MarshalOptions{
-		UseProtoNames:   false,
-		EmitUnpopulated: true,
-		UseEnumNumbers:  false,
-	})
-	if err != nil {
-		return resp, fmt.Errorf("failed to marshal request: %w", err)
-	}
-
-	// For async calls, we use a longer timeout since they may involve network operations
-	responseData, err := channel.QueueCallAsync(ctx, serviceName, methodName, requestData, 60*time.Second)
-	if err != nil {
-		return resp, err
-	}
-
-	// Unmarshal the response
-	respMsg, ok := any(&resp).(proto.Message)
+	// Try resp directly first (for pointer types like *PromptResponse),
+	// then its address (for value types)
+	respMsg, ok := any(resp).(proto.Message)
 	if !ok {
-		return resp, fmt.Errorf("response is not a proto message")
-	}
-
-	if err := marshaller.Unmarshal(responseData, respMsg, UnmarshalOptions{
-		DiscardUnknown: true,
-		AllowPartial:   true,
-	}); err != nil {
-		return resp, fmt.Errorf("failed to unmarshal response: %w", err)
+		if respMsg, ok = any(&resp).(proto.Message); !ok {
+			return resp, fmt.Errorf("response is not a proto message (type: %T)", resp)
+		}
 	}
 
-	return resp, nil
+	err := channel.Invoke(ctx, BrowserMethod{
+		FullMethod: "/" + serviceName + "/" + methodName,
+		Service:    serviceName,
+		Method:     methodName,
+		IsAsync:    isAsync,
+	}, reqMsg, respMsg)
+	return resp, err
 }
@@ -0,0 +1,44 @@
+//go:build !(js && wasm)
+
+package wasm
+
+import "sync"
+
+// Fake browser channels keyed by name, standing in for the JavaScript-backed channels
+// of js/wasm builds. Created lazily by GetNamedBrowserChannel() and removed by CloseNamedBrowserChannel().
+var (
+	fakeBrowserChannels  = make(map[string]*FakeBrowserChannel)
+	fakeBrowserChannelMu sync.Mutex
+)
+
+// GetBrowserChannel returns the unnamed fake channel (see GetNamedBrowserChannel).
+func GetBrowserChannel() *FakeBrowserChannel {
+	return GetNamedBrowserChannel("")
+}
+
+// GetNamedBrowserChannel returns the FakeBrowserChannel with the given name, creating it on
+// first call. Outside js/wasm, browser clients generated with host_browser_clients=true get
+// their channel here, so tests register fakes on it before creating the clients.
+func GetNamedBrowserChannel(name string) *FakeBrowserChannel {
+	fakeBrowserChannelMu.Lock()
+	defer fakeBrowserChannelMu.Unlock()
+	channel, exists := fakeBrowserChannels[name]
+	if !exists {
+		channel = NewFakeBrowserChannel()
+		fakeBrowserChannels[name] = channel
+	}
+	return channel
+}
+
+// CloseBrowserChannel forgets the unnamed fake channel (see CloseNamedBrowserChannel).
+func CloseBrowserChannel() {
+	CloseNamedBrowserChannel("")
+}
+
+// CloseNamedBrowserChannel forgets the fake channel with the given name, so the next
+// GetNamedBrowserChannel call returns one without fakes. Tests call it to start clean.
+func CloseNamedBrowserChannel(name string) {
+	fakeBrowserChannelMu.Lock()
+	defer fakeBrowserChannelMu.Unlock()
+	delete(fakeBrowserChannels, name)
+}
@@ -0,0 +1,137 @@
+// Package wasm provides runtime support for protoc-gen-go-wasmjs generated code.
+package wasm
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// Time a call to a browser-provided method (each attempt, when retried) waits for
+// JavaScript to pick it up and answer, unless its RetryPolicy sets AttemptTimeout
+const (
+	DefaultBrowserCallTimeout      = 30 * time.Second
+	DefaultAsyncBrowserCallTimeout = 60 * time.Second // Async methods may involve network operations
+)
+
+// BrowserChannel carries calls from generated browser clients to the implementations of
+// browser-provided services. In js/wasm builds it is a BrowserServiceChannel talking to
+// JavaScript; in host builds (and tests) it is a FakeBrowserChannel calling Go fakes.
+type BrowserChannel interface {
+	// Invoke calls a unary method through the channel interceptors followed by the
+	// given client interceptors, filling reply with the response.
+	Invoke(ctx context.Context, method BrowserMethod, req, reply proto.Message, interceptors ...BrowserClientInterceptor) error
+
+	// NewStream opens a server-streaming call, sending req to the implementation. The
+	// channel interceptors followed by the given client interceptors run around opening it.
+	NewStream(ctx context.Context, method BrowserMethod, req proto.Message, interceptors ...BrowserClientInterceptor) (*BrowserStream, error)
+}
+
+// BrowserMethod identifies a method of a browser-provided service.
+type BrowserMethod struct {
+	// FullMethod is the gRPC full method name passed to interceptors
+	// (e.g., "/browser.v1.BrowserAPI/GetLocalStorage").
+	FullMethod string
+
+	// Service is the service name registered in JavaScript (e.g., "BrowserAPI").
+	Service string
+
+	// Method is the method name called in JavaScript (e.g., "getLocalStorage").
+	Method string
+
+	// IsAsync indicates the JavaScript method returns a Promise.
+	IsAsync bool
+
+	// Retry, if set, retries failed calls. It runs inside all interceptors,
+	// so they see one call however many attempts it takes.
+	Retry *RetryPolicy
+
+	// Binary exchanges protobuf bytes with JavaScript instead of JSON (see MarshalBinary),
+	// which JavaScript decodes and encodes with the bundle's schemas. Browser clients
+	// generated with target=tinygo set it, as protojson is not available to TinyGo builds.
+	Binary bool
+
+	// RequestType and ResponseType are the full proto names of the method's messages
+	// (e.g., "browser.v1.StorageKeyRequest"), with which JavaScript decodes and encodes
+	// them when Binary is set.
+	RequestType  string
+	ResponseType string
+}
+
+// attemptTimeout returns how long an attempt of a unary call waits for JavaScript.
+func (m BrowserMethod) attemptTimeout() time.Duration {
+	if m.Retry != nil && m.Retry.AttemptTimeout > 0 {
+		return m.Retry.AttemptTimeout
+	}
+	if m.IsAsync {
+		return DefaultAsyncBrowserCallTimeout
+	}
+	return DefaultBrowserCallTimeout
+}
+
+// requestType returns the request type JavaScript decodes a binary call's request as;
+// empty for JSON calls.
+func (m BrowserMethod) requestType() string {
+	if !m.Binary {
+		return ""
+	}
+	return m.RequestType
+}
+
+// marshal serializes a request (or a stream message of a fake) for JavaScript.
+func (m BrowserMethod) marshal(msg proto.Message) ([]byte, error) {
+	if m.Binary {
+		return MarshalBinary(msg)
+	}
+	return GetMarshaller(msg).Marshal(msg, MarshalOptions{
+		UseProtoNames:   false,
+		EmitUnpopulated: true,
+		UseEnumNumbers:  false,
+	})
+}
+
+// unmarshal parses a response or stream message from JavaScript.
+func (m BrowserMethod) unmarshal(data []byte, msg proto.Message) error {
+	opts := UnmarshalOptions{
+		DiscardUnknown: true,
+		AllowPartial:   true,
+	}
+	if m.Binary {
+		return UnmarshalBinary(data, msg, opts)
+	}
+	return GetMarshaller(msg).Unmarshal(data, msg, opts)
+}
+
+// invokeBrowserMethod runs call through the channel interceptors, then the client
+// interceptors, then the method's retry policy.
+func invokeBrowserMethod(ctx context.Context, method BrowserMethod, req, reply proto.Message,
+	channelInterceptors, clientInterceptors []BrowserClientInterceptor,
+	call func(ctx context.Context, req, reply proto.Message) error) error {
+	chain := append(append([]BrowserClientInterceptor(nil), channelInterceptors...), clientInterceptors...)
+	if method.Retry != nil {
+		chain = append(chain, RetryInterceptor(*method.Retry))
+	}
+
+	invoker := func(ctx context.Context, _ string, req, reply proto.Message) error {
+		return call(ctx, req, reply)
+	}
+	return chainBrowserInterceptors(chain, invoker)(ctx, method.FullMethod, req, reply)
+}
+
+// openBrowserStream runs open through the channel interceptors, then the client
+// interceptors, which see the stream's request with a nil reply.
+func openBrowserStream(ctx context.Context, method BrowserMethod, req proto.Message,
+	channelInterceptors, clientInterceptors []BrowserClientInterceptor,
+	open func(ctx context.Context, req proto.Message) (*BrowserStream, error)) (*BrowserStream, error) {
+	var stream *BrowserStream
+	err := invokeBrowserMethod(ctx, method, req, nil, channelInterceptors, clientInterceptors, func(ctx context.Context, req, _ proto.Message) error {
+		var err error
+		stream, err = open(ctx, req)
+		return err
+	})
+	if err == nil && stream == nil {
+		return nil, Errorf(CodeInternal, "interceptors did not open the stream of browser method %s", method.FullMethod)
+	}
+	return stream, err
+}
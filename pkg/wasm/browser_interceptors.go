@@ -0,0 +1,36 @@
+// Package wasm provides runtime support for protoc-gen-go-wasmjs generated code.
+package wasm
+
+import (
+	"context"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// BrowserInvoker performs a call to a browser-provided service, filling reply
+// with the response. It is the browser counterpart of grpc.UnaryInvoker.
+type BrowserInvoker func(ctx context.Context, method string, req, reply proto.Message) error
+
+// BrowserClientInterceptor intercepts calls that leave WASM for a browser-provided
+// service, modelled on grpc.UnaryClientInterceptor. method is the gRPC full method
+// name (e.g., "/browser.v1.BrowserAPI/GetLocalStorage").
+//
+// Interceptors can log, measure, retry (by calling invoker again) or mock calls
+// (by filling reply without calling invoker).
+//
+// For server-streaming methods interceptors run around opening the stream, with a nil
+// reply; the streamed messages are not intercepted. The context an interceptor passes
+// to invoker becomes the stream's context, so it must outlive the interceptor.
+type BrowserClientInterceptor func(ctx context.Context, method string, req, reply proto.Message, invoker BrowserInvoker) error
+
+// chainBrowserInterceptors folds interceptors into a single invoker ending in invoker.
+// interceptors[0] is the outermost.
+func chainBrowserInterceptors(interceptors []BrowserClientInterceptor, invoker BrowserInvoker) BrowserInvoker {
+	for i := len(interceptors) - 1; i >= 0; i-- {
+		interceptor, next := interceptors[i], invoker
+		invoker = func(ctx context.Context, method string, req, reply proto.Message) error {
+			return interceptor(ctx, method, req, reply, next)
+		}
+	}
+	return invoker
+}
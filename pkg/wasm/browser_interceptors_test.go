@@ -0,0 +1,92 @@
+// Package wasm provides runtime support for protoc-gen-go-wasmjs generated code.
+package wasm
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+// TestChainBrowserInterceptors tests chaining of interceptors around browser service calls
+func TestChainBrowserInterceptors(t *testing.T) {
+	const method = "/browser.v1.BrowserAPI/GetLocalStorage"
+
+	t.Run("Runs interceptors outermost first", func(t *testing.T) {
+		var calls []string
+		record := func(name string) BrowserClientInterceptor {
+			return func(ctx context.Context, method string, req, reply proto.Message, invoker BrowserInvoker) error {
+				calls = append(calls, name+" "+method)
+				return invoker(ctx, method, req, reply)
+			}
+		}
+		invoker := func(ctx context.Context, method string, req, reply proto.Message) error {
+			calls = append(calls, "invoker")
+			reply.(*wrapperspb.StringValue).Value = "from browser"
+			return nil
+		}
+
+		reply := &wrapperspb.StringValue{}
+		err := chainBrowserInterceptors([]BrowserClientInterceptor{record("outer"), record("inner")}, invoker)(
+			context.Background(), method, wrapperspb.String("key"), reply)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		expected := []string{"outer " + method, "inner " + method, "invoker"}
+		if !reflect.DeepEqual(calls, expected) {
+			t.Errorf("Expected calls %v, got %v", expected, calls)
+		}
+		if reply.Value != "from browser" {
+			t.Errorf("Expected reply 'from browser', got %q", reply.Value)
+		}
+	})
+
+	t.Run("Interceptor can mock the call", func(t *testing.T) {
+		mock := func(ctx context.Context, method string, req, reply proto.Message, invoker BrowserInvoker) error {
+			reply.(*wrapperspb.StringValue).Value = "mocked"
+			return nil
+		}
+		invoker := func(ctx context.Context, method string, req, reply proto.Message) error {
+			return errors.New("browser should not be called")
+		}
+
+		reply := &wrapperspb.StringValue{}
+		if err := chainBrowserInterceptors([]BrowserClientInterceptor{mock}, invoker)(
+			context.Background(), method, wrapperspb.String("key"), reply); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if reply.Value != "mocked" {
+			t.Errorf("Expected reply 'mocked', got %q", reply.Value)
+		}
+	})
+
+	t.Run("Interceptor can retry the call", func(t *testing.T) {
+		attempts := 0
+		retry := func(ctx context.Context, method string, req, reply proto.Message, invoker BrowserInvoker) error {
+			err := invoker(ctx, method, req, reply)
+			if err != nil {
+				err = invoker(ctx, method, req, reply)
+			}
+			return err
+		}
+		invoker := func(ctx context.Context, method string, req, reply proto.Message) error {
+			attempts++
+			if attempts == 1 {
+				return errors.New("transient")
+			}
+			return nil
+		}
+
+		if err := chainBrowserInterceptors([]BrowserClientInterceptor{retry}, invoker)(
+			context.Background(), method, wrapperspb.String("key"), &wrapperspb.StringValue{}); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if attempts != 2 {
+			t.Errorf("Expected 2 attempts, got %d", attempts)
+		}
+	})
+}
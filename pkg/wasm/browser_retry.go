@@ -0,0 +1,149 @@
+// Package wasm provides runtime support for protoc-gen-go-wasmjs generated code.
+package wasm
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// Defaults applied to the zero fields of a RetryPolicy
+const (
+	DefaultRetryInitialBackoff    = 100 * time.Millisecond
+	DefaultRetryMaxBackoff        = 5 * time.Second
+	DefaultRetryBackoffMultiplier = 2.0
+)
+
+// DefaultRetryableCodes are the codes retried when a RetryPolicy lists none. Errors thrown
+// by JavaScript implementations carry no code and are classified as Unknown.
+var DefaultRetryableCodes = []Code{CodeUnknown, CodeDeadlineExceeded, CodeResourceExhausted, CodeUnavailable}
+
+var (
+	// ErrBrowserChannelClosed is returned by calls made through, or waiting on, a closed channel.
+	ErrBrowserChannelClosed = errors.New("browser channel closed")
+
+	// ErrBrowserCallTimeout is returned (wrapped) by calls JavaScript did not pick up or answer in time.
+	ErrBrowserCallTimeout = errors.New("browser call timeout")
+)
+
+// RetryPolicy retries failed calls to a browser-provided method with exponential backoff.
+// Generated clients apply the policy set with the method_retry and service_retry
+// annotations; each attempt is queued as a new call with its own call ID and timeout.
+//
+// Example:
+//
+//	err := channel.Invoke(ctx, wasm.BrowserMethod{
+//	    FullMethod: "/browser.v1.BrowserAPI/Fetch",
+//	    Service:    "BrowserAPI",
+//	    Method:     "fetch",
+//	    IsAsync:    true,
+//	    Retry:      &wasm.RetryPolicy{MaxAttempts: 3, RetryableCodes: []codes.Code{codes.Unknown}},
+//	}, req, resp)
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, including the first.
+	// Values below 2 disable retries.
+	MaxAttempts int
+
+	// InitialBackoff is the delay before the first retry (DefaultRetryInitialBackoff if zero).
+	InitialBackoff time.Duration
+
+	// MaxBackoff caps the delay between attempts (DefaultRetryMaxBackoff if zero).
+	MaxBackoff time.Duration
+
+	// BackoffMultiplier grows the delay after every retry (DefaultRetryBackoffMultiplier if zero).
+	BackoffMultiplier float64
+
+	// RetryableCodes are the error classes worth retrying (DefaultRetryableCodes if empty).
+	// Errors are classified with BrowserErrorCode.
+	RetryableCodes []Code
+
+	// AttemptTimeout is how long each attempt waits for JavaScript before failing with
+	// ErrBrowserCallTimeout (DefaultBrowserCallTimeout, or DefaultAsyncBrowserCallTimeout
+	// for async methods, if zero). It applies to policies set on BrowserMethod.Retry.
+	AttemptTimeout time.Duration
+}
+
+// Backoff returns the delay before the given retry (1 for the first retry).
+func (p *RetryPolicy) Backoff(retry int) time.Duration {
+	backoff := p.InitialBackoff
+	if backoff <= 0 {
+		backoff = DefaultRetryInitialBackoff
+	}
+	maxBackoff := p.MaxBackoff
+	if maxBackoff <= 0 {
+		maxBackoff = DefaultRetryMaxBackoff
+	}
+	multiplier := p.BackoffMultiplier
+	if multiplier <= 0 {
+		multiplier = DefaultRetryBackoffMultiplier
+	}
+
+	delay := float64(backoff)
+	for i := 1; i < retry && delay < float64(maxBackoff); i++ {
+		delay *= multiplier
+	}
+	if delay > float64(maxBackoff) {
+		return maxBackoff
+	}
+	return time.Duration(delay)
+}
+
+// IsRetryable reports whether err belongs to one of the policy's retryable error classes.
+func (p *RetryPolicy) IsRetryable(err error) bool {
+	retryableCodes := p.RetryableCodes
+	if len(retryableCodes) == 0 {
+		retryableCodes = DefaultRetryableCodes
+	}
+
+	code := BrowserErrorCode(err)
+	for _, retryable := range retryableCodes {
+		if code == retryable {
+			return true
+		}
+	}
+	return false
+}
+
+// BrowserErrorCode classifies an error returned by a browser service call: status errors
+// keep their code, timeouts are DeadlineExceeded, a closed channel or the caller's
+// cancellation is Canceled, and errors thrown by JavaScript are Unknown.
+func BrowserErrorCode(err error) Code {
+	if code, ok := errorCode(err); ok {
+		return code
+	}
+	switch {
+	case errors.Is(err, ErrBrowserCallTimeout), errors.Is(err, context.DeadlineExceeded):
+		return CodeDeadlineExceeded
+	case errors.Is(err, ErrBrowserChannelClosed), errors.Is(err, context.Canceled):
+		return CodeCanceled
+	default:
+		return CodeUnknown
+	}
+}
+
+// RetryInterceptor returns a client interceptor that retries calls failing with a
+// retryable error according to policy. It stops early when ctx is done.
+func RetryInterceptor(policy RetryPolicy) BrowserClientInterceptor {
+	return func(ctx context.Context, method string, req, reply proto.Message, invoker BrowserInvoker) error {
+		for attempt := 1; ; attempt++ {
+			err := invoker(ctx, method, req, reply)
+			if err == nil || attempt >= policy.MaxAttempts || !policy.IsRetryable(err) || ctx.Err() != nil {
+				return err
+			}
+
+			timer := time.NewTimer(policy.Backoff(attempt))
+			select {
+			case <-timer.C:
+			case <-ctx.Done():
+				timer.Stop()
+				return err
+			}
+			// Start every attempt from an empty reply (streams have none)
+			if reply != nil {
+				proto.Reset(reply)
+			}
+		}
+	}
+}
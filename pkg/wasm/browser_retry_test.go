@@ -0,0 +1,132 @@
+// Package wasm provides runtime support for protoc-gen-go-wasmjs generated code.
+package wasm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+// TestRetryPolicy tests backoff computation and classification of browser call errors
+func TestRetryPolicy(t *testing.T) {
+	t.Run("Backoff grows exponentially up to the cap", func(t *testing.T) {
+		policy := &RetryPolicy{InitialBackoff: 10 * time.Millisecond, MaxBackoff: 50 * time.Millisecond, BackoffMultiplier: 2}
+		expected := []time.Duration{10 * time.Millisecond, 20 * time.Millisecond, 40 * time.Millisecond, 50 * time.Millisecond, 50 * time.Millisecond}
+		for i, want := range expected {
+			if got := policy.Backoff(i + 1); got != want {
+				t.Errorf("Retry %d: expected %v, got %v", i+1, want, got)
+			}
+		}
+	})
+
+	t.Run("Zero fields use defaults", func(t *testing.T) {
+		policy := &RetryPolicy{}
+		if got := policy.Backoff(1); got != DefaultRetryInitialBackoff {
+			t.Errorf("Expected %v, got %v", DefaultRetryInitialBackoff, got)
+		}
+		if got := policy.Backoff(100); got != DefaultRetryMaxBackoff {
+			t.Errorf("Expected %v, got %v", DefaultRetryMaxBackoff, got)
+		}
+		if !policy.IsRetryable(errors.New("Failed to fetch")) {
+			t.Error("Expected JavaScript errors to be retryable by default")
+		}
+		if policy.IsRetryable(status.Error(codes.InvalidArgument, "bad request")) {
+			t.Error("Expected InvalidArgument not to be retryable by default")
+		}
+	})
+
+	t.Run("Attempt timeout overrides the call timeout", func(t *testing.T) {
+		cases := []struct {
+			method BrowserMethod
+			want   time.Duration
+		}{
+			{BrowserMethod{}, DefaultBrowserCallTimeout},
+			{BrowserMethod{IsAsync: true}, DefaultAsyncBrowserCallTimeout},
+			{BrowserMethod{IsAsync: true, Retry: &RetryPolicy{MaxAttempts: 3}}, DefaultAsyncBrowserCallTimeout},
+			{BrowserMethod{IsAsync: true, Retry: &RetryPolicy{MaxAttempts: 3, AttemptTimeout: time.Second}}, time.Second},
+		}
+		for _, c := range cases {
+			if got := c.method.attemptTimeout(); got != c.want {
+				t.Errorf("IsAsync=%v, Retry=%+v: expected %v, got %v", c.method.IsAsync, c.method.Retry, c.want, got)
+			}
+		}
+	})
+
+	t.Run("Classifies browser call errors", func(t *testing.T) {
+		cases := []struct {
+			err  error
+			code codes.Code
+		}{
+			{errors.New("Failed to fetch"), codes.Unknown},
+			{fmt.Errorf("%w after %v", ErrBrowserCallTimeout, time.Second), codes.DeadlineExceeded},
+			{context.DeadlineExceeded, codes.DeadlineExceeded},
+			{context.Canceled, codes.Canceled},
+			{ErrBrowserChannelClosed, codes.Canceled},
+			{ErrBrowserQueueFull, codes.ResourceExhausted},
+			{status.Error(codes.Unavailable, "offline"), codes.Unavailable},
+		}
+		for _, c := range cases {
+			if got := BrowserErrorCode(c.err); got != c.code {
+				t.Errorf("%v: expected %v, got %v", c.err, c.code, got)
+			}
+		}
+	})
+}
+
+// TestRetryInterceptor tests retrying of failed browser calls
+func TestRetryInterceptor(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond, RetryableCodes: []codes.Code{codes.Unavailable}}
+
+	call := func(ctx context.Context, interceptor BrowserClientInterceptor, errs ...error) (int, error) {
+		attempts := 0
+		invoker := func(ctx context.Context, method string, req, reply proto.Message) error {
+			attempts++
+			if attempts <= len(errs) {
+				return errs[attempts-1]
+			}
+			reply.(*wrapperspb.StringValue).Value = "ok"
+			return nil
+		}
+		err := interceptor(ctx, "/test.v1.Browser/Fetch", &wrapperspb.StringValue{}, &wrapperspb.StringValue{}, invoker)
+		return attempts, err
+	}
+	unavailable := status.Error(codes.Unavailable, "offline")
+
+	t.Run("Retries retryable errors until success", func(t *testing.T) {
+		attempts, err := call(context.Background(), RetryInterceptor(policy), unavailable, unavailable)
+		if err != nil || attempts != 3 {
+			t.Errorf("Expected success on attempt 3, got %d attempts (err: %v)", attempts, err)
+		}
+	})
+
+	t.Run("Stops after max attempts", func(t *testing.T) {
+		attempts, err := call(context.Background(), RetryInterceptor(policy), unavailable, unavailable, unavailable, unavailable)
+		if !errors.Is(err, unavailable) || attempts != 3 {
+			t.Errorf("Expected the last error after 3 attempts, got %d attempts (err: %v)", attempts, err)
+		}
+	})
+
+	t.Run("Does not retry other errors", func(t *testing.T) {
+		invalid := status.Error(codes.InvalidArgument, "bad request")
+		attempts, err := call(context.Background(), RetryInterceptor(policy), invalid)
+		if !errors.Is(err, invalid) || attempts != 1 {
+			t.Errorf("Expected a single attempt, got %d attempts (err: %v)", attempts, err)
+		}
+	})
+
+	t.Run("Stops when the context is done", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		attempts, err := call(ctx, RetryInterceptor(policy), unavailable, unavailable)
+		if !errors.Is(err, unavailable) || attempts != 1 {
+			t.Errorf("Expected a single attempt, got %d attempts (err: %v)", attempts, err)
+		}
+	})
+}
@@ -0,0 +1,140 @@
+// Package wasm provides runtime support for protoc-gen-go-wasmjs generated code.
+package wasm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// ErrBrowserStreamSendNotSupported is returned by SendMsg on a browser stream; browser-provided
+// streaming methods receive a single request, passed when the stream is opened.
+var ErrBrowserStreamSendNotSupported = errors.New("browser streams do not accept messages from Go")
+
+// BrowserStream receives the messages of a server-streaming method of a browser-provided
+// service. JavaScript pushes messages as its implementation yields or emits them, and the
+// stream ends when the implementation returns, throws or the stream's context is done.
+//
+// BrowserStream implements grpc.ClientStream, so generated clients wrap it in a
+// grpc.GenericClientStream (a GenericBrowserStream in TinyGo builds, which leave gRPC
+// out) to offer the familiar Recv loop:
+//
+//	stream, err := browserAPI.WatchPosition(ctx, &browserv1.WatchPositionRequest{})
+//	if err != nil {
+//	    return err
+//	}
+//	for {
+//	    position, err := stream.Recv()
+//	    if err == io.EOF {
+//	        break // JavaScript ended the stream
+//	    }
+//	    if err != nil {
+//	        return err
+//	    }
+//	    handlePosition(position)
+//	}
+type BrowserStream struct {
+	ctx      context.Context
+	messages *StreamQueue[[]byte]
+	method   BrowserMethod // Decodes the messages
+
+	mu   sync.Mutex
+	err  error
+	done chan struct{}
+	once sync.Once
+}
+
+// NewBrowserStream creates an open stream whose messages are received under ctx.
+// Browser channels create streams in BrowserServiceChannel.NewStream.
+func NewBrowserStream(ctx context.Context) *BrowserStream {
+	return &BrowserStream{
+		ctx:      ctx,
+		messages: NewStreamQueue[[]byte](),
+		done:     make(chan struct{}),
+	}
+}
+
+// Push adds a message sent by JavaScript. It never blocks, as it is called from
+// js.FuncOf handlers, and reports false once the stream has finished.
+func (s *BrowserStream) Push(data []byte) bool {
+	return s.messages.Push(data) == nil
+}
+
+// Finish ends the stream. Messages already pushed are still received, after which Recv
+// returns err, or io.EOF if err is nil. Only the first call has an effect.
+func (s *BrowserStream) Finish(err error) {
+	s.once.Do(func() {
+		s.mu.Lock()
+		s.err = err
+		s.mu.Unlock()
+		s.messages.Close()
+		close(s.done)
+	})
+}
+
+// Done returns a channel that is closed once the stream has finished.
+func (s *BrowserStream) Done() <-chan struct{} {
+	return s.done
+}
+
+// RecvMsg blocks until JavaScript sends the next message and unmarshals it into m.
+// It returns io.EOF once the stream ended normally, the error the stream finished with,
+// or the context's error once the stream's context is done.
+func (s *BrowserStream) RecvMsg(m any) error {
+	msg, ok := m.(proto.Message)
+	if !ok {
+		return fmt.Errorf("stream message is not a proto message (type: %T)", m)
+	}
+
+	data, err := s.messages.Recv(s.ctx)
+	if err == io.EOF {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if s.err != nil {
+			return s.err
+		}
+		return io.EOF
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := s.method.unmarshal(data, msg); err != nil {
+		return fmt.Errorf("failed to unmarshal stream message: %w", err)
+	}
+	return nil
+}
+
+// SendMsg is not supported; the request is sent when the stream is opened.
+func (s *BrowserStream) SendMsg(m any) error {
+	return ErrBrowserStreamSendNotSupported
+}
+
+// CloseSend has no effect; the request is sent when the stream is opened.
+func (s *BrowserStream) CloseSend() error {
+	return nil
+}
+
+// Context returns the stream's context; cancelling it aborts the JavaScript implementation.
+func (s *BrowserStream) Context() context.Context {
+	return s.ctx
+}
+
+// GenericBrowserStream adapts a BrowserStream to ServerStreamingClient, receiving
+// messages of type Res; the counterpart of grpc.GenericClientStream in TinyGo builds.
+type GenericBrowserStream[Res any] struct {
+	*BrowserStream
+}
+
+// Recv blocks until JavaScript sends the next message (see BrowserStream.RecvMsg).
+func (s *GenericBrowserStream[Res]) Recv() (*Res, error) {
+	m := new(Res)
+	if err := s.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
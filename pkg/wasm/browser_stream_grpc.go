@@ -0,0 +1,21 @@
+//go:build !tinygo
+
+package wasm
+
+import (
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// Header returns no metadata; browser streams carry none.
+func (s *BrowserStream) Header() (metadata.MD, error) {
+	return nil, nil
+}
+
+// Trailer returns no metadata; browser streams carry none.
+func (s *BrowserStream) Trailer() metadata.MD {
+	return nil
+}
+
+// Ensure BrowserStream implements grpc.ClientStream
+var _ grpc.ClientStream = (*BrowserStream)(nil)
@@ -0,0 +1,97 @@
+// Package wasm provides runtime support for protoc-gen-go-wasmjs generated code.
+package wasm
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+// TestBrowserStream tests receiving messages pushed by JavaScript to server-streaming browser calls
+func TestBrowserStream(t *testing.T) {
+	t.Run("Receives pushed messages then EOF", func(t *testing.T) {
+		stream := NewBrowserStream(context.Background())
+		stream.Push([]byte(`"first"`))
+		stream.Push([]byte(`"second"`))
+		stream.Finish(nil)
+
+		recv := &grpc.GenericClientStream[wrapperspb.StringValue, wrapperspb.StringValue]{ClientStream: stream}
+		for _, expected := range []string{"first", "second"} {
+			msg, err := recv.Recv()
+			if err != nil || msg.GetValue() != expected {
+				t.Fatalf("Expected %q, got %q (err: %v)", expected, msg.GetValue(), err)
+			}
+		}
+		if _, err := recv.Recv(); err != io.EOF {
+			t.Errorf("Expected io.EOF, got %v", err)
+		}
+		if stream.Push([]byte(`"late"`)) {
+			t.Error("Expected Push after Finish to fail")
+		}
+	})
+
+	t.Run("Receives through GenericBrowserStream", func(t *testing.T) {
+		stream := NewBrowserStream(context.Background())
+		stream.Push([]byte(`"only"`))
+		stream.Finish(nil)
+
+		var recv ServerStreamingClient[wrapperspb.StringValue] = &GenericBrowserStream[wrapperspb.StringValue]{BrowserStream: stream}
+		msg, err := recv.Recv()
+		if err != nil || msg.GetValue() != "only" {
+			t.Fatalf("Expected %q, got %q (err: %v)", "only", msg.GetValue(), err)
+		}
+		if _, err := recv.Recv(); err != io.EOF {
+			t.Errorf("Expected io.EOF, got %v", err)
+		}
+	})
+
+	t.Run("Ends with the error JavaScript reported", func(t *testing.T) {
+		stream := NewBrowserStream(context.Background())
+		stream.Push([]byte(`"only"`))
+		failure := errors.New("WebSocket closed")
+		stream.Finish(failure)
+
+		msg := &wrapperspb.StringValue{}
+		if err := stream.RecvMsg(msg); err != nil || msg.GetValue() != "only" {
+			t.Fatalf("Expected queued message before the error, got %q (err: %v)", msg.GetValue(), err)
+		}
+		if err := stream.RecvMsg(msg); !errors.Is(err, failure) {
+			t.Errorf("Expected %v, got %v", failure, err)
+		}
+	})
+
+	t.Run("Recv waits for a later push", func(t *testing.T) {
+		stream := NewBrowserStream(context.Background())
+		go func() {
+			time.Sleep(10 * time.Millisecond)
+			stream.Push([]byte(`"later"`))
+		}()
+
+		msg := &wrapperspb.StringValue{}
+		if err := stream.RecvMsg(msg); err != nil || msg.GetValue() != "later" {
+			t.Errorf("Expected later, got %q (err: %v)", msg.GetValue(), err)
+		}
+	})
+
+	t.Run("Recv stops when the context is done", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		stream := NewBrowserStream(ctx)
+		cancel()
+
+		if err := stream.RecvMsg(&wrapperspb.StringValue{}); !errors.Is(err, context.Canceled) {
+			t.Errorf("Expected context.Canceled, got %v", err)
+		}
+	})
+
+	t.Run("Sending is not supported", func(t *testing.T) {
+		stream := NewBrowserStream(context.Background())
+		if err := stream.SendMsg(&wrapperspb.StringValue{}); !errors.Is(err, ErrBrowserStreamSendNotSupported) {
+			t.Errorf("Expected ErrBrowserStreamSendNotSupported, got %v", err)
+		}
+	})
+}
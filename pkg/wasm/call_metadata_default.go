@@ -0,0 +1,73 @@
+//go:build js && wasm && !tinygo
+
+package wasm
+
+import (
+	"context"
+	"syscall/js"
+
+	"google.golang.org/grpc/metadata"
+)
+
+// callMetadataContext attaches the { headers } call option as incoming metadata and a
+// collector delivering the call's header and trailer metadata to { onHeader } and
+// { onTrailer } to ctx.
+func callMetadataContext(ctx context.Context, options js.Value, method string) context.Context {
+	callMetadata := NewCallMetadata(method, metadataCallback(options, "onHeader"), metadataCallback(options, "onTrailer"))
+	return NewCallMetadataContext(ctx, metadataFromJS(options), callMetadata)
+}
+
+// metadataFromJS converts the { headers } call option into gRPC metadata.
+// Header values may be strings or arrays of strings; keys are lowercased.
+func metadataFromJS(options js.Value) metadata.MD {
+	md := metadata.MD{}
+	if options.Type() != js.TypeObject {
+		return md
+	}
+	headers := options.Get("headers")
+	if headers.Type() != js.TypeObject {
+		return md
+	}
+
+	keys := js.Global().Get("Object").Call("keys", headers)
+	for i := 0; i < keys.Length(); i++ {
+		key := keys.Index(i).String()
+		value := headers.Get(key)
+		if js.Global().Get("Array").Call("isArray", value).Bool() {
+			for j := 0; j < value.Length(); j++ {
+				md.Append(key, value.Index(j).String())
+			}
+		} else {
+			md.Append(key, value.String())
+		}
+	}
+	return md
+}
+
+// metadataToJS converts gRPC metadata into a JavaScript object of string arrays
+func metadataToJS(md metadata.MD) js.Value {
+	jsMetadata := js.Global().Get("Object").New()
+	for key, values := range md {
+		jsValues := js.Global().Get("Array").New()
+		for _, value := range values {
+			jsValues.Call("push", value)
+		}
+		jsMetadata.Set(key, jsValues)
+	}
+	return jsMetadata
+}
+
+// metadataCallback returns a function invoking the named metadata callback of the
+// call options, or nil if the caller did not pass one.
+func metadataCallback(options js.Value, name string) func(metadata.MD) {
+	if options.Type() != js.TypeObject {
+		return nil
+	}
+	callback := options.Get(name)
+	if callback.Type() != js.TypeFunction {
+		return nil
+	}
+	return func(md metadata.MD) {
+		callback.Invoke(metadataToJS(md))
+	}
+}
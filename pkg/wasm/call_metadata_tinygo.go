@@ -0,0 +1,14 @@
+//go:build js && wasm && tinygo
+
+package wasm
+
+import (
+	"context"
+	"syscall/js"
+)
+
+// callMetadataContext returns ctx unchanged: TinyGo builds leave gRPC metadata out,
+// so the { headers }, { onHeader } and { onTrailer } call options are ignored.
+func callMetadataContext(ctx context.Context, options js.Value, method string) context.Context {
+	return ctx
+}
@@ -0,0 +1,217 @@
+// Package wasm provides runtime support for protoc-gen-go-wasmjs generated code.
+package wasm
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// DefaultCallQueueCapacity is the capacity of a browser channel's call queue unless
+// changed with SetQueueOptions.
+const DefaultCallQueueCapacity = 100
+
+// QueueOverflowPolicy decides what happens to a call queued while the queue is full.
+type QueueOverflowPolicy int
+
+const (
+	// QueueOverflowBlock waits for space until the caller's context is done,
+	// the call times out or the channel is closed (the default).
+	QueueOverflowBlock QueueOverflowPolicy = iota
+
+	// QueueOverflowFailFast fails the new call with ErrBrowserQueueFull.
+	QueueOverflowFailFast
+
+	// QueueOverflowDropOldest evicts the oldest queued call, which fails with
+	// ErrBrowserCallDropped, to make room for the new one.
+	QueueOverflowDropOldest
+)
+
+// String returns the name of the policy
+func (p QueueOverflowPolicy) String() string {
+	switch p {
+	case QueueOverflowBlock:
+		return "block"
+	case QueueOverflowFailFast:
+		return "fail_fast"
+	case QueueOverflowDropOldest:
+		return "drop_oldest"
+	default:
+		return "unknown"
+	}
+}
+
+// QueueOptions configures the capacity and overflow policy of a call queue.
+type QueueOptions struct {
+	// Capacity is the maximum number of calls waiting to be picked up.
+	// Values below 1 mean DefaultCallQueueCapacity.
+	Capacity int
+
+	// OverflowPolicy decides what happens to calls queued while the queue is full.
+	OverflowPolicy QueueOverflowPolicy
+}
+
+// QueueStats reports the state of a call queue and counts of what happened to calls queued on it.
+type QueueStats struct {
+	// Length is the number of calls waiting to be picked up by JavaScript
+	Length int
+	// Capacity is the maximum number of waiting calls
+	Capacity int
+	// OverflowPolicy is the policy applied when the queue is full
+	OverflowPolicy QueueOverflowPolicy
+	// Pending is the number of calls picked up by JavaScript and waiting for a response
+	// (filled in by BrowserServiceChannel.QueueStats)
+	Pending int
+
+	// Enqueued counts calls added to the queue
+	Enqueued uint64
+	// Blocked counts calls that had to wait for space before being added
+	Blocked uint64
+	// Rejected counts calls that failed because the queue was full
+	Rejected uint64
+	// Dropped counts queued calls evicted to make room for newer ones
+	Dropped uint64
+}
+
+var (
+	// ErrBrowserQueueFull is returned, with code RESOURCE_EXHAUSTED, for calls made while
+	// the queue is full under QueueOverflowFailFast.
+	ErrBrowserQueueFull = Errorf(CodeResourceExhausted, "browser call queue is full")
+
+	// ErrBrowserCallDropped is returned, with code RESOURCE_EXHAUSTED, for queued calls
+	// evicted under QueueOverflowDropOldest.
+	ErrBrowserCallDropped = Errorf(CodeResourceExhausted, "browser call dropped from full queue")
+
+	// errQueueStopped and errQueueTimeout report why a blocked Push gave up
+	errQueueStopped = errors.New("call queue stopped")
+	errQueueTimeout = errors.New("timeout waiting for space in call queue")
+)
+
+// CallQueue is a bounded FIFO of calls waiting to be picked up by JavaScript.
+// Push applies the queue's overflow policy when it is full; Pop never blocks, since it
+// is called from js.FuncOf handlers.
+type CallQueue[T any] struct {
+	mu      sync.Mutex
+	items   []T
+	options QueueOptions
+	stats   QueueStats
+	space   chan struct{} // Closed (and replaced) whenever an item leaves the queue
+}
+
+// NewCallQueue creates an empty call queue.
+func NewCallQueue[T any](options QueueOptions) *CallQueue[T] {
+	q := &CallQueue[T]{space: make(chan struct{})}
+	q.SetOptions(options)
+	return q
+}
+
+// SetOptions changes the capacity and overflow policy. Lowering the capacity below the
+// current length keeps the queued items; new ones are subject to the policy until it drains.
+func (q *CallQueue[T]) SetOptions(options QueueOptions) {
+	if options.Capacity < 1 {
+		options.Capacity = DefaultCallQueueCapacity
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.options = options
+	// Blocked pushes re-check against the new capacity
+	q.signalSpace()
+}
+
+// Push adds an item to the queue. When the queue is full, it applies the overflow policy:
+// it blocks until space frees up (returning ctx.Err(), errQueueStopped once stop is closed,
+// or errQueueTimeout once timeout fires), fails with ErrBrowserQueueFull, or evicts the
+// oldest item and returns it as dropped so the caller can fail it.
+func (q *CallQueue[T]) Push(ctx context.Context, item T, stop <-chan struct{}, timeout <-chan time.Time) (dropped T, hasDropped bool, err error) {
+	blocked := false
+	for {
+		q.mu.Lock()
+		if len(q.items) < q.options.Capacity {
+			q.items = append(q.items, item)
+			q.stats.Enqueued++
+			if blocked {
+				q.stats.Blocked++
+			}
+			q.mu.Unlock()
+			return dropped, false, nil
+		}
+
+		switch q.options.OverflowPolicy {
+		case QueueOverflowFailFast:
+			q.stats.Rejected++
+			q.mu.Unlock()
+			return dropped, false, ErrBrowserQueueFull
+		case QueueOverflowDropOldest:
+			dropped = q.items[0]
+			q.removeFirst()
+			q.items = append(q.items, item)
+			q.stats.Enqueued++
+			q.stats.Dropped++
+			q.mu.Unlock()
+			return dropped, true, nil
+		}
+
+		space := q.space
+		q.mu.Unlock()
+		blocked = true
+
+		select {
+		case <-space:
+		case <-stop:
+			return dropped, false, errQueueStopped
+		case <-ctx.Done():
+			return dropped, false, ctx.Err()
+		case <-timeout:
+			return dropped, false, errQueueTimeout
+		}
+	}
+}
+
+// Pop removes and returns the oldest item without blocking.
+func (q *CallQueue[T]) Pop() (T, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	var zero T
+	if len(q.items) == 0 {
+		return zero, false
+	}
+	item := q.items[0]
+	q.removeFirst()
+	q.signalSpace()
+	return item, true
+}
+
+// Len returns the number of queued items.
+func (q *CallQueue[T]) Len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.items)
+}
+
+// Stats returns the queue's current length, options and counters.
+func (q *CallQueue[T]) Stats() QueueStats {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	stats := q.stats
+	stats.Length = len(q.items)
+	stats.Capacity = q.options.Capacity
+	stats.OverflowPolicy = q.options.OverflowPolicy
+	return stats
+}
+
+// removeFirst drops the oldest item. Must be called with q.mu held.
+func (q *CallQueue[T]) removeFirst() {
+	var zero T
+	q.items[0] = zero
+	q.items = q.items[1:]
+}
+
+// signalSpace wakes up every blocked Push. Must be called with q.mu held.
+func (q *CallQueue[T]) signalSpace() {
+	close(q.space)
+	q.space = make(chan struct{})
+}
@@ -0,0 +1,140 @@
+// Package wasm provides runtime support for protoc-gen-go-wasmjs generated code.
+package wasm
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// TestCallQueue tests the bounded queue of browser calls and its overflow policies
+func TestCallQueue(t *testing.T) {
+	push := func(q *CallQueue[string], item string) (string, bool, error) {
+		return q.Push(context.Background(), item, nil, nil)
+	}
+
+	t.Run("Pops in FIFO order without blocking", func(t *testing.T) {
+		queue := NewCallQueue[string](QueueOptions{})
+		push(queue, "a")
+		push(queue, "b")
+
+		for _, expected := range []string{"a", "b"} {
+			if item, ok := queue.Pop(); !ok || item != expected {
+				t.Fatalf("Expected %q, got %q (ok: %v)", expected, item, ok)
+			}
+		}
+		if _, ok := queue.Pop(); ok {
+			t.Error("Expected empty queue")
+		}
+		if stats := queue.Stats(); stats.Capacity != DefaultCallQueueCapacity || stats.Enqueued != 2 {
+			t.Errorf("Unexpected stats: %+v", stats)
+		}
+	})
+
+	t.Run("Fail fast rejects with RESOURCE_EXHAUSTED", func(t *testing.T) {
+		queue := NewCallQueue[string](QueueOptions{Capacity: 1, OverflowPolicy: QueueOverflowFailFast})
+		push(queue, "a")
+
+		_, _, err := push(queue, "b")
+		if !errors.Is(err, ErrBrowserQueueFull) || status.Code(err) != codes.ResourceExhausted {
+			t.Fatalf("Expected ErrBrowserQueueFull, got %v", err)
+		}
+		if stats := queue.Stats(); stats.Length != 1 || stats.Rejected != 1 {
+			t.Errorf("Unexpected stats: %+v", stats)
+		}
+	})
+
+	t.Run("Drop oldest evicts the oldest item", func(t *testing.T) {
+		queue := NewCallQueue[string](QueueOptions{Capacity: 2, OverflowPolicy: QueueOverflowDropOldest})
+		push(queue, "a")
+		push(queue, "b")
+
+		dropped, hasDropped, err := push(queue, "c")
+		if err != nil || !hasDropped || dropped != "a" {
+			t.Fatalf("Expected a to be dropped, got %q (dropped: %v, err: %v)", dropped, hasDropped, err)
+		}
+		if item, _ := queue.Pop(); item != "b" {
+			t.Errorf("Expected b, got %q", item)
+		}
+		if stats := queue.Stats(); stats.Dropped != 1 || stats.Enqueued != 3 {
+			t.Errorf("Unexpected stats: %+v", stats)
+		}
+	})
+
+	t.Run("Block waits for space", func(t *testing.T) {
+		queue := NewCallQueue[string](QueueOptions{Capacity: 1})
+		push(queue, "a")
+
+		done := make(chan error, 1)
+		go func() {
+			_, _, err := push(queue, "b")
+			done <- err
+		}()
+
+		select {
+		case err := <-done:
+			t.Fatalf("Push returned before space was available: %v", err)
+		case <-time.After(50 * time.Millisecond):
+		}
+
+		queue.Pop()
+		select {
+		case err := <-done:
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("Push did not resume after Pop")
+		}
+		if stats := queue.Stats(); stats.Blocked != 1 || stats.Length != 1 {
+			t.Errorf("Unexpected stats: %+v", stats)
+		}
+	})
+
+	t.Run("Block gives up on context, stop and timeout", func(t *testing.T) {
+		queue := NewCallQueue[string](QueueOptions{Capacity: 1})
+		push(queue, "a")
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		if _, _, err := queue.Push(ctx, "b", nil, nil); !errors.Is(err, context.Canceled) {
+			t.Errorf("Expected context.Canceled, got %v", err)
+		}
+
+		stop := make(chan struct{})
+		close(stop)
+		if _, _, err := queue.Push(context.Background(), "b", stop, nil); !errors.Is(err, errQueueStopped) {
+			t.Errorf("Expected errQueueStopped, got %v", err)
+		}
+
+		if _, _, err := queue.Push(context.Background(), "b", nil, time.After(10*time.Millisecond)); !errors.Is(err, errQueueTimeout) {
+			t.Errorf("Expected errQueueTimeout, got %v", err)
+		}
+	})
+
+	t.Run("Raising the capacity unblocks waiting pushes", func(t *testing.T) {
+		queue := NewCallQueue[string](QueueOptions{Capacity: 1})
+		push(queue, "a")
+
+		done := make(chan error, 1)
+		go func() {
+			_, _, err := push(queue, "b")
+			done <- err
+		}()
+		time.Sleep(10 * time.Millisecond)
+
+		queue.SetOptions(QueueOptions{Capacity: 2})
+		select {
+		case err := <-done:
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("Push did not resume after raising the capacity")
+		}
+	})
+}
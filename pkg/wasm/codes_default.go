@@ -0,0 +1,47 @@
+//go:build !tinygo
+
+package wasm
+
+import (
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Code is a gRPC status code. It is codes.Code in standard builds; TinyGo builds, which
+// leave gRPC out, define their own with the same values.
+type Code = codes.Code
+
+// The gRPC status codes, usable in both standard and TinyGo builds
+const (
+	CodeOK                 = codes.OK
+	CodeCanceled           = codes.Canceled
+	CodeUnknown            = codes.Unknown
+	CodeInvalidArgument    = codes.InvalidArgument
+	CodeDeadlineExceeded   = codes.DeadlineExceeded
+	CodeNotFound           = codes.NotFound
+	CodeAlreadyExists      = codes.AlreadyExists
+	CodePermissionDenied   = codes.PermissionDenied
+	CodeResourceExhausted  = codes.ResourceExhausted
+	CodeFailedPrecondition = codes.FailedPrecondition
+	CodeAborted            = codes.Aborted
+	CodeOutOfRange         = codes.OutOfRange
+	CodeUnimplemented      = codes.Unimplemented
+	CodeInternal           = codes.Internal
+	CodeUnavailable        = codes.Unavailable
+	CodeDataLoss           = codes.DataLoss
+	CodeUnauthenticated    = codes.Unauthenticated
+)
+
+// Errorf returns an error carrying code and a formatted message, which generated code
+// passes to JavaScript as a structured status. In standard builds it is status.Errorf.
+func Errorf(code Code, format string, args ...any) error {
+	return status.Errorf(code, format, args...)
+}
+
+// errorCode returns the code of a status error, reporting false for other errors.
+func errorCode(err error) (Code, bool) {
+	if st, ok := status.FromError(err); ok {
+		return st.Code(), true
+	}
+	return CodeUnknown, false
+}
@@ -0,0 +1,76 @@
+//go:build tinygo
+
+package wasm
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+// Code is a gRPC status code. TinyGo builds leave gRPC out, so it mirrors codes.Code,
+// with the same values and names.
+type Code uint32
+
+// The gRPC status codes, usable in both standard and TinyGo builds
+const (
+	CodeOK Code = iota
+	CodeCanceled
+	CodeUnknown
+	CodeInvalidArgument
+	CodeDeadlineExceeded
+	CodeNotFound
+	CodeAlreadyExists
+	CodePermissionDenied
+	CodeResourceExhausted
+	CodeFailedPrecondition
+	CodeAborted
+	CodeOutOfRange
+	CodeUnimplemented
+	CodeInternal
+	CodeUnavailable
+	CodeDataLoss
+	CodeUnauthenticated
+)
+
+// codeNames are the names codes.Code.String returns
+var codeNames = [...]string{
+	"OK", "Canceled", "Unknown", "InvalidArgument", "DeadlineExceeded", "NotFound",
+	"AlreadyExists", "PermissionDenied", "ResourceExhausted", "FailedPrecondition",
+	"Aborted", "OutOfRange", "Unimplemented", "Internal", "Unavailable", "DataLoss",
+	"Unauthenticated",
+}
+
+// String returns the name of the code, as codes.Code does.
+func (c Code) String() string {
+	if int(c) < len(codeNames) {
+		return codeNames[c]
+	}
+	return "Code(" + strconv.FormatUint(uint64(c), 10) + ")"
+}
+
+// statusError is the error Errorf returns in TinyGo builds.
+type statusError struct {
+	code    Code
+	message string
+}
+
+// Error formats the error as a gRPC status error does.
+func (e *statusError) Error() string {
+	return fmt.Sprintf("rpc error: code = %s desc = %s", e.code, e.message)
+}
+
+// Errorf returns an error carrying code and a formatted message, which generated code
+// passes to JavaScript as a structured status. In standard builds it is status.Errorf.
+func Errorf(code Code, format string, args ...any) error {
+	return &statusError{code: code, message: fmt.Sprintf(format, args...)}
+}
+
+// errorCode returns the code of a status error, reporting false for other errors.
+func errorCode(err error) (Code, bool) {
+	var st *statusError
+	if errors.As(err, &st) {
+		return st.code, true
+	}
+	return CodeUnknown, false
+}
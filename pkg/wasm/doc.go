@@ -66,7 +66,7 @@ The browser service channel implements a sophisticated async communication syste
 	4. Process response or error
 
 	JavaScript Side:
-	1. Poll __wasmGetNextBrowserCall()
+	1. When WASM invokes __wasmBrowserCallDispatcher(), drain calls with __wasmGetNextBrowserCall()
 	2. Execute browser service method
 	3. Call __wasmHandleBrowserResponse(id, data) or __wasmHandleBrowserError(id, error)
 	4. WASM receives response and unblocks
@@ -95,8 +95,8 @@ Generated WASM Code:
 	    req := &StorageKeyRequest{Key: key}
 	    reqData, _ := proto.Marshal(req)
 
-	    // Get browser channel
-	    browserChannel := wasm.GetBrowserChannel()
+	    // Get the browser channel of the module (named after its JS namespace)
+	    browserChannel := wasm.GetNamedBrowserChannel("myApp")
 
 	    // Create browser call
 	    call := &wasm.BrowserCall{
@@ -137,7 +137,7 @@ JavaScript Implementation:
 	    }
 	});
 
-	// Browser service manager automatically polls for calls
+	// Browser service manager is notified by WASM when calls are queued
 	// and responds via __wasmHandleBrowserResponse()
 
 # Async Method Support
@@ -183,7 +183,7 @@ The package provides helpers for protobuf serialization in WASM:
 The BrowserServiceChannel is thread-safe:
 
   - Uses sync.RWMutex for concurrent access to pending calls map
-  - Channel-based communication for FIFO ordering
+  - Bounded FIFO call queue (CallQueue) with a configurable overflow policy
   - Atomic operations for call ID generation
   - Safe cleanup of expired calls
 
@@ -192,6 +192,7 @@ The BrowserServiceChannel is thread-safe:
 The package defines clear error types:
 
   - Context errors (timeout, cancellation)
+  - Queue overflow errors (ErrBrowserQueueFull, ErrBrowserCallDropped) with code RESOURCE_EXHAUSTED
   - Browser service errors (service not found, method error)
   - Serialization errors (protobuf marshaling/unmarshaling)
 
@@ -216,6 +217,11 @@ Run tests:
 
 	GOOS=js GOARCH=wasm go test ./pkg/wasm/...
 
+Generated code can be tested on the host as well. DispatchUnary, DispatchServerStream
+and DispatchClientStream run exported methods the way the WASM exports do, on JSON or
+protobuf bytes instead of JavaScript values; generating with host_exports=true emits a
+dispatcher built on them, reporting failures as *ExportError.
+
 # Debugging
 
 Enable debug logging:
@@ -0,0 +1,251 @@
+//go:build !tinygo
+
+package wasm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/proto"
+)
+
+// ErrUnknownExport is returned (wrapped) by generated dispatchers for names that are not exported.
+var ErrUnknownExport = errors.New("unknown export")
+
+// ExportError is the error a dispatcher returns where the WASM export would return
+// { success: false, message, error }: Message is the envelope's message and Status its
+// structured error, which is nil for failures that carry none (such as unparseable requests).
+// It unwraps to the service's error, so status.Code and errors.Is see through it.
+type ExportError struct {
+	Message string
+	Status  *ErrorStatus
+	Err     error
+}
+
+// Error returns the envelope's message.
+func (e *ExportError) Error() string {
+	return e.Message
+}
+
+// Unwrap returns the error the service method returned, if any.
+func (e *ExportError) Unwrap() error {
+	return e.Err
+}
+
+// newExportError builds the error envelope for err returned by a service, as createJSErrorResponse does.
+func newExportError(message string, err error) *ExportError {
+	return &ExportError{Message: message, Status: NewErrorStatus(err), Err: err}
+}
+
+// ExportCodec converts messages to and from the bytes a dispatcher exchanges, with the
+// wire format and JSON options of the generated exports: protobuf bytes when Binary is
+// set, JSON (as JavaScript would pass and receive it) otherwise.
+// Unmarshal applies to requests in both wire formats; Marshal only to JSON.
+type ExportCodec struct {
+	Binary    bool
+	Unmarshal UnmarshalOptions
+	Marshal   MarshalOptions
+}
+
+// decode parses a request the way the export wrappers parse arguments from JavaScript
+func (c ExportCodec) decode(data []byte, m proto.Message) error {
+	if c.Binary {
+		return proto.UnmarshalOptions{DiscardUnknown: c.Unmarshal.DiscardUnknown, AllowPartial: c.Unmarshal.AllowPartial}.Unmarshal(data, m)
+	}
+	return GetMarshaller(m).Unmarshal(data, m, c.Unmarshal)
+}
+
+// encode serializes a response the way the export wrappers return it to JavaScript
+func (c ExportCodec) encode(m proto.Message) ([]byte, error) {
+	if c.Binary {
+		return proto.Marshal(m)
+	}
+	return GetMarshaller(m).Marshal(m, c.Marshal)
+}
+
+// ExportCallContext creates the context for a dispatched call of method (the gRPC full
+// method name), as CallContext does for calls from JavaScript: it applies the method's
+// default timeout (0 means none) and turns the caller's outgoing metadata into the
+// method's incoming metadata. Header and trailer metadata set by the method is accepted
+// and discarded.
+func ExportCallContext(ctx context.Context, method string, defaultTimeout time.Duration) (context.Context, context.CancelFunc) {
+	var cancel context.CancelFunc
+	if defaultTimeout <= 0 {
+		ctx, cancel = context.WithCancel(ctx)
+	} else {
+		ctx, cancel = context.WithTimeout(ctx, defaultTimeout)
+	}
+
+	incoming, _ := metadata.FromOutgoingContext(ctx)
+	return NewCallMetadataContext(ctx, incoming.Copy(), NewCallMetadata(method, nil, nil)), cancel
+}
+
+// DispatchUnary runs a unary (or async) method for a generated dispatcher with the same
+// steps as its WASM export: parse the request, call the method through the unary
+// interceptors, recover panics, and serialize the response or build the error envelope.
+func DispatchUnary[Req any, PReq interface {
+	*Req
+	proto.Message
+}, Resp proto.Message](
+	ctx context.Context,
+	codec ExportCodec,
+	request []byte,
+	info *grpc.UnaryServerInfo,
+	interceptors []grpc.UnaryServerInterceptor,
+	handler func(context.Context, PReq) (Resp, error),
+) (response []byte, err error) {
+	defer RecoverPanic(info.FullMethod, func(panicErr error) {
+		response, err = nil, newExportError(panicErr.Error(), panicErr)
+	})
+
+	req := PReq(new(Req))
+	if err := codec.decode(request, req); err != nil {
+		return nil, &ExportError{Message: fmt.Sprintf("Failed to parse request: %v", err)}
+	}
+
+	resp, err := InvokeUnary(ctx, req, info, interceptors, handler)
+	FinishCall(ctx)
+	if err != nil {
+		return nil, newExportError(fmt.Sprintf("Service call failed: %v", err), err)
+	}
+
+	data, err := codec.encode(resp)
+	if err != nil {
+		return nil, &ExportError{Message: fmt.Sprintf("Failed to marshal response: %v", err)}
+	}
+	return data, nil
+}
+
+// DispatchServerStream runs a server-streaming method for a generated dispatcher. Each
+// response is serialized and passed to onMessage; an error from onMessage ends the
+// stream, as a JavaScript callback returning false does.
+func DispatchServerStream[Req any, PReq interface {
+	*Req
+	proto.Message
+}](
+	ctx context.Context,
+	codec ExportCodec,
+	request []byte,
+	srv any,
+	info *grpc.StreamServerInfo,
+	interceptors []grpc.StreamServerInterceptor,
+	handler func(PReq, grpc.ServerStream) error,
+	onMessage func(response []byte) error,
+) error {
+	req := PReq(new(Req))
+	if err := codec.decode(request, req); err != nil {
+		return &ExportError{Message: fmt.Sprintf("Failed to parse request: %v", err)}
+	}
+
+	stream := &exportStream{ctx: ctx, codec: codec, onMessage: onMessage}
+	return runExportStream(srv, stream, info, interceptors, func(srv any, stream grpc.ServerStream) error {
+		return handler(req, stream)
+	})
+}
+
+// DispatchClientStream runs a client-streaming or bidirectional streaming method for a
+// generated dispatcher. The requests are queued up front and the send side closed, so
+// the method receives io.EOF after the last one; responses go to onMessage.
+func DispatchClientStream[Req any, PReq interface {
+	*Req
+	proto.Message
+}](
+	ctx context.Context,
+	codec ExportCodec,
+	requests [][]byte,
+	srv any,
+	info *grpc.StreamServerInfo,
+	interceptors []grpc.StreamServerInterceptor,
+	handler func(grpc.ServerStream) error,
+	onMessage func(response []byte) error,
+) error {
+	queue := NewStreamQueue[proto.Message]()
+	for _, request := range requests {
+		req := PReq(new(Req))
+		if err := codec.decode(request, req); err != nil {
+			return &ExportError{Message: fmt.Sprintf("Failed to parse request: %v", err)}
+		}
+		queue.Push(req)
+	}
+	queue.Close()
+
+	stream := &exportStream{ctx: ctx, codec: codec, onMessage: onMessage, requests: queue}
+	return runExportStream(srv, stream, info, interceptors, func(srv any, stream grpc.ServerStream) error {
+		return handler(stream)
+	})
+}
+
+// runExportStream calls a streaming method through the stream interceptors, recovering
+// panics and wrapping its error in the envelope the exports pass to the callback.
+func runExportStream(srv any, stream *exportStream, info *grpc.StreamServerInfo, interceptors []grpc.StreamServerInterceptor, handler grpc.StreamHandler) (err error) {
+	defer RecoverPanic(info.FullMethod, func(panicErr error) {
+		err = newExportError(panicErr.Error(), panicErr)
+	})
+
+	err = InvokeStream(srv, stream, info, interceptors, handler)
+	FinishCall(stream.ctx)
+	if err != nil {
+		return newExportError(err.Error(), err)
+	}
+	return nil
+}
+
+// exportStream is the grpc.ServerStream dispatchers hand to streaming methods,
+// mirroring the generated stream wrappers of the WASM exports.
+type exportStream struct {
+	ctx       context.Context
+	codec     ExportCodec
+	onMessage func([]byte) error
+	requests  *StreamQueue[proto.Message]
+}
+
+// SendMsg serializes a response and passes it to onMessage.
+func (s *exportStream) SendMsg(m any) error {
+	if err := s.ctx.Err(); err != nil {
+		return err
+	}
+	msg, ok := m.(proto.Message)
+	if !ok {
+		return fmt.Errorf("unexpected message type %T", m)
+	}
+
+	SendPendingHeader(s.ctx)
+	data, err := s.codec.encode(msg)
+	if err != nil {
+		return fmt.Errorf("Failed to marshal response: %v", err)
+	}
+	if s.onMessage == nil {
+		return nil
+	}
+	return s.onMessage(data)
+}
+
+// RecvMsg fills m with the next queued request, or returns io.EOF once they are drained.
+// Server-streaming methods have no request stream; their request is passed directly.
+func (s *exportStream) RecvMsg(m any) error {
+	if s.requests == nil {
+		return nil
+	}
+	req, err := s.requests.Recv(s.ctx)
+	if err != nil {
+		return err
+	}
+	msg, ok := m.(proto.Message)
+	if !ok {
+		return fmt.Errorf("unexpected message type %T", m)
+	}
+	proto.Merge(msg, req)
+	return nil
+}
+
+func (s *exportStream) Context() context.Context        { return s.ctx }
+func (s *exportStream) SetHeader(md metadata.MD) error  { return grpc.SetHeader(s.ctx, md) }
+func (s *exportStream) SendHeader(md metadata.MD) error { return grpc.SendHeader(s.ctx, md) }
+func (s *exportStream) SetTrailer(md metadata.MD)       { grpc.SetTrailer(s.ctx, md) }
+
+// Ensure exportStream implements grpc.ServerStream
+var _ grpc.ServerStream = (*exportStream)(nil)
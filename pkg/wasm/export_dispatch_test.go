@@ -0,0 +1,193 @@
+// Package wasm provides runtime support for protoc-gen-go-wasmjs generated code.
+package wasm
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+// TestDispatchUnary tests running unary methods the way generated exports do
+func TestDispatchUnary(t *testing.T) {
+	info := &grpc.UnaryServerInfo{FullMethod: "/test.v1.Echo/Echo"}
+	codec := ExportCodec{Marshal: MarshalOptions{EmitUnpopulated: true}}
+	echo := func(ctx context.Context, req *wrapperspb.StringValue) (*wrapperspb.StringValue, error) {
+		return wrapperspb.String("echo " + req.GetValue()), nil
+	}
+
+	t.Run("Parses the request and serializes the response", func(t *testing.T) {
+		var intercepted []string
+		interceptor := func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+			intercepted = append(intercepted, info.FullMethod)
+			return handler(ctx, req)
+		}
+
+		response, err := DispatchUnary(context.Background(), codec, []byte(`"hi"`), info, []grpc.UnaryServerInterceptor{interceptor}, echo)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if string(response) != `"echo hi"` {
+			t.Errorf(`Expected "echo hi", got %s`, response)
+		}
+		if len(intercepted) != 1 {
+			t.Errorf("Expected the interceptor to run once, got %v", intercepted)
+		}
+	})
+
+	t.Run("Uses protobuf bytes with the binary wire format", func(t *testing.T) {
+		request, _ := proto.Marshal(wrapperspb.String("bytes"))
+		response, err := DispatchUnary(context.Background(), ExportCodec{Binary: true}, request, info, nil, echo)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		msg := &wrapperspb.StringValue{}
+		if err := proto.Unmarshal(response, msg); err != nil || msg.GetValue() != "echo bytes" {
+			t.Errorf("Expected 'echo bytes', got %q (err: %v)", msg.GetValue(), err)
+		}
+	})
+
+	t.Run("Applies the unmarshal options to binary requests", func(t *testing.T) {
+		request := []byte{0x0a, 0x02, 'h', 'i', 0x10, 0x01} // value "hi" and unknown field 2
+		for _, discard := range []bool{true, false} {
+			var kept bool
+			inspect := func(ctx context.Context, req *wrapperspb.StringValue) (*wrapperspb.StringValue, error) {
+				kept = len(req.ProtoReflect().GetUnknown()) > 0
+				return req, nil
+			}
+			binary := ExportCodec{Binary: true, Unmarshal: UnmarshalOptions{DiscardUnknown: discard}}
+			if _, err := DispatchUnary(context.Background(), binary, request, info, nil, inspect); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if kept == discard {
+				t.Errorf("DiscardUnknown=%v: unknown fields kept = %v", discard, kept)
+			}
+		}
+	})
+
+	t.Run("Reports unparseable requests without a status", func(t *testing.T) {
+		_, err := DispatchUnary(context.Background(), codec, []byte(`{not json`), info, nil, echo)
+		var exportErr *ExportError
+		if !errors.As(err, &exportErr) || exportErr.Status != nil {
+			t.Fatalf("Expected an ExportError without status, got %#v", err)
+		}
+	})
+
+	t.Run("Wraps service errors in the error envelope", func(t *testing.T) {
+		_, err := DispatchUnary(context.Background(), codec, []byte(`"missing"`), info, nil,
+			func(ctx context.Context, req *wrapperspb.StringValue) (*wrapperspb.StringValue, error) {
+				return nil, status.Error(codes.NotFound, "no such thing")
+			})
+		var exportErr *ExportError
+		if !errors.As(err, &exportErr) {
+			t.Fatalf("Expected an ExportError, got %v", err)
+		}
+		if exportErr.Message != "Service call failed: rpc error: code = NotFound desc = no such thing" {
+			t.Errorf("Unexpected message %q", exportErr.Message)
+		}
+		if exportErr.Status.Code != codes.NotFound || status.Code(err) != codes.NotFound {
+			t.Errorf("Expected NOT_FOUND, got %v / %v", exportErr.Status.Code, status.Code(err))
+		}
+	})
+
+	t.Run("Recovers panics as INTERNAL errors", func(t *testing.T) {
+		_, err := DispatchUnary(context.Background(), codec, []byte(`"boom"`), info, nil,
+			func(ctx context.Context, req *wrapperspb.StringValue) (*wrapperspb.StringValue, error) {
+				panic("boom")
+			})
+		if status.Code(err) != codes.Internal {
+			t.Errorf("Expected INTERNAL, got %v", err)
+		}
+	})
+
+	t.Run("Passes outgoing metadata as incoming metadata", func(t *testing.T) {
+		ctx, cancel := ExportCallContext(metadata.AppendToOutgoingContext(context.Background(), "x-user", "ada"), info.FullMethod, 0)
+		defer cancel()
+
+		response, err := DispatchUnary(ctx, codec, []byte(`""`), info, nil,
+			func(ctx context.Context, req *wrapperspb.StringValue) (*wrapperspb.StringValue, error) {
+				md, _ := metadata.FromIncomingContext(ctx)
+				if err := grpc.SetHeader(ctx, metadata.Pairs("x-seen", "yes")); err != nil {
+					return nil, err
+				}
+				return wrapperspb.String(md.Get("x-user")[0]), nil
+			})
+		if err != nil || string(response) != `"ada"` {
+			t.Errorf(`Expected "ada", got %s (err: %v)`, response, err)
+		}
+	})
+}
+
+// TestDispatchStreams tests running streaming methods the way generated exports do
+func TestDispatchStreams(t *testing.T) {
+	codec := ExportCodec{}
+
+	t.Run("Delivers server-streamed responses", func(t *testing.T) {
+		info := &grpc.StreamServerInfo{FullMethod: "/test.v1.Echo/Repeat", IsServerStream: true}
+		var responses []string
+		err := DispatchServerStream(context.Background(), codec, []byte(`"x"`), nil, info, nil,
+			func(req *wrapperspb.StringValue, stream grpc.ServerStream) error {
+				for _, suffix := range []string{"1", "2"} {
+					if err := stream.SendMsg(wrapperspb.String(req.GetValue() + suffix)); err != nil {
+						return err
+					}
+				}
+				return nil
+			}, func(response []byte) error {
+				responses = append(responses, string(response))
+				return nil
+			})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(responses) != 2 || responses[0] != `"x1"` || responses[1] != `"x2"` {
+			t.Errorf("Expected x1 and x2, got %v", responses)
+		}
+	})
+
+	t.Run("Stops the stream when onMessage fails", func(t *testing.T) {
+		info := &grpc.StreamServerInfo{FullMethod: "/test.v1.Echo/Repeat", IsServerStream: true}
+		stop := errors.New("stop")
+		err := DispatchServerStream(context.Background(), codec, []byte(`"x"`), nil, info, nil,
+			func(req *wrapperspb.StringValue, stream grpc.ServerStream) error {
+				return stream.SendMsg(req)
+			}, func(response []byte) error {
+				return stop
+			})
+		if !errors.Is(err, stop) {
+			t.Errorf("Expected the onMessage error, got %v", err)
+		}
+	})
+
+	t.Run("Feeds client-streamed requests until EOF", func(t *testing.T) {
+		info := &grpc.StreamServerInfo{FullMethod: "/test.v1.Echo/Join", IsClientStream: true}
+		var response string
+		err := DispatchClientStream[wrapperspb.StringValue](context.Background(), codec, [][]byte{[]byte(`"a"`), []byte(`"b"`)}, nil, info, nil,
+			func(stream grpc.ServerStream) error {
+				joined := ""
+				for {
+					req := &wrapperspb.StringValue{}
+					if err := stream.RecvMsg(req); err == io.EOF {
+						break
+					} else if err != nil {
+						return err
+					}
+					joined += req.GetValue()
+				}
+				return stream.SendMsg(wrapperspb.String(joined))
+			}, func(data []byte) error {
+				response = string(data)
+				return nil
+			})
+		if err != nil || response != `"ab"` {
+			t.Errorf(`Expected "ab", got %s (err: %v)`, response, err)
+		}
+	})
+}
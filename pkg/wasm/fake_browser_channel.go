@@ -0,0 +1,151 @@
+// Package wasm provides runtime support for protoc-gen-go-wasmjs generated code.
+package wasm
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// FakeBrowserChannel is a BrowserChannel that calls Go fakes of browser-provided methods
+// instead of JavaScript, so code using generated browser clients can be tested with plain
+// go test. Generate the clients with host_browser_clients=true to build them outside js/wasm,
+// where GetNamedBrowserChannel returns a FakeBrowserChannel; or pass one to the generated
+// New{Service}ClientWithChannel constructor.
+//
+// Example:
+//
+//	channel := wasm.GetNamedBrowserChannel("myApp")
+//	wasm.FakeUnary(channel, "/browser.v1.BrowserAPI/GetLocalStorage",
+//	    func(ctx context.Context, req *browserv1.StorageKeyRequest) (*browserv1.StorageValueResponse, error) {
+//	        return &browserv1.StorageValueResponse{Value: "stored", Exists: true}, nil
+//	    })
+//
+//	browserAPI := browserv1_wasm.NewBrowserAPIClient()
+//	resp, err := browserAPI.GetLocalStorage(ctx, &browserv1.StorageKeyRequest{Key: "user"})
+//
+// Calls to methods without a fake fail with code UNIMPLEMENTED.
+type FakeBrowserChannel struct {
+	mu           sync.RWMutex
+	unary        map[string]fakeUnaryHandler
+	streams      map[string]fakeStreamHandler
+	interceptors []BrowserClientInterceptor
+}
+
+// fakeUnaryHandler and fakeStreamHandler are the untyped forms of FakeUnary and FakeServerStream handlers
+type (
+	fakeUnaryHandler  func(ctx context.Context, req proto.Message) (proto.Message, error)
+	fakeStreamHandler func(ctx context.Context, req proto.Message, send func(proto.Message) error) error
+)
+
+// NewFakeBrowserChannel creates a channel without any fakes.
+func NewFakeBrowserChannel() *FakeBrowserChannel {
+	return &FakeBrowserChannel{
+		unary:   make(map[string]fakeUnaryHandler),
+		streams: make(map[string]fakeStreamHandler),
+	}
+}
+
+// FakeUnary registers handler as the fake of a unary browser-provided method, identified
+// by its gRPC full method name. It replaces any fake registered before for the method.
+func FakeUnary[Req, Resp proto.Message](channel *FakeBrowserChannel, fullMethod string, handler func(ctx context.Context, req Req) (Resp, error)) {
+	channel.mu.Lock()
+	defer channel.mu.Unlock()
+	channel.unary[fullMethod] = func(ctx context.Context, req proto.Message) (proto.Message, error) {
+		typedReq, ok := req.(Req)
+		if !ok {
+			return nil, Errorf(CodeInvalidArgument, "%s: unexpected request type %T", fullMethod, req)
+		}
+		return handler(ctx, typedReq)
+	}
+}
+
+// FakeServerStream registers handler as the fake of a server-streaming browser-provided
+// method. The handler sends messages with send; the stream ends with the error it returns,
+// or io.EOF if it returns nil.
+func FakeServerStream[Req, Resp proto.Message](channel *FakeBrowserChannel, fullMethod string, handler func(ctx context.Context, req Req, send func(Resp) error) error) {
+	channel.mu.Lock()
+	defer channel.mu.Unlock()
+	channel.streams[fullMethod] = func(ctx context.Context, req proto.Message, send func(proto.Message) error) error {
+		typedReq, ok := req.(Req)
+		if !ok {
+			return Errorf(CodeInvalidArgument, "%s: unexpected request type %T", fullMethod, req)
+		}
+		return handler(ctx, typedReq, func(resp Resp) error { return send(resp) })
+	}
+}
+
+// UseInterceptors adds client interceptors that run around every call made through this
+// channel, as BrowserServiceChannel.UseInterceptors does.
+func (f *FakeBrowserChannel) UseInterceptors(interceptors ...BrowserClientInterceptor) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.interceptors = append(f.interceptors, interceptors...)
+}
+
+// Invoke calls the fake of a unary method through the channel interceptors, the given
+// client interceptors and the method's retry policy, filling reply with its response.
+func (f *FakeBrowserChannel) Invoke(ctx context.Context, method BrowserMethod, req, reply proto.Message, interceptors ...BrowserClientInterceptor) error {
+	f.mu.RLock()
+	handler := f.unary[method.FullMethod]
+	channelInterceptors := f.interceptors
+	f.mu.RUnlock()
+
+	return invokeBrowserMethod(ctx, method, req, reply, channelInterceptors, interceptors, func(ctx context.Context, req, reply proto.Message) error {
+		if handler == nil {
+			return Errorf(CodeUnimplemented, "no fake registered for browser method %s", method.FullMethod)
+		}
+		resp, err := handler(ctx, req)
+		if err != nil {
+			return err
+		}
+		proto.Merge(reply, resp)
+		return nil
+	})
+}
+
+// NewStream runs the fake of a server-streaming method in a goroutine, through the channel
+// interceptors and the given client interceptors, delivering the messages it sends through
+// the returned stream. Messages go through the marshaller of their type, as they would
+// coming from JavaScript.
+func (f *FakeBrowserChannel) NewStream(ctx context.Context, method BrowserMethod, req proto.Message, interceptors ...BrowserClientInterceptor) (*BrowserStream, error) {
+	f.mu.RLock()
+	handler := f.streams[method.FullMethod]
+	channelInterceptors := f.interceptors
+	f.mu.RUnlock()
+
+	return openBrowserStream(ctx, method, req, channelInterceptors, interceptors, func(ctx context.Context, req proto.Message) (*BrowserStream, error) {
+		return f.runStream(ctx, method, req, handler)
+	})
+}
+
+// runStream starts handler, the fake of a server-streaming method.
+func (f *FakeBrowserChannel) runStream(ctx context.Context, method BrowserMethod, req proto.Message, handler fakeStreamHandler) (*BrowserStream, error) {
+	if handler == nil {
+		return nil, Errorf(CodeUnimplemented, "no fake registered for browser method %s", method.FullMethod)
+	}
+
+	stream := NewBrowserStream(ctx)
+	stream.method = method
+	send := func(m proto.Message) error {
+		data, err := method.marshal(m)
+		if err != nil {
+			return fmt.Errorf("failed to marshal stream message: %w", err)
+		}
+		if !stream.Push(data) {
+			return io.ErrClosedPipe
+		}
+		return nil
+	}
+
+	go func() {
+		stream.Finish(handler(ctx, req, send))
+	}()
+	return stream, nil
+}
+
+// Ensure FakeBrowserChannel implements BrowserChannel
+var _ BrowserChannel = (*FakeBrowserChannel)(nil)
@@ -0,0 +1,175 @@
+// Package wasm provides runtime support for protoc-gen-go-wasmjs generated code.
+package wasm
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+// TestFakeBrowserChannel tests calling Go fakes of browser-provided methods
+func TestFakeBrowserChannel(t *testing.T) {
+	getMethod := BrowserMethod{FullMethod: "/test.v1.BrowserAPI/Get", Service: "BrowserAPI", Method: "get"}
+	watchMethod := BrowserMethod{FullMethod: "/test.v1.BrowserAPI/Watch", Service: "BrowserAPI", Method: "watch"}
+
+	t.Run("Invokes unary fakes through interceptors", func(t *testing.T) {
+		channel := NewFakeBrowserChannel()
+		FakeUnary(channel, getMethod.FullMethod, func(ctx context.Context, req *wrapperspb.StringValue) (*wrapperspb.StringValue, error) {
+			return wrapperspb.String("value of " + req.GetValue()), nil
+		})
+
+		var intercepted []string
+		channel.UseInterceptors(func(ctx context.Context, method string, req, reply proto.Message, invoker BrowserInvoker) error {
+			intercepted = append(intercepted, method)
+			return invoker(ctx, method, req, reply)
+		})
+
+		reply := &wrapperspb.StringValue{}
+		if err := channel.Invoke(context.Background(), getMethod, wrapperspb.String("key"), reply); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if reply.GetValue() != "value of key" {
+			t.Errorf("Expected 'value of key', got %q", reply.GetValue())
+		}
+		if len(intercepted) != 1 || intercepted[0] != getMethod.FullMethod {
+			t.Errorf("Expected the channel interceptor to see the call, got %v", intercepted)
+		}
+	})
+
+	t.Run("Applies the retry policy", func(t *testing.T) {
+		channel := NewFakeBrowserChannel()
+		attempts := 0
+		FakeUnary(channel, getMethod.FullMethod, func(ctx context.Context, req *wrapperspb.StringValue) (*wrapperspb.StringValue, error) {
+			if attempts++; attempts < 2 {
+				return nil, errors.New("Failed to fetch")
+			}
+			return wrapperspb.String("ok"), nil
+		})
+
+		method := getMethod
+		method.Retry = &RetryPolicy{MaxAttempts: 2, InitialBackoff: 1}
+		reply := &wrapperspb.StringValue{}
+		if err := channel.Invoke(context.Background(), method, wrapperspb.String("key"), reply); err != nil || attempts != 2 {
+			t.Errorf("Expected success on attempt 2, got %d attempts (err: %v)", attempts, err)
+		}
+	})
+
+	t.Run("Methods without fakes are unimplemented", func(t *testing.T) {
+		channel := NewFakeBrowserChannel()
+		err := channel.Invoke(context.Background(), getMethod, wrapperspb.String("key"), &wrapperspb.StringValue{})
+		if status.Code(err) != codes.Unimplemented {
+			t.Errorf("Expected UNIMPLEMENTED, got %v", err)
+		}
+		if _, err := channel.NewStream(context.Background(), watchMethod, wrapperspb.String("key")); status.Code(err) != codes.Unimplemented {
+			t.Errorf("Expected UNIMPLEMENTED, got %v", err)
+		}
+	})
+
+	t.Run("Streams messages sent by server-streaming fakes", func(t *testing.T) {
+		channel := NewFakeBrowserChannel()
+		FakeServerStream(channel, watchMethod.FullMethod, func(ctx context.Context, req *wrapperspb.StringValue, send func(*wrapperspb.StringValue) error) error {
+			for _, suffix := range []string{"1", "2"} {
+				if err := send(wrapperspb.String(req.GetValue() + suffix)); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+
+		stream, err := channel.NewStream(context.Background(), watchMethod, wrapperspb.String("event"))
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		recv := &grpc.GenericClientStream[wrapperspb.StringValue, wrapperspb.StringValue]{ClientStream: stream}
+		for _, expected := range []string{"event1", "event2"} {
+			msg, err := recv.Recv()
+			if err != nil || msg.GetValue() != expected {
+				t.Fatalf("Expected %q, got %q (err: %v)", expected, msg.GetValue(), err)
+			}
+		}
+		if _, err := recv.Recv(); err != io.EOF {
+			t.Errorf("Expected io.EOF, got %v", err)
+		}
+	})
+
+	t.Run("Opens streams through interceptors", func(t *testing.T) {
+		type userKey struct{}
+		channel := NewFakeBrowserChannel()
+		FakeServerStream(channel, watchMethod.FullMethod, func(ctx context.Context, req *wrapperspb.StringValue, send func(*wrapperspb.StringValue) error) error {
+			user, _ := ctx.Value(userKey{}).(string)
+			return send(wrapperspb.String(user))
+		})
+
+		var intercepted []string
+		channel.UseInterceptors(func(ctx context.Context, method string, req, reply proto.Message, invoker BrowserInvoker) error {
+			intercepted = append(intercepted, "channel "+method)
+			return invoker(ctx, method, req, reply)
+		})
+		auth := func(ctx context.Context, method string, req, reply proto.Message, invoker BrowserInvoker) error {
+			if reply != nil {
+				t.Errorf("Expected a nil reply for a stream, got %v", reply)
+			}
+			intercepted = append(intercepted, "client "+method)
+			return invoker(context.WithValue(ctx, userKey{}, "alice"), method, req, reply)
+		}
+
+		stream, err := channel.NewStream(context.Background(), watchMethod, wrapperspb.String("event"), auth)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		msg := &wrapperspb.StringValue{}
+		if err := stream.RecvMsg(msg); err != nil || msg.GetValue() != "alice" {
+			t.Fatalf("Expected the fake to see the interceptor's context, got %q (err: %v)", msg.GetValue(), err)
+		}
+		if len(intercepted) != 2 || intercepted[0] != "channel "+watchMethod.FullMethod || intercepted[1] != "client "+watchMethod.FullMethod {
+			t.Errorf("Expected the channel then the client interceptor, got %v", intercepted)
+		}
+	})
+
+	t.Run("Interceptors not opening streams fail the call", func(t *testing.T) {
+		channel := NewFakeBrowserChannel()
+		skip := func(ctx context.Context, method string, req, reply proto.Message, invoker BrowserInvoker) error {
+			return nil
+		}
+		if _, err := channel.NewStream(context.Background(), watchMethod, wrapperspb.String("event"), skip); status.Code(err) != codes.Internal {
+			t.Errorf("Expected INTERNAL, got %v", err)
+		}
+	})
+
+	t.Run("Streams protobuf bytes for binary methods", func(t *testing.T) {
+		binaryMethod := watchMethod
+		binaryMethod.Binary = true
+		channel := NewFakeBrowserChannel()
+		FakeServerStream(channel, binaryMethod.FullMethod, func(ctx context.Context, req *wrapperspb.StringValue, send func(*wrapperspb.StringValue) error) error {
+			return send(wrapperspb.String(req.GetValue() + "1"))
+		})
+
+		stream, err := channel.NewStream(context.Background(), binaryMethod, wrapperspb.String("event"))
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		msg := &wrapperspb.StringValue{}
+		if err := stream.RecvMsg(msg); err != nil || msg.GetValue() != "event1" {
+			t.Fatalf("Expected 'event1', got %q (err: %v)", msg.GetValue(), err)
+		}
+	})
+
+	t.Run("Named channels are shared until closed", func(t *testing.T) {
+		channel := GetNamedBrowserChannel("fakeTest")
+		if GetNamedBrowserChannel("fakeTest") != channel {
+			t.Error("Expected the same channel for the same name")
+		}
+		CloseNamedBrowserChannel("fakeTest")
+		if GetNamedBrowserChannel("fakeTest") == channel {
+			t.Error("Expected a new channel after close")
+		}
+		CloseNamedBrowserChannel("fakeTest")
+	})
+}
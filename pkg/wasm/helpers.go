@@ -17,8 +17,10 @@
 package wasm
 
 import (
+	"context"
 	"encoding/json"
 	"syscall/js"
+	"time"
 )
 
 // CreateJSResponse creates a JavaScript-compatible response object
@@ -45,4 +47,144 @@ func CreateJSResponse(success bool, message string, data any) any {
 	}
 
 	return js.Global().Get("JSON").Call("parse", string(responseBytes))
-}
\ No newline at end of file
+}
+
+// CreateJSBinaryResponse creates the same response envelope as CreateJSResponse but
+// carries data as a Uint8Array of protobuf bytes instead of a JSON value.
+// This is used by generated WASM service methods when wire_format=binary.
+func CreateJSBinaryResponse(success bool, message string, data []byte) any {
+	response := js.Global().Get("Object").New()
+	response.Set("success", success)
+	response.Set("message", message)
+
+	if data != nil {
+		response.Set("data", BytesToJS(data))
+	}
+
+	return response
+}
+
+// IsJSBytes reports whether the JavaScript value is a Uint8Array
+func IsJSBytes(value js.Value) bool {
+	return value.InstanceOf(js.Global().Get("Uint8Array"))
+}
+
+// BytesFromJS copies the contents of a JavaScript Uint8Array into a new Go byte slice
+func BytesFromJS(value js.Value) []byte {
+	data := make([]byte, value.Get("length").Int())
+	js.CopyBytesToGo(data, value)
+	return data
+}
+
+// BytesToJS copies a Go byte slice into a new JavaScript Uint8Array
+func BytesToJS(data []byte) js.Value {
+	array := js.Global().Get("Uint8Array").New(len(data))
+	js.CopyBytesToJS(array, data)
+	return array
+}
+
+// CreateJSErrorResponse creates a failed response envelope that also carries the
+// structured gRPC status of err under "error", so callers can branch on the code.
+func CreateJSErrorResponse(message string, err error) any {
+	response := js.Global().Get("Object").New()
+	response.Set("success", false)
+	response.Set("message", message)
+	response.Set("error", ErrorToJS(err))
+	return response
+}
+
+// ErrorToJS converts an error returned by a service into a JavaScript status object:
+// { code, message, details: [{ typeUrl, value, message? }] }
+// where value holds the serialized detail bytes and message the decoded detail, if known.
+func ErrorToJS(err error) js.Value {
+	errorStatus := NewErrorStatus(err)
+
+	details := js.Global().Get("Array").New()
+	for _, detail := range errorStatus.Details {
+		jsDetail := js.Global().Get("Object").New()
+		jsDetail.Set("typeUrl", detail.TypeURL)
+		jsDetail.Set("value", BytesToJS(detail.Value))
+		if detail.JSON != nil {
+			jsDetail.Set("message", js.Global().Get("JSON").Call("parse", string(detail.JSON)))
+		}
+		details.Call("push", jsDetail)
+	}
+
+	jsStatus := js.Global().Get("Object").New()
+	jsStatus.Set("code", int(errorStatus.Code))
+	jsStatus.Set("message", errorStatus.Message)
+	jsStatus.Set("details", details)
+	return jsStatus
+}
+
+// CallContext creates the context for an exported call of method (the gRPC full method name).
+// defaultTimeout comes from the method's timeout annotations (0 means no timeout).
+// TypeScript clients can override it per call by passing { timeoutMs } in the options
+// object at args[optionsIndex]; a timeoutMs of 0 disables the timeout for that call.
+// An AbortSignal passed as { signal } cancels the context when it fires.
+//
+// Headers passed as { headers } become the incoming gRPC metadata of the context.
+// Metadata the method sets with grpc.SetHeader/SendHeader and grpc.SetTrailer is
+// delivered to the { onHeader } and { onTrailer } callbacks (see FinishCall).
+// TinyGo builds leave gRPC metadata out, so they ignore these options.
+func CallContext(args []js.Value, optionsIndex int, method string, defaultTimeout time.Duration) (context.Context, context.CancelFunc) {
+	timeout := defaultTimeout
+	signal := js.Undefined()
+	options := js.Undefined()
+	if optionsIndex < len(args) && args[optionsIndex].Type() == js.TypeObject {
+		options = args[optionsIndex]
+		if timeoutMs := options.Get("timeoutMs"); timeoutMs.Type() == js.TypeNumber {
+			timeout = time.Duration(timeoutMs.Float() * float64(time.Millisecond))
+		}
+		signal = options.Get("signal")
+	}
+
+	var ctx context.Context
+	var cancel context.CancelFunc
+	if timeout <= 0 {
+		ctx, cancel = context.WithCancel(context.Background())
+	} else {
+		ctx, cancel = context.WithTimeout(context.Background(), timeout)
+	}
+
+	if signal.Type() == js.TypeObject {
+		cancelOnAbort(ctx, cancel, signal)
+	}
+
+	return callMetadataContext(ctx, options, method), cancel
+}
+
+// cancelOnAbort cancels ctx when the AbortSignal fires.
+// The abort listener is removed and released once ctx is done.
+func cancelOnAbort(ctx context.Context, cancel context.CancelFunc, signal js.Value) {
+	if signal.Get("aborted").Truthy() {
+		cancel()
+		return
+	}
+
+	onAbort := js.FuncOf(func(this js.Value, args []js.Value) any {
+		cancel()
+		return nil
+	})
+	signal.Call("addEventListener", "abort", onAbort)
+
+	go func() {
+		<-ctx.Done()
+		signal.Call("removeEventListener", "abort", onAbort)
+		onAbort.Release()
+	}()
+}
+
+// CreateJSStreamHandle creates the response returned when a client streaming or
+// bidirectional streaming call starts. JavaScript sends requests through send,
+// half-closes the stream with closeSend and stops the call with cancel.
+// The functions are released by generated code once the method returns.
+func CreateJSStreamHandle(send, closeSend, cancel js.Func) any {
+	handle := js.Global().Get("Object").New()
+	handle.Set("success", true)
+	handle.Set("message", "Stream started")
+	handle.Set("send", send)
+	handle.Set("closeSend", closeSend)
+	handle.Set("cancel", cancel)
+	return handle
+}
@@ -0,0 +1,98 @@
+//go:build !tinygo
+
+package wasm
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// InvokeUnary calls a unary (or async) service method through a chain of
+// gRPC unary server interceptors, so the same logging, auth and validation
+// interceptors used on a gRPC server run inside the WASM module.
+//
+// Interceptors run in order: interceptors[0] is the outermost.
+// With no interceptors the handler is called directly.
+func InvokeUnary[Req any, Resp any](
+	ctx context.Context,
+	req Req,
+	info *grpc.UnaryServerInfo,
+	interceptors []grpc.UnaryServerInterceptor,
+	handler func(context.Context, Req) (Resp, error),
+) (Resp, error) {
+	if len(interceptors) == 0 {
+		return handler(ctx, req)
+	}
+
+	var zero Resp
+	resp, err := chainUnary(interceptors, func(ctx context.Context, req any) (any, error) {
+		typedReq, ok := req.(Req)
+		if !ok {
+			return nil, status.Errorf(codes.Internal, "interceptor passed %T to %s, expected %T", req, info.FullMethod, zero)
+		}
+		return handler(ctx, typedReq)
+	})(ctx, req, info)
+	if err != nil {
+		return zero, err
+	}
+
+	typedResp, ok := resp.(Resp)
+	if !ok {
+		return zero, status.Errorf(codes.Internal, "interceptor returned %T from %s", resp, info.FullMethod)
+	}
+	return typedResp, nil
+}
+
+// InvokeStream calls a streaming service method through a chain of gRPC
+// stream server interceptors. The handler receives the (possibly wrapped)
+// stream and should adapt it with grpc.GenericServerStream.
+//
+// Interceptors run in order: interceptors[0] is the outermost.
+// With no interceptors the handler is called directly.
+func InvokeStream(
+	srv any,
+	stream grpc.ServerStream,
+	info *grpc.StreamServerInfo,
+	interceptors []grpc.StreamServerInterceptor,
+	handler grpc.StreamHandler,
+) error {
+	if len(interceptors) == 0 {
+		return handler(srv, stream)
+	}
+	return chainStream(interceptors, handler)(srv, stream, info)
+}
+
+// chainUnary folds interceptors into a single function ending in handler.
+func chainUnary(interceptors []grpc.UnaryServerInterceptor, handler grpc.UnaryHandler) func(context.Context, any, *grpc.UnaryServerInfo) (any, error) {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo) (any, error) {
+		var next func(i int) grpc.UnaryHandler
+		next = func(i int) grpc.UnaryHandler {
+			if i == len(interceptors) {
+				return handler
+			}
+			return func(ctx context.Context, req any) (any, error) {
+				return interceptors[i](ctx, req, info, next(i+1))
+			}
+		}
+		return next(0)(ctx, req)
+	}
+}
+
+// chainStream folds interceptors into a single function ending in handler.
+func chainStream(interceptors []grpc.StreamServerInterceptor, handler grpc.StreamHandler) func(any, grpc.ServerStream, *grpc.StreamServerInfo) error {
+	return func(srv any, stream grpc.ServerStream, info *grpc.StreamServerInfo) error {
+		var next func(i int) grpc.StreamHandler
+		next = func(i int) grpc.StreamHandler {
+			if i == len(interceptors) {
+				return handler
+			}
+			return func(srv any, stream grpc.ServerStream) error {
+				return interceptors[i](srv, stream, info, next(i+1))
+			}
+		}
+		return next(0)(srv, stream)
+	}
+}
@@ -0,0 +1,105 @@
+// Package wasm provides runtime support for protoc-gen-go-wasmjs generated code.
+package wasm
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// TestInvokeUnary tests running exported unary methods through interceptor chains
+func TestInvokeUnary(t *testing.T) {
+	info := &grpc.UnaryServerInfo{FullMethod: "/library.v1.LibraryService/FindBooks"}
+	handler := func(ctx context.Context, req string) (string, error) {
+		return "handled " + req, nil
+	}
+
+	t.Run("Calls handler directly without interceptors", func(t *testing.T) {
+		resp, err := InvokeUnary(context.Background(), "req", info, nil, handler)
+		if err != nil || resp != "handled req" {
+			t.Errorf("Expected 'handled req', got %q (err: %v)", resp, err)
+		}
+	})
+
+	t.Run("Runs interceptors outermost first with method info", func(t *testing.T) {
+		var calls []string
+		record := func(name string) grpc.UnaryServerInterceptor {
+			return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+				calls = append(calls, name+" "+info.FullMethod)
+				return handler(ctx, req)
+			}
+		}
+
+		resp, err := InvokeUnary(context.Background(), "req", info,
+			[]grpc.UnaryServerInterceptor{record("outer"), record("inner")}, handler)
+		if err != nil || resp != "handled req" {
+			t.Fatalf("Expected 'handled req', got %q (err: %v)", resp, err)
+		}
+
+		expected := []string{
+			"outer /library.v1.LibraryService/FindBooks",
+			"inner /library.v1.LibraryService/FindBooks",
+		}
+		if !reflect.DeepEqual(calls, expected) {
+			t.Errorf("Expected calls %v, got %v", expected, calls)
+		}
+	})
+
+	t.Run("Interceptor can reject the call", func(t *testing.T) {
+		deny := func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+			return nil, status.Error(codes.Unauthenticated, "no token")
+		}
+
+		_, err := InvokeUnary(context.Background(), "req", info, []grpc.UnaryServerInterceptor{deny}, handler)
+		if status.Code(err) != codes.Unauthenticated {
+			t.Errorf("Expected Unauthenticated, got %v", err)
+		}
+	})
+
+	t.Run("Interceptor returning the wrong response type fails", func(t *testing.T) {
+		replace := func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+			return 42, nil
+		}
+
+		_, err := InvokeUnary(context.Background(), "req", info, []grpc.UnaryServerInterceptor{replace}, handler)
+		if status.Code(err) != codes.Internal {
+			t.Errorf("Expected Internal, got %v", err)
+		}
+	})
+}
+
+// TestInvokeStream tests running exported streaming methods through interceptor chains
+func TestInvokeStream(t *testing.T) {
+	info := &grpc.StreamServerInfo{FullMethod: "/editor.v1.EditorService/SyncCursors", IsClientStream: true, IsServerStream: true}
+	errHandled := errors.New("handled")
+
+	t.Run("Runs interceptors outermost first and passes wrapped streams", func(t *testing.T) {
+		var calls []string
+		record := func(name string) grpc.StreamServerInterceptor {
+			return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+				calls = append(calls, name)
+				return handler(srv, ss)
+			}
+		}
+
+		err := InvokeStream("server", nil, info,
+			[]grpc.StreamServerInterceptor{record("outer"), record("inner")},
+			func(srv any, stream grpc.ServerStream) error {
+				calls = append(calls, "handler "+srv.(string))
+				return errHandled
+			})
+		if !errors.Is(err, errHandled) {
+			t.Fatalf("Expected handler error, got %v", err)
+		}
+
+		expected := []string{"outer", "inner", "handler server"}
+		if !reflect.DeepEqual(calls, expected) {
+			t.Errorf("Expected calls %v, got %v", expected, calls)
+		}
+	})
+}
@@ -0,0 +1,172 @@
+// Copyright 2025 Sri Panyam
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build js && wasm
+
+package wasm
+
+import (
+	"fmt"
+	"strconv"
+	"syscall/js"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// JSValueMarshaller is implemented by marshallers that convert messages straight to
+// and from JavaScript values. Generated code uses it, when the marshaller of a message
+// implements it, instead of going through JSON text.
+type JSValueMarshaller interface {
+	// MarshalJS converts a proto message into a JavaScript value
+	MarshalJS(m proto.Message, opts MarshalOptions) (js.Value, error)
+	// UnmarshalJS fills a proto message from a JavaScript value
+	UnmarshalJS(value js.Value, m proto.Message, opts UnmarshalOptions) error
+}
+
+// MarshalJS converts a proto message into a JavaScript object by walking it with
+// protoreflect. The result equals JSON.parse of the protojson encoding.
+func (r *ReflectMarshaller) MarshalJS(m proto.Message, opts MarshalOptions) (js.Value, error) {
+	return marshalValue[js.Value](jsValues{}, m, opts)
+}
+
+// UnmarshalJS fills a proto message from a JavaScript value, accepting what
+// protojson accepts for JSON.stringify of the value.
+func (r *ReflectMarshaller) UnmarshalJS(value js.Value, m proto.Message, opts UnmarshalOptions) error {
+	return unmarshalValue[js.Value](jsValues{}, jsonValue(value), m, opts)
+}
+
+// Ensure ReflectMarshaller implements JSValueMarshaller
+var _ JSValueMarshaller = (*ReflectMarshaller)(nil)
+
+// MessageToJS converts a response message into a JavaScript value with the message's
+// marshaller (see GetMarshaller): directly if it implements JSValueMarshaller,
+// otherwise through JSON.
+func MessageToJS(m proto.Message, opts MarshalOptions) (js.Value, error) {
+	marshaller := GetMarshaller(m)
+	if jsMarshaller, ok := marshaller.(JSValueMarshaller); ok {
+		return jsMarshaller.MarshalJS(m, opts)
+	}
+
+	data, err := marshaller.Marshal(m, opts)
+	if err != nil {
+		return js.Undefined(), err
+	}
+	return js.Global().Get("JSON").Call("parse", string(data)), nil
+}
+
+// MessageFromJS fills a request message from a JavaScript value with the message's
+// marshaller (see GetMarshaller). The value is either a request object or its JSON string.
+func MessageFromJS(value js.Value, m proto.Message, opts UnmarshalOptions) error {
+	marshaller := GetMarshaller(m)
+	if value.Type() == js.TypeString {
+		return marshaller.Unmarshal([]byte(value.String()), m, opts)
+	}
+	if jsMarshaller, ok := marshaller.(JSValueMarshaller); ok {
+		return jsMarshaller.UnmarshalJS(value, m, opts)
+	}
+
+	data := js.Global().Get("JSON").Call("stringify", value)
+	if data.Type() != js.TypeString {
+		return fmt.Errorf("request must be an object, got %s", value.Type())
+	}
+	return marshaller.Unmarshal([]byte(data.String()), m, opts)
+}
+
+// CreateJSValueResponse creates the same response envelope as CreateJSResponse but
+// carries data as an already converted JavaScript value (see MessageToJS).
+func CreateJSValueResponse(success bool, message string, data js.Value) any {
+	response := js.Global().Get("Object").New()
+	response.Set("success", success)
+	response.Set("message", message)
+	response.Set("data", data)
+	return response
+}
+
+// jsValues reads and writes JavaScript values for the protoreflect converter
+type jsValues struct{}
+
+func (jsValues) null() js.Value             { return js.Null() }
+func (jsValues) boolean(b bool) js.Value    { return js.ValueOf(b) }
+func (jsValues) number(f float64) js.Value  { return js.ValueOf(f) }
+func (jsValues) str(s string) js.Value      { return js.ValueOf(s) }
+func (jsValues) boolOf(v js.Value) bool     { return v.Bool() }
+func (jsValues) stringOf(v js.Value) string { return v.String() }
+func (jsValues) length(v js.Value) int      { return v.Length() }
+func (jsValues) index(v js.Value, i int) js.Value {
+	return jsonValue(v.Index(i))
+}
+func (jsValues) get(v js.Value, key string) js.Value {
+	return jsonValue(v.Get(key))
+}
+
+func (jsValues) array(items []js.Value) js.Value {
+	array := js.Global().Get("Array").New(len(items))
+	for i, item := range items {
+		array.SetIndex(i, item)
+	}
+	return array
+}
+
+func (jsValues) object(keys []string, values []js.Value) js.Value {
+	object := js.Global().Get("Object").New()
+	for i, key := range keys {
+		object.Set(key, values[i])
+	}
+	return object
+}
+
+func (jsValues) numberText(v js.Value) string {
+	return strconv.FormatFloat(v.Float(), 'g', -1, 64)
+}
+
+func (jsValues) kind(v js.Value) (valueKind, error) {
+	switch v.Type() {
+	case js.TypeNull, js.TypeUndefined:
+		return nullValue, nil
+	case js.TypeBoolean:
+		return boolValue, nil
+	case js.TypeNumber:
+		return numberValue, nil
+	case js.TypeString:
+		return stringValue, nil
+	case js.TypeObject:
+		if js.Global().Get("Array").Call("isArray", v).Bool() {
+			return arrayValue, nil
+		}
+		return objectValue, nil
+	default:
+		return 0, fmt.Errorf("unsupported JavaScript value of type %s", v.Type())
+	}
+}
+
+func (jsValues) keys(v js.Value) []string {
+	jsKeys := js.Global().Get("Object").Call("keys", v)
+	keys := make([]string, 0, jsKeys.Length())
+	for i := 0; i < jsKeys.Length(); i++ {
+		// Undefined properties are dropped, as JSON.stringify drops them
+		if key := jsKeys.Index(i).String(); v.Get(key).Type() != js.TypeUndefined {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// jsonValue returns the value JSON.stringify would serialize: the result of
+// toJSON() when the value has one (e.g., a Date becomes its ISO string).
+func jsonValue(v js.Value) js.Value {
+	if v.Type() == js.TypeObject && v.Get("toJSON").Type() == js.TypeFunction {
+		return v.Call("toJSON")
+	}
+	return v
+}
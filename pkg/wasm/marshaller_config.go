@@ -2,19 +2,27 @@
 package wasm
 
 import (
+	"reflect"
 	"sync"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
 )
 
 var (
 	// globalMarshaller is the default marshaller used by all generated code
 	globalMarshaller ProtoMarshaller
-	// marshallerMutex protects access to globalMarshaller
+	// messageMarshallers overrides the global marshaller for single message types
+	messageMarshallers = map[protoreflect.FullName]ProtoMarshaller{}
+	// packageMarshallers overrides the global marshaller for messages of a Go package
+	packageMarshallers = map[string]ProtoMarshaller{}
+	// marshallerMutex protects access to globalMarshaller and the registries
 	marshallerMutex sync.RWMutex
 )
 
 func init() {
-	// Default to protojson marshaller for backward compatibility
-	globalMarshaller = NewProtojsonMarshaller()
+	// Default to protojson marshaller for backward compatibility (see defaultMarshaller)
+	globalMarshaller = defaultMarshaller()
 }
 
 // SetGlobalMarshaller sets the marshaller to be used by all generated WASM code.
@@ -45,3 +53,66 @@ func GetGlobalMarshaller() ProtoMarshaller {
 	defer marshallerMutex.RUnlock()
 	return globalMarshaller
 }
+
+// RegisterMessageMarshaller sets the marshaller used for one message type, identified
+// by its full proto name (e.g., "library.v1.Book"). It takes precedence over package
+// and global marshallers. Passing nil removes the registration.
+//
+// Example usage, with vtprotobuf code generated only for hot messages:
+//
+//	wasm.RegisterMessageMarshaller("game.v1.WorldUpdate", wasm.NewVTProtoMarshaller())
+func RegisterMessageMarshaller(fullName protoreflect.FullName, marshaller ProtoMarshaller) {
+	marshallerMutex.Lock()
+	defer marshallerMutex.Unlock()
+	if marshaller == nil {
+		delete(messageMarshallers, fullName)
+		return
+	}
+	messageMarshallers[fullName] = marshaller
+}
+
+// RegisterPackageMarshaller sets the marshaller used for all message types generated
+// into a Go package, identified by its import path. It takes precedence over the global
+// marshaller. Passing nil removes the registration.
+//
+// Example usage:
+//
+//	wasm.RegisterPackageMarshaller("example.com/gen/go/game/v1", wasm.NewVTProtoMarshaller())
+func RegisterPackageMarshaller(goImportPath string, marshaller ProtoMarshaller) {
+	marshallerMutex.Lock()
+	defer marshallerMutex.Unlock()
+	if marshaller == nil {
+		delete(packageMarshallers, goImportPath)
+		return
+	}
+	packageMarshallers[goImportPath] = marshaller
+}
+
+// GetMarshaller returns the marshaller for a message: the one registered for its type,
+// otherwise the one registered for its Go package, otherwise the global marshaller.
+// Generated code uses it for every request and response.
+func GetMarshaller(m proto.Message) ProtoMarshaller {
+	marshallerMutex.RLock()
+	defer marshallerMutex.RUnlock()
+
+	if len(messageMarshallers) > 0 && m != nil {
+		if marshaller, ok := messageMarshallers[m.ProtoReflect().Descriptor().FullName()]; ok {
+			return marshaller
+		}
+	}
+	if len(packageMarshallers) > 0 && m != nil {
+		if marshaller, ok := packageMarshallers[goPackageOf(m)]; ok {
+			return marshaller
+		}
+	}
+	return globalMarshaller
+}
+
+// goPackageOf returns the import path of the Go package declaring a message's type.
+func goPackageOf(m proto.Message) string {
+	t := reflect.TypeOf(m)
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t.PkgPath()
+}
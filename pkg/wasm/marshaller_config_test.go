@@ -0,0 +1,61 @@
+// Package wasm provides runtime support for protoc-gen-go-wasmjs generated code.
+package wasm
+
+import (
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+// namedMarshaller is a ProtoMarshaller distinguishable by name in lookups
+type namedMarshaller struct {
+	*ProtojsonMarshaller
+	name string
+}
+
+// TestGetMarshaller tests marshaller lookup by message type, Go package and global fallback
+func TestGetMarshaller(t *testing.T) {
+	global := &namedMarshaller{NewProtojsonMarshaller(), "global"}
+	byMessage := &namedMarshaller{NewProtojsonMarshaller(), "message"}
+	byPackage := &namedMarshaller{NewProtojsonMarshaller(), "package"}
+
+	previous := GetGlobalMarshaller()
+	SetGlobalMarshaller(global)
+	RegisterMessageMarshaller("google.protobuf.StringValue", byMessage)
+	RegisterPackageMarshaller("google.golang.org/protobuf/types/known/wrapperspb", byPackage)
+	t.Cleanup(func() {
+		SetGlobalMarshaller(previous)
+		RegisterMessageMarshaller("google.protobuf.StringValue", nil)
+		RegisterPackageMarshaller("google.golang.org/protobuf/types/known/wrapperspb", nil)
+	})
+
+	tests := []struct {
+		name     string
+		message  proto.Message
+		expected string
+	}{
+		{"message registration wins", wrapperspb.String("x"), "message"},
+		{"package registration covers other messages", wrapperspb.Int32(1), "package"},
+		{"unregistered messages use the global marshaller", &emptypb.Empty{}, "global"},
+		{"other packages use the global marshaller", durationpb.New(0), "global"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			marshaller, ok := GetMarshaller(tt.message).(*namedMarshaller)
+			if !ok || marshaller.name != tt.expected {
+				t.Errorf("Expected %s marshaller, got %v", tt.expected, GetMarshaller(tt.message))
+			}
+		})
+	}
+
+	t.Run("Removing a registration falls back", func(t *testing.T) {
+		RegisterMessageMarshaller("google.protobuf.StringValue", nil)
+		if marshaller := GetMarshaller(wrapperspb.String("x")).(*namedMarshaller); marshaller.name != "package" {
+			t.Errorf("Expected package marshaller, got %s", marshaller.name)
+		}
+	})
+}
@@ -0,0 +1,8 @@
+//go:build !tinygo
+
+package wasm
+
+// defaultMarshaller returns the global marshaller of standard Go builds
+func defaultMarshaller() ProtoMarshaller {
+	return NewProtojsonMarshaller()
+}
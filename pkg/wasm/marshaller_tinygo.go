@@ -0,0 +1,9 @@
+//go:build tinygo
+
+package wasm
+
+// defaultMarshaller returns the global marshaller of TinyGo builds, which leave out
+// protojson: the reflection-based marshaller follows the same JSON mapping.
+func defaultMarshaller() ProtoMarshaller {
+	return NewReflectMarshaller()
+}
@@ -0,0 +1,131 @@
+//go:build !tinygo
+
+package wasm
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// errHeaderSent is returned when a method sets header metadata after it was delivered.
+var errHeaderSent = errors.New("header metadata already sent")
+
+// CallMetadata collects the header and trailer metadata an exported method sets
+// with grpc.SetHeader, grpc.SendHeader and grpc.SetTrailer, and delivers it to
+// the JavaScript caller. It implements grpc.ServerTransportStream, so service
+// implementations use the same metadata API as on a gRPC server.
+type CallMetadata struct {
+	method    string
+	onHeader  func(metadata.MD)
+	onTrailer func(metadata.MD)
+
+	mu         sync.Mutex
+	header     metadata.MD
+	trailer    metadata.MD
+	headerSent bool
+}
+
+// NewCallMetadata creates the metadata collector of a call to method (the gRPC full method name).
+// onHeader and onTrailer receive the header and trailer metadata; either may be nil.
+func NewCallMetadata(method string, onHeader, onTrailer func(metadata.MD)) *CallMetadata {
+	return &CallMetadata{
+		method:    method,
+		onHeader:  onHeader,
+		onTrailer: onTrailer,
+	}
+}
+
+// NewCallMetadataContext attaches the caller's headers as incoming metadata and
+// the call's metadata collector to ctx.
+func NewCallMetadataContext(ctx context.Context, incoming metadata.MD, callMetadata *CallMetadata) context.Context {
+	if incoming == nil {
+		incoming = metadata.MD{}
+	}
+	ctx = metadata.NewIncomingContext(ctx, incoming)
+	return grpc.NewContextWithServerTransportStream(ctx, callMetadata)
+}
+
+// Method returns the gRPC full method name of the call.
+func (m *CallMetadata) Method() string {
+	return m.method
+}
+
+// SetHeader adds header metadata to be delivered with the first response.
+func (m *CallMetadata) SetHeader(md metadata.MD) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.headerSent {
+		return errHeaderSent
+	}
+	m.header = metadata.Join(m.header, md)
+	return nil
+}
+
+// SendHeader adds header metadata and delivers all header metadata immediately.
+func (m *CallMetadata) SendHeader(md metadata.MD) error {
+	if err := m.SetHeader(md); err != nil {
+		return err
+	}
+	m.flushHeader()
+	return nil
+}
+
+// SetTrailer adds trailer metadata to be delivered when the call completes.
+func (m *CallMetadata) SetTrailer(md metadata.MD) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.trailer = metadata.Join(m.trailer, md)
+	return nil
+}
+
+// flushHeader delivers the header metadata unless it was already sent.
+func (m *CallMetadata) flushHeader() {
+	m.mu.Lock()
+	if m.headerSent {
+		m.mu.Unlock()
+		return
+	}
+	m.headerSent = true
+	header := m.header
+	m.mu.Unlock()
+
+	if m.onHeader != nil && len(header) > 0 {
+		m.onHeader(header)
+	}
+}
+
+// finish delivers pending header metadata followed by the trailer metadata.
+func (m *CallMetadata) finish() {
+	m.flushHeader()
+
+	m.mu.Lock()
+	trailer := m.trailer
+	m.trailer = nil
+	m.mu.Unlock()
+
+	if m.onTrailer != nil && len(trailer) > 0 {
+		m.onTrailer(trailer)
+	}
+}
+
+// SendPendingHeader delivers the header metadata of the call in ctx, if not yet sent.
+// Generated stream wrappers call it before the first streamed response.
+func SendPendingHeader(ctx context.Context) {
+	if callMetadata, ok := grpc.ServerTransportStreamFromContext(ctx).(*CallMetadata); ok {
+		callMetadata.flushHeader()
+	}
+}
+
+// FinishCall delivers the header and trailer metadata of the call in ctx.
+// Generated exports call it once the method returns, before delivering the result.
+func FinishCall(ctx context.Context) {
+	if callMetadata, ok := grpc.ServerTransportStreamFromContext(ctx).(*CallMetadata); ok {
+		callMetadata.finish()
+	}
+}
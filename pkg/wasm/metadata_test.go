@@ -0,0 +1,88 @@
+// Package wasm provides runtime support for protoc-gen-go-wasmjs generated code.
+package wasm
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// TestCallMetadata tests metadata propagation between JavaScript callers and exported methods
+func TestCallMetadata(t *testing.T) {
+	const method = "/library.v1.LibraryService/FindBooks"
+
+	newCall := func() (context.Context, *[]metadata.MD, *[]metadata.MD) {
+		var headers, trailers []metadata.MD
+		callMetadata := NewCallMetadata(method,
+			func(md metadata.MD) { headers = append(headers, md) },
+			func(md metadata.MD) { trailers = append(trailers, md) })
+		ctx := NewCallMetadataContext(context.Background(), metadata.Pairs("authorization", "Bearer token", "x-tenant-id", "acme"), callMetadata)
+		return ctx, &headers, &trailers
+	}
+
+	t.Run("Caller headers are incoming metadata", func(t *testing.T) {
+		ctx, _, _ := newCall()
+
+		md, ok := metadata.FromIncomingContext(ctx)
+		if !ok {
+			t.Fatal("Expected incoming metadata")
+		}
+		if got := md.Get("x-tenant-id"); !reflect.DeepEqual(got, []string{"acme"}) {
+			t.Errorf("Expected tenant [acme], got %v", got)
+		}
+		if got, _ := grpc.Method(ctx); got != method {
+			t.Errorf("Expected method %q, got %q", method, got)
+		}
+	})
+
+	t.Run("Header and trailer are delivered when the call finishes", func(t *testing.T) {
+		ctx, headers, trailers := newCall()
+
+		if err := grpc.SetHeader(ctx, metadata.Pairs("x-request-id", "1")); err != nil {
+			t.Fatalf("SetHeader failed: %v", err)
+		}
+		if err := grpc.SetTrailer(ctx, metadata.Pairs("x-cost", "3")); err != nil {
+			t.Fatalf("SetTrailer failed: %v", err)
+		}
+		if len(*headers) != 0 || len(*trailers) != 0 {
+			t.Fatal("Metadata should not be delivered before the call finishes")
+		}
+
+		FinishCall(ctx)
+
+		if !reflect.DeepEqual(*headers, []metadata.MD{metadata.Pairs("x-request-id", "1")}) {
+			t.Errorf("Unexpected headers: %v", *headers)
+		}
+		if !reflect.DeepEqual(*trailers, []metadata.MD{metadata.Pairs("x-cost", "3")}) {
+			t.Errorf("Unexpected trailers: %v", *trailers)
+		}
+	})
+
+	t.Run("SendHeader delivers immediately and only once", func(t *testing.T) {
+		ctx, headers, _ := newCall()
+
+		if err := grpc.SendHeader(ctx, metadata.Pairs("x-request-id", "1")); err != nil {
+			t.Fatalf("SendHeader failed: %v", err)
+		}
+		if len(*headers) != 1 {
+			t.Fatalf("Expected header to be delivered, got %v", *headers)
+		}
+		if err := grpc.SetHeader(ctx, metadata.Pairs("x-late", "1")); err == nil {
+			t.Error("Expected error setting header after it was sent")
+		}
+
+		SendPendingHeader(ctx)
+		FinishCall(ctx)
+		if len(*headers) != 1 {
+			t.Errorf("Expected header to be delivered once, got %v", *headers)
+		}
+	})
+
+	t.Run("Contexts without call metadata are ignored", func(t *testing.T) {
+		FinishCall(context.Background())
+		SendPendingHeader(context.Background())
+	})
+}
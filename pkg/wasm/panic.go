@@ -0,0 +1,49 @@
+// Package wasm provides runtime support for protoc-gen-go-wasmjs generated code.
+package wasm
+
+import (
+	"fmt"
+	"os"
+	"runtime/debug"
+)
+
+// IncludePanicStack controls whether errors built from recovered panics carry the
+// goroutine stack as a google.rpc.DebugInfo detail. It defaults to true in builds
+// tagged wasmjs_debug and false otherwise, so release builds don't leak stacks.
+var IncludePanicStack = debugBuild
+
+// PanicError converts a value recovered from a panic in method (the gRPC full
+// method name) into an INTERNAL status error.
+func PanicError(method string, recovered any) error {
+	return panicError(method, recovered, debug.Stack())
+}
+
+// RecoverPanic recovers a panic in an exported method call and hands it to onPanic
+// as an INTERNAL status error, keeping the WASM module alive. It must be deferred
+// directly by generated export wrappers and the goroutines they start:
+//
+//	defer wasm.RecoverPanic("/library.v1.LibraryService/FindBooks", func(err error) {
+//		result = createJSErrorResponse(err.Error(), err)
+//	})
+//
+// A panic inside onPanic itself (e.g., a throwing JavaScript callback) is logged and dropped.
+func RecoverPanic(method string, onPanic func(err error)) {
+	recovered := recover()
+	if recovered == nil {
+		return
+	}
+
+	stack := debug.Stack()
+	fmt.Fprintf(os.Stderr, "wasm: recovered panic in %s: %v\n%s", method, recovered, stack)
+	reportPanic(panicError(method, recovered, stack), onPanic)
+}
+
+// reportPanic calls onPanic, recovering any panic it raises.
+func reportPanic(err error, onPanic func(err error)) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			fmt.Fprintf(os.Stderr, "wasm: failed to report panic (%v): %v\n", err, recovered)
+		}
+	}()
+	onPanic(err)
+}
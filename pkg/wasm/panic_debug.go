@@ -0,0 +1,6 @@
+//go:build wasmjs_debug
+
+package wasm
+
+// debugBuild is set in builds tagged wasmjs_debug
+const debugBuild = true
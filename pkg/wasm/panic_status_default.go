@@ -0,0 +1,30 @@
+//go:build !tinygo
+
+package wasm
+
+import (
+	"fmt"
+	"strings"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// panicError builds the INTERNAL status for a recovered panic, attaching stack
+// as debug info when IncludePanicStack is set.
+func panicError(method string, recovered any, stack []byte) error {
+	st := status.Newf(codes.Internal, "panic in %s: %v", method, recovered)
+	if !IncludePanicStack {
+		return st.Err()
+	}
+
+	withStack, err := st.WithDetails(&errdetails.DebugInfo{
+		StackEntries: strings.Split(strings.TrimSpace(string(stack)), "\n"),
+		Detail:       fmt.Sprint(recovered),
+	})
+	if err != nil {
+		return st.Err()
+	}
+	return withStack.Err()
+}
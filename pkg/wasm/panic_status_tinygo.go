@@ -0,0 +1,12 @@
+//go:build tinygo
+
+package wasm
+
+// panicError builds the INTERNAL status for a recovered panic. TinyGo builds have no
+// status details, so the stack is appended to the message when IncludePanicStack is set.
+func panicError(method string, recovered any, stack []byte) error {
+	if !IncludePanicStack {
+		return Errorf(CodeInternal, "panic in %s: %v", method, recovered)
+	}
+	return Errorf(CodeInternal, "panic in %s: %v\n%s", method, recovered, stack)
+}
@@ -0,0 +1,70 @@
+// Package wasm provides runtime support for protoc-gen-go-wasmjs generated code.
+package wasm
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// TestRecoverPanic tests converting panics in exported methods into INTERNAL errors
+func TestRecoverPanic(t *testing.T) {
+	const method = "/library.v1.LibraryService/FindBooks"
+
+	// call runs fn the way a generated wrapper does and returns the reported error
+	call := func(fn func()) (reported error) {
+		defer RecoverPanic(method, func(err error) {
+			reported = err
+		})
+		fn()
+		return nil
+	}
+
+	t.Run("Panic becomes an INTERNAL error", func(t *testing.T) {
+		err := call(func() { panic("nil map write") })
+
+		st := status.Convert(err)
+		if st.Code() != codes.Internal {
+			t.Fatalf("Expected Internal, got %v", err)
+		}
+		if !strings.Contains(st.Message(), method) || !strings.Contains(st.Message(), "nil map write") {
+			t.Errorf("Expected message to name method and panic, got %q", st.Message())
+		}
+	})
+
+	t.Run("No panic reports nothing", func(t *testing.T) {
+		if err := call(func() {}); err != nil {
+			t.Errorf("Expected no error, got %v", err)
+		}
+	})
+
+	t.Run("Stack is attached only when enabled", func(t *testing.T) {
+		defer func(previous bool) { IncludePanicStack = previous }(IncludePanicStack)
+
+		IncludePanicStack = false
+		if details := status.Convert(call(func() { panic("boom") })).Details(); len(details) != 0 {
+			t.Errorf("Expected no details, got %v", details)
+		}
+
+		IncludePanicStack = true
+		details := status.Convert(call(func() { panic(errors.New("boom")) })).Details()
+		if len(details) != 1 {
+			t.Fatalf("Expected debug info detail, got %v", details)
+		}
+		debugInfo, ok := details[0].(*errdetails.DebugInfo)
+		if !ok || len(debugInfo.StackEntries) == 0 || debugInfo.Detail != "boom" {
+			t.Errorf("Expected debug info with stack, got %v", details[0])
+		}
+	})
+
+	t.Run("Panic while reporting is contained", func(t *testing.T) {
+		func() {
+			defer RecoverPanic(method, func(err error) { panic("callback threw") })
+			panic("boom")
+		}()
+	})
+}
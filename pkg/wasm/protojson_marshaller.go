@@ -1,3 +1,5 @@
+//go:build !tinygo
+
 // Package wasm provides runtime support for protoc-gen-go-wasmjs generated code.
 package wasm
 
@@ -8,7 +10,7 @@ import (
 
 // ProtojsonMarshaller implements ProtoMarshaller using protojson encoding.
 // This is the default marshaller and is compatible with most Go environments.
-// For TinyGo compatibility, consider using vtprotobuf or a custom marshaller.
+// It is left out of TinyGo builds, which default to ReflectMarshaller instead.
 type ProtojsonMarshaller struct{}
 
 // NewProtojsonMarshaller creates a new protojson-based marshaller.
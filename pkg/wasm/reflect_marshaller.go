@@ -0,0 +1,51 @@
+// Package wasm provides runtime support for protoc-gen-go-wasmjs generated code.
+package wasm
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// ReflectMarshaller implements ProtoMarshaller by walking messages with protoreflect,
+// following protojson's JSON mapping. Inside WASM it also converts messages straight
+// to and from JavaScript values (see MessageToJS), skipping the JSON text round-trip
+// that other marshallers need, which cuts allocations for high-frequency methods.
+//
+// Usage:
+//
+//	func main() {
+//	    wasm.SetGlobalMarshaller(wasm.NewReflectMarshaller())
+//	    // ... rest of your code
+//	}
+type ReflectMarshaller struct{}
+
+// NewReflectMarshaller creates a new protoreflect-based marshaller.
+func NewReflectMarshaller() *ReflectMarshaller {
+	return &ReflectMarshaller{}
+}
+
+// Marshal converts a proto message to JSON bytes.
+// The bytes decode to the same value as protojson's, though object keys are sorted.
+func (r *ReflectMarshaller) Marshal(m proto.Message, opts MarshalOptions) ([]byte, error) {
+	value, err := MessageToValue(m, opts)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(value)
+}
+
+// Unmarshal parses JSON bytes into a proto message.
+func (r *ReflectMarshaller) Unmarshal(data []byte, m proto.Message, opts UnmarshalOptions) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber() // Keep 64-bit integers exact
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return err
+	}
+	return MessageFromValue(value, m, opts)
+}
+
+// Ensure ReflectMarshaller implements ProtoMarshaller
+var _ ProtoMarshaller = (*ReflectMarshaller)(nil)
@@ -0,0 +1,92 @@
+// Copyright 2025 Sri Panyam
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build js && wasm
+
+package wasm
+
+import (
+	"sync"
+	"syscall/js"
+)
+
+// JSRegistration tracks the JavaScript functions and globals created when an exports
+// instance registers its API, so they can all be released when it is unregistered.
+// Without releasing them, every js.Func stays reachable from JavaScript forever and
+// reloading a module leaks memory.
+//
+// Example:
+//
+//	registration := wasm.NewJSRegistration()
+//	registration.SetGlobal("myApp", map[string]any{
+//	    "ping": registration.Func(func(this js.Value, args []js.Value) any { return "pong" }),
+//	})
+//
+//	// Later: removes window.myApp and releases the function
+//	registration.Release()
+type JSRegistration struct {
+	mu       sync.Mutex
+	funcs    []js.Func
+	globals  []string
+	done     chan struct{}
+	released bool
+}
+
+// NewJSRegistration creates an empty registration.
+func NewJSRegistration() *JSRegistration {
+	return &JSRegistration{done: make(chan struct{})}
+}
+
+// Func wraps fn in a js.Func that is released with the registration.
+func (r *JSRegistration) Func(fn func(this js.Value, args []js.Value) any) js.Func {
+	f := js.FuncOf(fn)
+	r.mu.Lock()
+	r.funcs = append(r.funcs, f)
+	r.mu.Unlock()
+	return f
+}
+
+// SetGlobal sets a property of the JavaScript global object that is deleted with the registration.
+func (r *JSRegistration) SetGlobal(name string, value any) {
+	js.Global().Set(name, value)
+	r.mu.Lock()
+	r.globals = append(r.globals, name)
+	r.mu.Unlock()
+}
+
+// Release deletes the registered globals, releases the registered functions and
+// closes Done. Calling it more than once has no effect.
+func (r *JSRegistration) Release() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.released {
+		return
+	}
+	r.released = true
+
+	// Remove the globals first so JavaScript can no longer reach released functions
+	for _, name := range r.globals {
+		js.Global().Delete(name)
+	}
+	for _, f := range r.funcs {
+		f.Release()
+	}
+	r.funcs, r.globals = nil, nil
+	close(r.done)
+}
+
+// Done returns a channel that is closed once the registration is released.
+func (r *JSRegistration) Done() <-chan struct{} {
+	return r.done
+}
@@ -0,0 +1,25 @@
+// Package wasm provides runtime support for protoc-gen-go-wasmjs generated code.
+package wasm
+
+// ErrorStatus is the structured form of a gRPC status that generated code passes
+// across the WASM boundary, so JavaScript callers can branch on the status code
+// instead of parsing error strings.
+type ErrorStatus struct {
+	// Code is the gRPC status code (e.g., codes.NotFound)
+	Code Code
+	// Message is the status message (without any "Service call failed" prefix)
+	Message string
+	// Details are the status details, in the order they were attached
+	Details []ErrorDetail
+}
+
+// ErrorDetail is a single status detail, carried as the contents of its Any.
+type ErrorDetail struct {
+	// TypeURL is the Any type URL (e.g., "type.googleapis.com/google.rpc.BadRequest")
+	TypeURL string
+	// Value is the serialized detail message
+	Value []byte
+	// JSON is the detail message encoded with the global marshaller.
+	// It is nil when the detail type is not linked into the WASM binary.
+	JSON []byte
+}
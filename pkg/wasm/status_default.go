@@ -0,0 +1,42 @@
+//go:build !tinygo
+
+package wasm
+
+import "google.golang.org/grpc/status"
+
+// StatusFromError converts an error returned by a service into a gRPC status.
+// Status errors keep their code, message and details; context errors map to
+// DeadlineExceeded or Canceled, and any other error maps to Unknown.
+func StatusFromError(err error) *status.Status {
+	if st, ok := status.FromError(err); ok {
+		return st
+	}
+	return status.FromContextError(err)
+}
+
+// NewErrorStatus builds the structured status for an error returned by a service.
+func NewErrorStatus(err error) *ErrorStatus {
+	st := StatusFromError(err)
+	result := &ErrorStatus{
+		Code:    st.Code(),
+		Message: st.Message(),
+	}
+
+	for _, detail := range st.Proto().GetDetails() {
+		errorDetail := ErrorDetail{
+			TypeURL: detail.GetTypeUrl(),
+			Value:   detail.GetValue(),
+		}
+
+		// Decode details whose types are known so callers don't have to
+		if msg, err := detail.UnmarshalNew(); err == nil {
+			if detailJSON, err := GetMarshaller(msg).Marshal(msg, MarshalOptions{}); err == nil {
+				errorDetail.JSON = detailJSON
+			}
+		}
+
+		result.Details = append(result.Details, errorDetail)
+	}
+
+	return result
+}
@@ -0,0 +1,85 @@
+// Package wasm provides runtime support for protoc-gen-go-wasmjs generated code.
+package wasm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+// TestNewErrorStatus tests conversion of service errors into structured statuses
+func TestNewErrorStatus(t *testing.T) {
+	t.Run("Status error keeps code and message", func(t *testing.T) {
+		result := NewErrorStatus(status.Error(codes.NotFound, "book not found"))
+
+		if result.Code != codes.NotFound {
+			t.Errorf("Expected code NotFound, got %v", result.Code)
+		}
+		if result.Message != "book not found" {
+			t.Errorf("Expected message 'book not found', got %q", result.Message)
+		}
+		if len(result.Details) != 0 {
+			t.Errorf("Expected no details, got %d", len(result.Details))
+		}
+	})
+
+	t.Run("Wrapped status error is detected", func(t *testing.T) {
+		err := fmt.Errorf("lookup: %w", status.Error(codes.PermissionDenied, "no access"))
+		result := NewErrorStatus(err)
+
+		if result.Code != codes.PermissionDenied {
+			t.Errorf("Expected code PermissionDenied, got %v", result.Code)
+		}
+	})
+
+	t.Run("Plain error maps to Unknown", func(t *testing.T) {
+		result := NewErrorStatus(errors.New("boom"))
+
+		if result.Code != codes.Unknown {
+			t.Errorf("Expected code Unknown, got %v", result.Code)
+		}
+		if result.Message != "boom" {
+			t.Errorf("Expected message 'boom', got %q", result.Message)
+		}
+	})
+
+	t.Run("Context errors map to their codes", func(t *testing.T) {
+		if code := NewErrorStatus(context.DeadlineExceeded).Code; code != codes.DeadlineExceeded {
+			t.Errorf("Expected code DeadlineExceeded, got %v", code)
+		}
+		if code := NewErrorStatus(context.Canceled).Code; code != codes.Canceled {
+			t.Errorf("Expected code Canceled, got %v", code)
+		}
+	})
+
+	t.Run("Details are passed through and decoded", func(t *testing.T) {
+		st, err := status.New(codes.InvalidArgument, "bad request").WithDetails(wrapperspb.String("title"))
+		if err != nil {
+			t.Fatalf("WithDetails failed: %v", err)
+		}
+
+		result := NewErrorStatus(st.Err())
+		if len(result.Details) != 1 {
+			t.Fatalf("Expected 1 detail, got %d", len(result.Details))
+		}
+
+		detail := result.Details[0]
+		if detail.TypeURL != "type.googleapis.com/google.protobuf.StringValue" {
+			t.Errorf("Unexpected type URL: %s", detail.TypeURL)
+		}
+
+		decoded := &wrapperspb.StringValue{}
+		if err := proto.Unmarshal(detail.Value, decoded); err != nil || decoded.GetValue() != "title" {
+			t.Errorf("Detail value did not round trip: %v %v", decoded, err)
+		}
+		if string(detail.JSON) != `"title"` {
+			t.Errorf("Expected decoded JSON \"title\", got %s", detail.JSON)
+		}
+	})
+}
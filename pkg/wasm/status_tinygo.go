@@ -0,0 +1,26 @@
+//go:build tinygo
+
+package wasm
+
+import (
+	"context"
+	"errors"
+)
+
+// NewErrorStatus builds the structured status for an error returned by a service.
+// Errors from Errorf keep their code and message; context errors map to
+// DeadlineExceeded or Canceled, and any other error maps to Unknown. TinyGo builds
+// have no gRPC status details, so Details is empty.
+func NewErrorStatus(err error) *ErrorStatus {
+	var st *statusError
+	switch {
+	case errors.As(err, &st):
+		return &ErrorStatus{Code: st.code, Message: st.message}
+	case errors.Is(err, context.DeadlineExceeded):
+		return &ErrorStatus{Code: CodeDeadlineExceeded, Message: err.Error()}
+	case errors.Is(err, context.Canceled):
+		return &ErrorStatus{Code: CodeCanceled, Message: err.Error()}
+	default:
+		return &ErrorStatus{Code: CodeUnknown, Message: err.Error()}
+	}
+}
@@ -0,0 +1,93 @@
+// Package wasm provides runtime support for protoc-gen-go-wasmjs generated code.
+package wasm
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+)
+
+// ErrStreamSendClosed is returned when JavaScript sends a request after closing its side of the stream.
+var ErrStreamSendClosed = errors.New("stream send side is closed")
+
+// StreamQueue buffers the requests JavaScript sends to a client streaming or
+// bidirectional streaming method until the method receives them.
+//
+// Push and Close are called from js.FuncOf handlers, so they never block:
+// the queue grows as needed instead of stalling the JavaScript event loop.
+type StreamQueue[T any] struct {
+	mu     sync.Mutex
+	items  []T
+	closed bool
+	ready  chan struct{} // Signalled (non-blocking) when an item is pushed or the queue is closed
+}
+
+// NewStreamQueue creates an empty, open stream queue.
+func NewStreamQueue[T any]() *StreamQueue[T] {
+	return &StreamQueue[T]{
+		ready: make(chan struct{}, 1),
+	}
+}
+
+// Push appends a request sent by JavaScript.
+// It returns ErrStreamSendClosed once the send side has been closed.
+func (q *StreamQueue[T]) Push(item T) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.closed {
+		return ErrStreamSendClosed
+	}
+	q.items = append(q.items, item)
+	q.signal()
+	return nil
+}
+
+// Close marks the send side as closed (JavaScript called closeSend).
+// Requests already queued are still delivered before Recv returns io.EOF.
+func (q *StreamQueue[T]) Close() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.closed = true
+	q.signal()
+}
+
+// Recv returns the next request, blocking until one is available.
+// It returns io.EOF once the queue is closed and drained, and the
+// context's error if ctx is done first.
+func (q *StreamQueue[T]) Recv(ctx context.Context) (T, error) {
+	for {
+		q.mu.Lock()
+		if len(q.items) > 0 {
+			item := q.items[0]
+			var zero T
+			q.items[0] = zero
+			q.items = q.items[1:]
+			q.mu.Unlock()
+			return item, nil
+		}
+		closed := q.closed
+		q.mu.Unlock()
+
+		var zero T
+		if closed {
+			return zero, io.EOF
+		}
+
+		select {
+		case <-q.ready:
+		case <-ctx.Done():
+			return zero, ctx.Err()
+		}
+	}
+}
+
+// signal wakes up a pending Recv. Must be called with q.mu held.
+func (q *StreamQueue[T]) signal() {
+	select {
+	case q.ready <- struct{}{}:
+	default:
+	}
+}
@@ -0,0 +1,62 @@
+// Package wasm provides runtime support for protoc-gen-go-wasmjs generated code.
+package wasm
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+)
+
+// TestStreamQueue tests buffering of requests sent by JavaScript to streaming methods
+func TestStreamQueue(t *testing.T) {
+	t.Run("Delivers requests in order then EOF after close", func(t *testing.T) {
+		queue := NewStreamQueue[string]()
+		queue.Push("a")
+		queue.Push("b")
+		queue.Close()
+
+		for _, expected := range []string{"a", "b"} {
+			item, err := queue.Recv(context.Background())
+			if err != nil || item != expected {
+				t.Fatalf("Expected %q, got %q (err: %v)", expected, item, err)
+			}
+		}
+		if _, err := queue.Recv(context.Background()); err != io.EOF {
+			t.Errorf("Expected io.EOF after close, got %v", err)
+		}
+	})
+
+	t.Run("Push after close fails", func(t *testing.T) {
+		queue := NewStreamQueue[string]()
+		queue.Close()
+
+		if err := queue.Push("late"); !errors.Is(err, ErrStreamSendClosed) {
+			t.Errorf("Expected ErrStreamSendClosed, got %v", err)
+		}
+	})
+
+	t.Run("Recv waits for a later push", func(t *testing.T) {
+		queue := NewStreamQueue[int]()
+		go func() {
+			time.Sleep(10 * time.Millisecond)
+			queue.Push(42)
+		}()
+
+		item, err := queue.Recv(context.Background())
+		if err != nil || item != 42 {
+			t.Errorf("Expected 42, got %d (err: %v)", item, err)
+		}
+	})
+
+	t.Run("Recv stops when the context is cancelled", func(t *testing.T) {
+		queue := NewStreamQueue[int]()
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		if _, err := queue.Recv(ctx); !errors.Is(err, context.Canceled) {
+			t.Errorf("Expected context.Canceled, got %v", err)
+		}
+	})
+}
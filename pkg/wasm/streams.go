@@ -0,0 +1,54 @@
+// Package wasm provides runtime support for protoc-gen-go-wasmjs generated code.
+package wasm
+
+import "context"
+
+// ServerStreamingServer is the server side of a server-streaming method in code generated
+// with target=tinygo, which leaves gRPC out of the module. It has the methods of
+// grpc.ServerStreamingServer that WASM exports support. Service interfaces generated
+// with target=tinygo take it instead of the grpc type, so implementations written
+// against grpc.ServerStreamingServer must change their parameter type to build.
+type ServerStreamingServer[Res any] interface {
+	// Send sends a response to the JavaScript caller.
+	Send(*Res) error
+
+	// Context returns the call's context, done once the caller aborts or the call times out.
+	Context() context.Context
+}
+
+// ClientStreamingServer is the server side of a client-streaming method in code generated
+// with target=tinygo; the counterpart of grpc.ClientStreamingServer.
+type ClientStreamingServer[Req any, Res any] interface {
+	// Recv returns the next request sent by JavaScript, or io.EOF once it closed the send side.
+	Recv() (*Req, error)
+
+	// SendAndClose sends the response to the JavaScript caller.
+	SendAndClose(*Res) error
+
+	// Context returns the call's context, done once the caller aborts or the call times out.
+	Context() context.Context
+}
+
+// BidiStreamingServer is the server side of a bidirectional streaming method in code
+// generated with target=tinygo; the counterpart of grpc.BidiStreamingServer.
+type BidiStreamingServer[Req any, Res any] interface {
+	// Recv returns the next request sent by JavaScript, or io.EOF once it closed the send side.
+	Recv() (*Req, error)
+
+	// Send sends a response to the JavaScript caller.
+	Send(*Res) error
+
+	// Context returns the call's context, done once the caller aborts or the call times out.
+	Context() context.Context
+}
+
+// ServerStreamingClient is the stream browser clients generated with target=tinygo return
+// for server-streaming methods of browser-provided services; the counterpart of
+// grpc.ServerStreamingClient.
+type ServerStreamingClient[Res any] interface {
+	// Recv returns the next message sent by JavaScript, or io.EOF once the stream ended.
+	Recv() (*Res, error)
+
+	// Context returns the stream's context.
+	Context() context.Context
+}
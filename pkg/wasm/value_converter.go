@@ -0,0 +1,1120 @@
+// Package wasm provides runtime support for protoc-gen-go-wasmjs generated code.
+package wasm
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+)
+
+// This file converts proto messages to and from untyped values (JSON-like trees)
+// by walking them with protoreflect, following protojson's JSON mapping exactly.
+// The walk is generic over the value representation, so the same code builds
+// js.Value objects inside WASM (see js_values.go) and plain Go values elsewhere.
+
+// maxValueDepth bounds message nesting, guarding against cyclic JavaScript objects.
+// It matches protojson's default recursion limit.
+const maxValueDepth = 10000
+
+// valueKind is the JSON type of an untyped value
+type valueKind int
+
+const (
+	nullValue valueKind = iota
+	boolValue
+	numberValue
+	stringValue
+	arrayValue
+	objectValue
+)
+
+// valueWriter builds untyped values of type V
+type valueWriter[V any] interface {
+	null() V
+	boolean(b bool) V
+	number(f float64) V
+	str(s string) V
+	array(items []V) V
+	// object builds an object whose keys keep the given order
+	object(keys []string, values []V) V
+}
+
+// valueReader inspects untyped values of type V
+type valueReader[V any] interface {
+	kind(v V) (valueKind, error)
+	boolOf(v V) bool
+	// numberText returns a number as its decimal text, preserving precision where the
+	// representation has more than a float64
+	numberText(v V) string
+	stringOf(v V) string
+	length(v V) int
+	index(v V, i int) V
+	keys(v V) []string
+	get(v V, key string) V
+}
+
+// MessageToValue converts a message into the Go value encoding/json would decode
+// from its protojson encoding: map[string]any, []any, string, float64, bool or nil.
+func MessageToValue(m proto.Message, opts MarshalOptions) (any, error) {
+	return marshalValue[any](goValues{}, m, opts)
+}
+
+// MessageFromValue fills a message from a Go value as decoded by encoding/json
+// (json.Number values are accepted), following protojson's JSON mapping.
+func MessageFromValue(value any, m proto.Message, opts UnmarshalOptions) error {
+	return unmarshalValue[any](goValues{}, value, m, opts)
+}
+
+// marshalValue converts a message into an untyped value built by w
+func marshalValue[V any](w valueWriter[V], m proto.Message, opts MarshalOptions) (V, error) {
+	return valueEncoder[V]{w: w, opts: opts}.message(m.ProtoReflect(), "", 0)
+}
+
+// unmarshalValue fills a message from an untyped value inspected by r
+func unmarshalValue[V any](r valueReader[V], value V, m proto.Message, opts UnmarshalOptions) error {
+	proto.Reset(m)
+	if err := (valueDecoder[V]{r: r, opts: opts}).message(value, m.ProtoReflect(), false, 0); err != nil {
+		return err
+	}
+	if opts.AllowPartial {
+		return nil
+	}
+	return proto.CheckInitialized(m)
+}
+
+// =============================================================================
+// Encoding
+// =============================================================================
+
+// valueEncoder converts messages into untyped values
+type valueEncoder[V any] struct {
+	w    valueWriter[V]
+	opts MarshalOptions
+}
+
+// message converts m, adding an "@type" entry first when typeURL is set (for Any)
+func (e valueEncoder[V]) message(m protoreflect.Message, typeURL string, depth int) (V, error) {
+	if depth > maxValueDepth {
+		var zero V
+		return zero, errors.New("exceeded max recursion depth")
+	}
+	if isWellKnownType(m.Descriptor().FullName()) {
+		return e.wellKnown(m, depth)
+	}
+
+	var keys []string
+	var values []V
+	if typeURL != "" {
+		keys = append(keys, "@type")
+		values = append(values, e.w.str(typeURL))
+	}
+
+	add := func(fd protoreflect.FieldDescriptor, v protoreflect.Value) error {
+		name := fd.JSONName()
+		if e.opts.UseProtoNames {
+			name = fd.TextName()
+		}
+		value, err := e.field(v, fd, depth)
+		if err != nil {
+			return err
+		}
+		keys = append(keys, name)
+		values = append(values, value)
+		return nil
+	}
+
+	// Fields in declaration order, as protojson emits them
+	fds := m.Descriptor().Fields()
+	for i := 0; i < fds.Len(); i++ {
+		fd := fds.Get(i)
+		v := m.Get(fd)
+		if !m.Has(fd) {
+			if !e.opts.EmitUnpopulated || fd.ContainingOneof() != nil {
+				continue
+			}
+			if fd.HasPresence() {
+				v = protoreflect.Value{} // Emitted as null
+			}
+		}
+		if err := add(fd, v); err != nil {
+			var zero V
+			return zero, err
+		}
+	}
+
+	// Extensions follow, ordered by full name
+	var extensions []protoreflect.FieldDescriptor
+	m.Range(func(fd protoreflect.FieldDescriptor, _ protoreflect.Value) bool {
+		if fd.IsExtension() {
+			extensions = append(extensions, fd)
+		}
+		return true
+	})
+	sort.Slice(extensions, func(i, j int) bool { return extensions[i].FullName() < extensions[j].FullName() })
+	for _, fd := range extensions {
+		if err := add(fd, m.Get(fd)); err != nil {
+			var zero V
+			return zero, err
+		}
+	}
+
+	return e.w.object(keys, values), nil
+}
+
+// field converts a field value, which may be a list or map
+func (e valueEncoder[V]) field(v protoreflect.Value, fd protoreflect.FieldDescriptor, depth int) (V, error) {
+	var zero V
+	switch {
+	case fd.IsList():
+		list := v.List()
+		items := make([]V, list.Len())
+		for i := range items {
+			item, err := e.singular(list.Get(i), fd, depth)
+			if err != nil {
+				return zero, err
+			}
+			items[i] = item
+		}
+		return e.w.array(items), nil
+
+	case fd.IsMap():
+		mapKeys := sortedMapKeys(v.Map())
+		keys := make([]string, len(mapKeys))
+		values := make([]V, len(mapKeys))
+		for i, key := range mapKeys {
+			value, err := e.singular(v.Map().Get(key), fd.MapValue(), depth)
+			if err != nil {
+				return zero, err
+			}
+			keys[i] = key.String()
+			values[i] = value
+		}
+		return e.w.object(keys, values), nil
+
+	default:
+		return e.singular(v, fd, depth)
+	}
+}
+
+// singular converts a non-repeated value of field fd
+func (e valueEncoder[V]) singular(v protoreflect.Value, fd protoreflect.FieldDescriptor, depth int) (V, error) {
+	var zero V
+	if !v.IsValid() {
+		return e.w.null(), nil
+	}
+
+	switch fd.Kind() {
+	case protoreflect.BoolKind:
+		return e.w.boolean(v.Bool()), nil
+	case protoreflect.StringKind:
+		if !utf8.ValidString(v.String()) {
+			return zero, fmt.Errorf("field %v contains invalid UTF-8", fd.FullName())
+		}
+		return e.w.str(v.String()), nil
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		return e.w.number(float64(v.Int())), nil
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		return e.w.number(float64(v.Uint())), nil
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind,
+		protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		// 64-bit integers are strings, as JavaScript numbers can't hold them exactly
+		return e.w.str(v.String()), nil
+	case protoreflect.FloatKind:
+		return e.float(v.Float(), 32), nil
+	case protoreflect.DoubleKind:
+		return e.float(v.Float(), 64), nil
+	case protoreflect.BytesKind:
+		return e.w.str(base64.StdEncoding.EncodeToString(v.Bytes())), nil
+	case protoreflect.EnumKind:
+		if fd.Enum().FullName() == "google.protobuf.NullValue" {
+			return e.w.null(), nil
+		}
+		desc := fd.Enum().Values().ByNumber(v.Enum())
+		if e.opts.UseEnumNumbers || desc == nil {
+			return e.w.number(float64(v.Enum())), nil
+		}
+		return e.w.str(string(desc.Name())), nil
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		return e.message(v.Message(), "", depth+1)
+	default:
+		return zero, fmt.Errorf("field %v has unknown kind %v", fd.FullName(), fd.Kind())
+	}
+}
+
+// float converts a float, writing the special values as strings.
+// Floats are rounded to their shortest 32-bit representation, as protojson writes them.
+func (e valueEncoder[V]) float(f float64, bitSize int) V {
+	switch {
+	case math.IsNaN(f):
+		return e.w.str("NaN")
+	case math.IsInf(f, 1):
+		return e.w.str("Infinity")
+	case math.IsInf(f, -1):
+		return e.w.str("-Infinity")
+	}
+	if bitSize == 32 {
+		f, _ = strconv.ParseFloat(strconv.FormatFloat(f, 'g', -1, 32), 64)
+	}
+	return e.w.number(f)
+}
+
+// wellKnown converts the well-known types that have a special JSON mapping
+func (e valueEncoder[V]) wellKnown(m protoreflect.Message, depth int) (V, error) {
+	var zero V
+	fds := m.Descriptor().Fields()
+	name := m.Descriptor().FullName()
+
+	switch name {
+	case "google.protobuf.Any":
+		return e.any(m, depth)
+
+	case "google.protobuf.Timestamp":
+		secs, nanos := m.Get(fds.ByNumber(1)).Int(), m.Get(fds.ByNumber(2)).Int()
+		if secs < minTimestampSeconds || secs > maxTimestampSeconds {
+			return zero, fmt.Errorf("%s: seconds out of range %v", name, secs)
+		}
+		if nanos < 0 || nanos > 1e9 {
+			return zero, fmt.Errorf("%s: nanos out of range %v", name, nanos)
+		}
+		formatted := time.Unix(secs, nanos).UTC().Format("2006-01-02T15:04:05.000000000")
+		return e.w.str(trimFraction(formatted) + "Z"), nil
+
+	case "google.protobuf.Duration":
+		secs, nanos := m.Get(fds.ByNumber(1)).Int(), m.Get(fds.ByNumber(2)).Int()
+		if secs < -maxDurationSeconds || secs > maxDurationSeconds {
+			return zero, fmt.Errorf("%s: seconds out of range %v", name, secs)
+		}
+		if nanos < -1e9 || nanos > 1e9 {
+			return zero, fmt.Errorf("%s: nanos out of range %v", name, nanos)
+		}
+		if (secs > 0 && nanos < 0) || (secs < 0 && nanos > 0) {
+			return zero, fmt.Errorf("%s: signs of seconds and nanos do not match", name)
+		}
+		sign := ""
+		if secs < 0 || nanos < 0 {
+			sign, secs, nanos = "-", -secs, -nanos
+		}
+		return e.w.str(trimFraction(fmt.Sprintf("%s%d.%09d", sign, secs, nanos)) + "s"), nil
+
+	case "google.protobuf.Struct", "google.protobuf.ListValue":
+		fd := fds.ByNumber(1)
+		return e.field(m.Get(fd), fd, depth)
+
+	case "google.protobuf.Value":
+		fd := m.WhichOneof(m.Descriptor().Oneofs().ByName("kind"))
+		if fd == nil {
+			return zero, fmt.Errorf("%s: none of the oneof fields is set", name)
+		}
+		if fd.Kind() == protoreflect.DoubleKind {
+			if f := m.Get(fd).Float(); math.IsNaN(f) || math.IsInf(f, 0) {
+				return zero, fmt.Errorf("%s: invalid number_value %v", name, f)
+			}
+		}
+		return e.singular(m.Get(fd), fd, depth)
+
+	case "google.protobuf.FieldMask":
+		list := m.Get(fds.ByNumber(1)).List()
+		paths := make([]string, list.Len())
+		for i := range paths {
+			path := list.Get(i).String()
+			if !protoreflect.FullName(path).IsValid() {
+				return zero, fmt.Errorf("%s contains invalid path: %q", name, path)
+			}
+			camel := jsonCamelCase(path)
+			if jsonSnakeCase(camel) != path {
+				return zero, fmt.Errorf("%s contains irreversible value %q", name, path)
+			}
+			paths[i] = camel
+		}
+		return e.w.str(strings.Join(paths, ",")), nil
+
+	case "google.protobuf.Empty":
+		return e.w.object(nil, nil), nil
+
+	default: // Wrappers
+		fd := fds.ByNumber(1)
+		return e.singular(m.Get(fd), fd, depth)
+	}
+}
+
+// any converts an Any into the fields of the embedded message plus "@type",
+// or into { "@type", "value" } when the embedded message is itself a well-known type.
+func (e valueEncoder[V]) any(m protoreflect.Message, depth int) (V, error) {
+	var zero V
+	fds := m.Descriptor().Fields()
+	fdType, fdValue := fds.ByNumber(1), fds.ByNumber(2)
+
+	if !m.Has(fdType) {
+		if m.Has(fdValue) {
+			return zero, errors.New("google.protobuf.Any: type_url is not set")
+		}
+		return e.w.object(nil, nil), nil
+	}
+
+	typeURL := m.Get(fdType).String()
+	embedded, err := newAnyMessage(typeURL, m.Get(fdValue).Bytes())
+	if err != nil {
+		return zero, err
+	}
+
+	if isWellKnownType(embedded.Descriptor().FullName()) {
+		value, err := e.wellKnown(embedded, depth+1)
+		if err != nil {
+			return zero, err
+		}
+		return e.w.object([]string{"@type", "value"}, []V{e.w.str(typeURL), value}), nil
+	}
+	return e.message(embedded, typeURL, depth+1)
+}
+
+// =============================================================================
+// Decoding
+// =============================================================================
+
+// valueDecoder fills messages from untyped values
+type valueDecoder[V any] struct {
+	r    valueReader[V]
+	opts UnmarshalOptions
+}
+
+// message fills m from an object, ignoring "@type" when skipTypeURL is set (for Any)
+func (d valueDecoder[V]) message(v V, m protoreflect.Message, skipTypeURL bool, depth int) error {
+	if depth > maxValueDepth {
+		return errors.New("exceeded max recursion depth")
+	}
+	desc := m.Descriptor()
+	if isWellKnownType(desc.FullName()) {
+		return d.wellKnown(v, m, depth)
+	}
+	if err := d.expect(v, objectValue, desc.FullName()); err != nil {
+		return err
+	}
+
+	seenOneofs := map[int]bool{}
+	for _, name := range d.r.keys(v) {
+		if skipTypeURL && name == "@type" {
+			continue
+		}
+
+		// Fields are matched by JSON name or proto name; extensions by [full.name]
+		var fd protoreflect.FieldDescriptor
+		if strings.HasPrefix(name, "[") && strings.HasSuffix(name, "]") {
+			extType, err := protoregistry.GlobalTypes.FindExtensionByName(protoreflect.FullName(name[1 : len(name)-1]))
+			if err != nil && err != protoregistry.NotFound {
+				return fmt.Errorf("unable to resolve %s: %v", name, err)
+			}
+			if extType != nil {
+				fd = extType.TypeDescriptor()
+				if !desc.ExtensionRanges().Has(fd.Number()) || fd.ContainingMessage().FullName() != desc.FullName() {
+					return fmt.Errorf("message %v cannot be extended by %v", desc.FullName(), fd.FullName())
+				}
+			}
+		} else if fd = desc.Fields().ByJSONName(name); fd == nil {
+			fd = desc.Fields().ByTextName(name)
+		}
+		if fd == nil {
+			if d.opts.DiscardUnknown {
+				continue
+			}
+			return fmt.Errorf("%v: unknown field %q", desc.FullName(), name)
+		}
+
+		value := d.r.get(v, name)
+		kind, err := d.r.kind(value)
+		if err != nil {
+			return err
+		}
+		// null leaves the field unset, except for the types that represent null
+		if kind == nullValue && !isKnownValue(fd) && !isNullValue(fd) {
+			continue
+		}
+
+		switch {
+		case fd.IsList():
+			if err := d.list(value, m.Mutable(fd).List(), fd, depth); err != nil {
+				return err
+			}
+		case fd.IsMap():
+			if err := d.mapField(value, m.Mutable(fd).Map(), fd, depth); err != nil {
+				return err
+			}
+		default:
+			if od := fd.ContainingOneof(); od != nil {
+				if seenOneofs[od.Index()] {
+					return fmt.Errorf("error parsing %q, oneof %v is already set", name, od.FullName())
+				}
+				seenOneofs[od.Index()] = true
+			}
+			if err := d.setSingular(value, m, fd, depth); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// setSingular sets a non-repeated field of m
+func (d valueDecoder[V]) setSingular(v V, m protoreflect.Message, fd protoreflect.FieldDescriptor, depth int) error {
+	if fd.Message() != nil {
+		value := m.NewField(fd)
+		if err := d.message(v, value.Message(), false, depth+1); err != nil {
+			return err
+		}
+		m.Set(fd, value)
+		return nil
+	}
+
+	value, err := d.scalar(v, fd)
+	if err != nil {
+		return err
+	}
+	if value.IsValid() {
+		m.Set(fd, value)
+	}
+	return nil
+}
+
+// list appends the items of an array to a repeated field
+func (d valueDecoder[V]) list(v V, list protoreflect.List, fd protoreflect.FieldDescriptor, depth int) error {
+	if err := d.expect(v, arrayValue, fd.FullName()); err != nil {
+		return err
+	}
+	for i := 0; i < d.r.length(v); i++ {
+		item := d.r.index(v, i)
+		if fd.Message() != nil {
+			value := list.NewElement()
+			if err := d.message(item, value.Message(), false, depth+1); err != nil {
+				return err
+			}
+			list.Append(value)
+			continue
+		}
+
+		value, err := d.scalar(item, fd)
+		if err != nil {
+			return err
+		}
+		if value.IsValid() {
+			list.Append(value)
+		}
+	}
+	return nil
+}
+
+// mapField adds the entries of an object to a map field
+func (d valueDecoder[V]) mapField(v V, mmap protoreflect.Map, fd protoreflect.FieldDescriptor, depth int) error {
+	if err := d.expect(v, objectValue, fd.FullName()); err != nil {
+		return err
+	}
+	keyDesc, valueDesc := fd.MapKey(), fd.MapValue()
+	for _, name := range d.r.keys(v) {
+		key, err := parseMapKey(name, keyDesc)
+		if err != nil {
+			return err
+		}
+		item := d.r.get(v, name)
+
+		if valueDesc.Message() != nil {
+			value := mmap.NewValue()
+			if err := d.message(item, value.Message(), false, depth+1); err != nil {
+				return err
+			}
+			mmap.Set(key, value)
+			continue
+		}
+
+		value, err := d.scalar(item, valueDesc)
+		if err != nil {
+			return err
+		}
+		if value.IsValid() {
+			mmap.Set(key, value)
+		}
+	}
+	return nil
+}
+
+// scalar converts a value for a non-message field. It returns an invalid value
+// for unknown enum names when unknown values are discarded.
+func (d valueDecoder[V]) scalar(v V, fd protoreflect.FieldDescriptor) (protoreflect.Value, error) {
+	kind, err := d.r.kind(v)
+	if err != nil {
+		return protoreflect.Value{}, err
+	}
+
+	switch fd.Kind() {
+	case protoreflect.BoolKind:
+		if kind == boolValue {
+			return protoreflect.ValueOfBool(d.r.boolOf(v)), nil
+		}
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		if n, ok := d.integer(v, kind, 32); ok {
+			return protoreflect.ValueOfInt32(int32(n)), nil
+		}
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		if n, ok := d.integer(v, kind, 64); ok {
+			return protoreflect.ValueOfInt64(n), nil
+		}
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		if n, ok := d.unsigned(v, kind, 32); ok {
+			return protoreflect.ValueOfUint32(uint32(n)), nil
+		}
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		if n, ok := d.unsigned(v, kind, 64); ok {
+			return protoreflect.ValueOfUint64(n), nil
+		}
+	case protoreflect.FloatKind:
+		if f, ok := d.float(v, kind, 32); ok {
+			return protoreflect.ValueOfFloat32(float32(f)), nil
+		}
+	case protoreflect.DoubleKind:
+		if f, ok := d.float(v, kind, 64); ok {
+			return protoreflect.ValueOfFloat64(f), nil
+		}
+	case protoreflect.StringKind:
+		if kind == stringValue {
+			return protoreflect.ValueOfString(d.r.stringOf(v)), nil
+		}
+	case protoreflect.BytesKind:
+		if kind == stringValue {
+			if b, ok := decodeBase64(d.r.stringOf(v)); ok {
+				return protoreflect.ValueOfBytes(b), nil
+			}
+		}
+	case protoreflect.EnumKind:
+		switch kind {
+		case stringValue:
+			if enumValue := fd.Enum().Values().ByName(protoreflect.Name(d.r.stringOf(v))); enumValue != nil {
+				return protoreflect.ValueOfEnum(enumValue.Number()), nil
+			}
+			if d.opts.DiscardUnknown {
+				return protoreflect.Value{}, nil
+			}
+		case numberValue:
+			if n, ok := parseInteger(d.r.numberText(v), 32); ok {
+				return protoreflect.ValueOfEnum(protoreflect.EnumNumber(n)), nil
+			}
+		case nullValue:
+			if isNullValue(fd) {
+				return protoreflect.ValueOfEnum(0), nil
+			}
+		}
+	}
+
+	return protoreflect.Value{}, fmt.Errorf("invalid value for %v field %v", fd.Kind(), fd.JSONName())
+}
+
+// integer reads a signed integer from a number or a numeric string
+func (d valueDecoder[V]) integer(v V, kind valueKind, bitSize int) (int64, bool) {
+	switch kind {
+	case numberValue:
+		return parseInteger(d.r.numberText(v), bitSize)
+	case stringValue:
+		return parseInteger(d.r.stringOf(v), bitSize)
+	}
+	return 0, false
+}
+
+// unsigned reads an unsigned integer from a number or a numeric string
+func (d valueDecoder[V]) unsigned(v V, kind valueKind, bitSize int) (uint64, bool) {
+	switch kind {
+	case numberValue:
+		return parseUnsigned(d.r.numberText(v), bitSize)
+	case stringValue:
+		return parseUnsigned(d.r.stringOf(v), bitSize)
+	}
+	return 0, false
+}
+
+// float reads a float from a number, a numeric string or one of the special values
+func (d valueDecoder[V]) float(v V, kind valueKind, bitSize int) (float64, bool) {
+	var text string
+	switch kind {
+	case numberValue:
+		text = d.r.numberText(v)
+	case stringValue:
+		text = d.r.stringOf(v)
+		switch text {
+		case "NaN":
+			return math.NaN(), true
+		case "Infinity":
+			return math.Inf(1), true
+		case "-Infinity":
+			return math.Inf(-1), true
+		}
+		if strings.TrimSpace(text) != text {
+			return 0, false
+		}
+	default:
+		return 0, false
+	}
+
+	f, err := strconv.ParseFloat(text, bitSize)
+	if err != nil || math.IsInf(f, 0) || math.IsNaN(f) {
+		return 0, false
+	}
+	return f, true
+}
+
+// wellKnown fills the well-known types that have a special JSON mapping
+func (d valueDecoder[V]) wellKnown(v V, m protoreflect.Message, depth int) error {
+	fds := m.Descriptor().Fields()
+	name := m.Descriptor().FullName()
+
+	switch name {
+	case "google.protobuf.Any":
+		return d.any(v, m, depth)
+
+	case "google.protobuf.Timestamp":
+		if err := d.expect(v, stringValue, name); err != nil {
+			return err
+		}
+		t, err := time.Parse(time.RFC3339Nano, d.r.stringOf(v))
+		if err != nil {
+			return fmt.Errorf("invalid %v value %q", name, d.r.stringOf(v))
+		}
+		if t.Unix() < minTimestampSeconds || t.Unix() > maxTimestampSeconds {
+			return fmt.Errorf("%v value out of range: %q", name, d.r.stringOf(v))
+		}
+		m.Set(fds.ByNumber(1), protoreflect.ValueOfInt64(t.Unix()))
+		m.Set(fds.ByNumber(2), protoreflect.ValueOfInt32(int32(t.Nanosecond())))
+		return nil
+
+	case "google.protobuf.Duration":
+		if err := d.expect(v, stringValue, name); err != nil {
+			return err
+		}
+		secs, nanos, ok := parseDuration(d.r.stringOf(v))
+		if !ok {
+			return fmt.Errorf("invalid %v value %q", name, d.r.stringOf(v))
+		}
+		m.Set(fds.ByNumber(1), protoreflect.ValueOfInt64(secs))
+		m.Set(fds.ByNumber(2), protoreflect.ValueOfInt32(nanos))
+		return nil
+
+	case "google.protobuf.Struct":
+		return d.mapField(v, m.Mutable(fds.ByNumber(1)).Map(), fds.ByNumber(1), depth)
+
+	case "google.protobuf.ListValue":
+		return d.list(v, m.Mutable(fds.ByNumber(1)).List(), fds.ByNumber(1), depth)
+
+	case "google.protobuf.Value":
+		kind, err := d.r.kind(v)
+		if err != nil {
+			return err
+		}
+		switch kind {
+		case nullValue:
+			m.Set(fds.ByName("null_value"), protoreflect.ValueOfEnum(0))
+		case boolValue:
+			m.Set(fds.ByName("bool_value"), protoreflect.ValueOfBool(d.r.boolOf(v)))
+		case numberValue:
+			f, ok := d.float(v, kind, 64)
+			if !ok {
+				return fmt.Errorf("invalid %v number %v", name, d.r.numberText(v))
+			}
+			m.Set(fds.ByName("number_value"), protoreflect.ValueOfFloat64(f))
+		case stringValue:
+			m.Set(fds.ByName("string_value"), protoreflect.ValueOfString(d.r.stringOf(v)))
+		case arrayValue:
+			fd := fds.ByName("list_value")
+			value := m.NewField(fd)
+			if err := d.message(v, value.Message(), false, depth+1); err != nil {
+				return err
+			}
+			m.Set(fd, value)
+		case objectValue:
+			fd := fds.ByName("struct_value")
+			value := m.NewField(fd)
+			if err := d.message(v, value.Message(), false, depth+1); err != nil {
+				return err
+			}
+			m.Set(fd, value)
+		}
+		return nil
+
+	case "google.protobuf.FieldMask":
+		if err := d.expect(v, stringValue, name); err != nil {
+			return err
+		}
+		text := strings.TrimSpace(d.r.stringOf(v))
+		if text == "" {
+			return nil
+		}
+		paths := m.Mutable(fds.ByNumber(1)).List()
+		for _, camel := range strings.Split(text, ",") {
+			path := jsonSnakeCase(camel)
+			if !protoreflect.FullName(path).IsValid() {
+				return fmt.Errorf("%v contains invalid path: %q", name, camel)
+			}
+			paths.Append(protoreflect.ValueOfString(path))
+		}
+		return nil
+
+	case "google.protobuf.Empty":
+		if err := d.expect(v, objectValue, name); err != nil {
+			return err
+		}
+		if keys := d.r.keys(v); len(keys) > 0 && !d.opts.DiscardUnknown {
+			return fmt.Errorf("%v: unknown field %q", name, keys[0])
+		}
+		return nil
+
+	default: // Wrappers
+		fd := fds.ByNumber(1)
+		return d.setSingular(v, m, fd, depth)
+	}
+}
+
+// any fills an Any from the embedded message's fields plus "@type", or from
+// { "@type", "value" } when the embedded message is itself a well-known type.
+func (d valueDecoder[V]) any(v V, m protoreflect.Message, depth int) error {
+	if err := d.expect(v, objectValue, m.Descriptor().FullName()); err != nil {
+		return err
+	}
+	keys := d.r.keys(v)
+	if len(keys) == 0 {
+		return nil
+	}
+
+	typeValue := d.r.get(v, "@type")
+	if kind, err := d.r.kind(typeValue); err != nil || kind != stringValue {
+		return errors.New(`google.protobuf.Any: missing or invalid "@type" field`)
+	}
+	typeURL := d.r.stringOf(typeValue)
+	messageType, err := protoregistry.GlobalTypes.FindMessageByURL(typeURL)
+	if err != nil {
+		return fmt.Errorf("google.protobuf.Any: unable to resolve %q: %v", typeURL, err)
+	}
+
+	embedded := messageType.New()
+	if isWellKnownType(embedded.Descriptor().FullName()) {
+		for _, key := range keys {
+			if key != "@type" && key != "value" && !d.opts.DiscardUnknown {
+				return fmt.Errorf("google.protobuf.Any: unknown field %q", key)
+			}
+		}
+		err = d.wellKnown(d.r.get(v, "value"), embedded, depth+1)
+	} else {
+		err = d.message(v, embedded, true, depth+1)
+	}
+	if err != nil {
+		return err
+	}
+
+	value, err := proto.MarshalOptions{AllowPartial: true, Deterministic: true}.Marshal(embedded.Interface())
+	if err != nil {
+		return fmt.Errorf("google.protobuf.Any: unable to marshal %q: %v", typeURL, err)
+	}
+	fds := m.Descriptor().Fields()
+	m.Set(fds.ByNumber(1), protoreflect.ValueOfString(typeURL))
+	m.Set(fds.ByNumber(2), protoreflect.ValueOfBytes(value))
+	return nil
+}
+
+// expect checks that v has the wanted JSON type
+func (d valueDecoder[V]) expect(v V, want valueKind, name protoreflect.FullName) error {
+	kind, err := d.r.kind(v)
+	if err != nil {
+		return err
+	}
+	if kind != want {
+		return fmt.Errorf("invalid value for %v: expected %s, got %s", name, want, kind)
+	}
+	return nil
+}
+
+// String returns the JSON name of the value kind
+func (k valueKind) String() string {
+	switch k {
+	case nullValue:
+		return "null"
+	case boolValue:
+		return "boolean"
+	case numberValue:
+		return "number"
+	case stringValue:
+		return "string"
+	case arrayValue:
+		return "array"
+	default:
+		return "object"
+	}
+}
+
+// =============================================================================
+// Go values
+// =============================================================================
+
+// goValues reads and writes the Go values produced by encoding/json
+type goValues struct{}
+
+func (goValues) null() any                 { return nil }
+func (goValues) boolean(b bool) any        { return b }
+func (goValues) number(f float64) any      { return f }
+func (goValues) str(s string) any          { return s }
+func (goValues) array(items []any) any     { return items }
+func (goValues) numberText(v any) string   { return fmt.Sprint(v) }
+func (goValues) length(v any) int          { return len(v.([]any)) }
+func (goValues) index(v any, i int) any    { return v.([]any)[i] }
+func (goValues) get(v any, key string) any { return v.(map[string]any)[key] }
+
+func (goValues) object(keys []string, values []any) any {
+	object := make(map[string]any, len(keys))
+	for i, key := range keys {
+		object[key] = values[i]
+	}
+	return object
+}
+
+func (goValues) kind(v any) (valueKind, error) {
+	switch v := v.(type) {
+	case nil:
+		return nullValue, nil
+	case bool:
+		return boolValue, nil
+	case float64, json.Number:
+		return numberValue, nil
+	case string:
+		return stringValue, nil
+	case []any:
+		return arrayValue, nil
+	case map[string]any:
+		return objectValue, nil
+	default:
+		return 0, fmt.Errorf("unsupported value type %T", v)
+	}
+}
+
+func (goValues) boolOf(v any) bool     { return v.(bool) }
+func (goValues) stringOf(v any) string { return v.(string) }
+
+// keys returns the object keys sorted, so conversion errors are deterministic
+func (goValues) keys(v any) []string {
+	object := v.(map[string]any)
+	keys := make([]string, 0, len(object))
+	for key := range object {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// =============================================================================
+// Helpers
+// =============================================================================
+
+const (
+	minTimestampSeconds = -62135596800 // 0001-01-01T00:00:00Z
+	maxTimestampSeconds = 253402300799 // 9999-12-31T23:59:59Z
+	maxDurationSeconds  = 315576000000 // 10000 years
+)
+
+// isWellKnownType reports whether messages of the named type have a special JSON mapping
+func isWellKnownType(name protoreflect.FullName) bool {
+	switch name {
+	case "google.protobuf.Any", "google.protobuf.Timestamp", "google.protobuf.Duration",
+		"google.protobuf.Struct", "google.protobuf.ListValue", "google.protobuf.Value",
+		"google.protobuf.FieldMask", "google.protobuf.Empty",
+		"google.protobuf.BoolValue", "google.protobuf.Int32Value", "google.protobuf.Int64Value",
+		"google.protobuf.UInt32Value", "google.protobuf.UInt64Value", "google.protobuf.FloatValue",
+		"google.protobuf.DoubleValue", "google.protobuf.StringValue", "google.protobuf.BytesValue":
+		return true
+	}
+	return false
+}
+
+// isKnownValue reports whether fd holds a google.protobuf.Value, which represents null
+func isKnownValue(fd protoreflect.FieldDescriptor) bool {
+	return fd.Message() != nil && fd.Message().FullName() == "google.protobuf.Value"
+}
+
+// isNullValue reports whether fd holds a google.protobuf.NullValue
+func isNullValue(fd protoreflect.FieldDescriptor) bool {
+	return fd.Enum() != nil && fd.Enum().FullName() == "google.protobuf.NullValue"
+}
+
+// newAnyMessage resolves the type of an Any and unmarshals its value
+func newAnyMessage(typeURL string, value []byte) (protoreflect.Message, error) {
+	messageType, err := protoregistry.GlobalTypes.FindMessageByURL(typeURL)
+	if err != nil {
+		return nil, fmt.Errorf("google.protobuf.Any: unable to resolve %q: %v", typeURL, err)
+	}
+	embedded := messageType.New()
+	if err := (proto.UnmarshalOptions{AllowPartial: true}).Unmarshal(value, embedded.Interface()); err != nil {
+		return nil, fmt.Errorf("google.protobuf.Any: unable to unmarshal %q: %v", typeURL, err)
+	}
+	return embedded, nil
+}
+
+// sortedMapKeys returns the keys of a map in protojson's order: false before true,
+// numbers ascending and strings by code point
+func sortedMapKeys(mmap protoreflect.Map) []protoreflect.MapKey {
+	keys := make([]protoreflect.MapKey, 0, mmap.Len())
+	mmap.Range(func(key protoreflect.MapKey, _ protoreflect.Value) bool {
+		keys = append(keys, key)
+		return true
+	})
+	sort.Slice(keys, func(i, j int) bool {
+		x, y := keys[i], keys[j]
+		switch x.Interface().(type) {
+		case bool:
+			return !x.Bool() && y.Bool()
+		case int32, int64:
+			return x.Int() < y.Int()
+		case uint32, uint64:
+			return x.Uint() < y.Uint()
+		default:
+			return x.String() < y.String()
+		}
+	})
+	return keys
+}
+
+// parseMapKey parses an object key into a map key of the field's key kind
+func parseMapKey(name string, fd protoreflect.FieldDescriptor) (protoreflect.MapKey, error) {
+	switch fd.Kind() {
+	case protoreflect.StringKind:
+		return protoreflect.ValueOfString(name).MapKey(), nil
+	case protoreflect.BoolKind:
+		switch name {
+		case "true":
+			return protoreflect.ValueOfBool(true).MapKey(), nil
+		case "false":
+			return protoreflect.ValueOfBool(false).MapKey(), nil
+		}
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		if n, err := strconv.ParseInt(name, 10, 32); err == nil {
+			return protoreflect.ValueOfInt32(int32(n)).MapKey(), nil
+		}
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		if n, err := strconv.ParseInt(name, 10, 64); err == nil {
+			return protoreflect.ValueOfInt64(n).MapKey(), nil
+		}
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		if n, err := strconv.ParseUint(name, 10, 32); err == nil {
+			return protoreflect.ValueOfUint32(uint32(n)).MapKey(), nil
+		}
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		if n, err := strconv.ParseUint(name, 10, 64); err == nil {
+			return protoreflect.ValueOfUint64(n).MapKey(), nil
+		}
+	}
+	return protoreflect.MapKey{}, fmt.Errorf("invalid map key %q for field %v", name, fd.FullName())
+}
+
+// parseInteger parses a signed integer, accepting exponent forms of whole numbers
+func parseInteger(text string, bitSize int) (int64, bool) {
+	if n, err := strconv.ParseInt(text, 10, bitSize); err == nil {
+		return n, true
+	}
+	f, err := strconv.ParseFloat(text, 64)
+	if err != nil || f != math.Trunc(f) || f < -math.Ldexp(1, bitSize-1) || f >= math.Ldexp(1, bitSize-1) {
+		return 0, false
+	}
+	return int64(f), true
+}
+
+// parseUnsigned parses an unsigned integer, accepting exponent forms of whole numbers
+func parseUnsigned(text string, bitSize int) (uint64, bool) {
+	if n, err := strconv.ParseUint(text, 10, bitSize); err == nil {
+		return n, true
+	}
+	f, err := strconv.ParseFloat(text, 64)
+	if err != nil || f != math.Trunc(f) || f < 0 || f >= math.Ldexp(1, bitSize) {
+		return 0, false
+	}
+	return uint64(f), true
+}
+
+// parseDuration parses a duration string such as "-1.5s" into seconds and nanos
+func parseDuration(text string) (int64, int32, bool) {
+	if !strings.HasSuffix(text, "s") {
+		return 0, 0, false
+	}
+	text = strings.TrimSuffix(text, "s")
+	negative := strings.HasPrefix(text, "-")
+	text = strings.TrimPrefix(text, "-")
+
+	whole, fraction, hasFraction := strings.Cut(text, ".")
+	if whole == "" || strings.ContainsAny(whole, "+-") || (hasFraction && (fraction == "" || len(fraction) > 9)) {
+		return 0, 0, false
+	}
+	secs, err := strconv.ParseInt(whole, 10, 64)
+	if err != nil || secs > maxDurationSeconds {
+		return 0, 0, false
+	}
+	var nanos int64
+	if hasFraction {
+		if strings.ContainsAny(fraction, "+-") {
+			return 0, 0, false
+		}
+		if nanos, err = strconv.ParseInt(fraction+strings.Repeat("0", 9-len(fraction)), 10, 32); err != nil {
+			return 0, 0, false
+		}
+	}
+	if negative {
+		secs, nanos = -secs, -nanos
+	}
+	return secs, int32(nanos), true
+}
+
+// trimFraction trims a 9-digit fraction to 6, 3 or 0 digits where possible
+func trimFraction(text string) string {
+	text = strings.TrimSuffix(text, "000")
+	text = strings.TrimSuffix(text, "000")
+	return strings.TrimSuffix(text, ".000")
+}
+
+// decodeBase64 decodes standard or URL-safe base64, with or without padding
+func decodeBase64(text string) ([]byte, bool) {
+	encoding := base64.StdEncoding
+	if strings.ContainsAny(text, "-_") {
+		encoding = base64.URLEncoding
+	}
+	if len(text)%4 != 0 {
+		encoding = encoding.WithPadding(base64.NoPadding)
+	}
+	b, err := encoding.DecodeString(text)
+	return b, err == nil
+}
+
+// jsonCamelCase converts a snake_case field path to lowerCamelCase, as protojson does for field masks
+func jsonCamelCase(text string) string {
+	var b []byte
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		if c != '_' {
+			if i > 0 && text[i-1] == '_' && 'a' <= c && c <= 'z' {
+				c -= 'a' - 'A'
+			}
+			b = append(b, c)
+		}
+	}
+	return string(b)
+}
+
+// jsonSnakeCase converts a lowerCamelCase field path to snake_case, as protojson does for field masks
+func jsonSnakeCase(text string) string {
+	var b []byte
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		if 'A' <= c && c <= 'Z' {
+			b = append(b, '_')
+			c += 'a' - 'A'
+		}
+		b = append(b, c)
+	}
+	return string(b)
+}
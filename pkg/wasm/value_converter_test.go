@@ -0,0 +1,389 @@
+// Package wasm provides runtime support for protoc-gen-go-wasmjs generated code.
+package wasm
+
+import (
+	"encoding/json"
+	"math"
+	"reflect"
+	"testing"
+	"time"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+// newKitchenSinkDescriptor builds a proto3 message covering every field kind,
+// presence, oneofs, maps and the well-known types with special JSON mappings.
+func newKitchenSinkDescriptor(t *testing.T) protoreflect.MessageDescriptor {
+	t.Helper()
+
+	field := func(name string, number int32, fieldType descriptorpb.FieldDescriptorProto_Type, typeName string) *descriptorpb.FieldDescriptorProto {
+		f := &descriptorpb.FieldDescriptorProto{
+			Name:   proto.String(name),
+			Number: proto.Int32(number),
+			Type:   fieldType.Enum(),
+			Label:  descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+		}
+		if typeName != "" {
+			f.TypeName = proto.String(typeName)
+		}
+		return f
+	}
+	repeated := func(f *descriptorpb.FieldDescriptorProto) *descriptorpb.FieldDescriptorProto {
+		f.Label = descriptorpb.FieldDescriptorProto_LABEL_REPEATED.Enum()
+		return f
+	}
+	inOneof := func(f *descriptorpb.FieldDescriptorProto, index int32) *descriptorpb.FieldDescriptorProto {
+		f.OneofIndex = proto.Int32(index)
+		return f
+	}
+	mapEntry := func(name string, key, value *descriptorpb.FieldDescriptorProto) *descriptorpb.DescriptorProto {
+		return &descriptorpb.DescriptorProto{
+			Name:    proto.String(name),
+			Field:   []*descriptorpb.FieldDescriptorProto{key, value},
+			Options: &descriptorpb.MessageOptions{MapEntry: proto.Bool(true)},
+		}
+	}
+
+	optionalName := field("optional_name", 30, descriptorpb.FieldDescriptorProto_TYPE_STRING, "")
+	optionalName.Proto3Optional = proto.Bool(true)
+
+	file := &descriptorpb.FileDescriptorProto{
+		Name:    proto.String("wasm_test/kitchen_sink.proto"),
+		Package: proto.String("wasm_test"),
+		Syntax:  proto.String("proto3"),
+		Dependency: []string{
+			"google/protobuf/any.proto", "google/protobuf/duration.proto", "google/protobuf/field_mask.proto",
+			"google/protobuf/struct.proto", "google/protobuf/timestamp.proto", "google/protobuf/wrappers.proto",
+			"google/protobuf/empty.proto",
+		},
+		EnumType: []*descriptorpb.EnumDescriptorProto{{
+			Name: proto.String("Shelf"),
+			Value: []*descriptorpb.EnumValueDescriptorProto{
+				{Name: proto.String("SHELF_UNSPECIFIED"), Number: proto.Int32(0)},
+				{Name: proto.String("SHELF_FICTION"), Number: proto.Int32(1)},
+			},
+		}},
+		MessageType: []*descriptorpb.DescriptorProto{{
+			Name: proto.String("KitchenSink"),
+			Field: []*descriptorpb.FieldDescriptorProto{
+				field("flag", 1, descriptorpb.FieldDescriptorProto_TYPE_BOOL, ""),
+				field("int32_value", 2, descriptorpb.FieldDescriptorProto_TYPE_INT32, ""),
+				field("sint32_value", 3, descriptorpb.FieldDescriptorProto_TYPE_SINT32, ""),
+				field("uint32_value", 4, descriptorpb.FieldDescriptorProto_TYPE_UINT32, ""),
+				field("int64_value", 5, descriptorpb.FieldDescriptorProto_TYPE_INT64, ""),
+				field("uint64_value", 6, descriptorpb.FieldDescriptorProto_TYPE_FIXED64, ""),
+				field("float_value", 7, descriptorpb.FieldDescriptorProto_TYPE_FLOAT, ""),
+				field("double_value", 8, descriptorpb.FieldDescriptorProto_TYPE_DOUBLE, ""),
+				field("title", 9, descriptorpb.FieldDescriptorProto_TYPE_STRING, ""),
+				field("cover", 10, descriptorpb.FieldDescriptorProto_TYPE_BYTES, ""),
+				field("shelf", 11, descriptorpb.FieldDescriptorProto_TYPE_ENUM, ".wasm_test.Shelf"),
+				field("child", 12, descriptorpb.FieldDescriptorProto_TYPE_MESSAGE, ".wasm_test.KitchenSink"),
+				repeated(field("tags", 13, descriptorpb.FieldDescriptorProto_TYPE_STRING, "")),
+				repeated(field("ratings", 14, descriptorpb.FieldDescriptorProto_TYPE_FLOAT, "")),
+				repeated(field("shelves", 15, descriptorpb.FieldDescriptorProto_TYPE_ENUM, ".wasm_test.Shelf")),
+				repeated(field("children", 16, descriptorpb.FieldDescriptorProto_TYPE_MESSAGE, ".wasm_test.KitchenSink")),
+				repeated(field("counts", 17, descriptorpb.FieldDescriptorProto_TYPE_MESSAGE, ".wasm_test.KitchenSink.CountsEntry")),
+				repeated(field("names", 18, descriptorpb.FieldDescriptorProto_TYPE_MESSAGE, ".wasm_test.KitchenSink.NamesEntry")),
+				repeated(field("flags", 19, descriptorpb.FieldDescriptorProto_TYPE_MESSAGE, ".wasm_test.KitchenSink.FlagsEntry")),
+				inOneof(field("isbn", 20, descriptorpb.FieldDescriptorProto_TYPE_STRING, ""), 0),
+				inOneof(field("catalog_id", 21, descriptorpb.FieldDescriptorProto_TYPE_INT64, ""), 0),
+				field("any", 22, descriptorpb.FieldDescriptorProto_TYPE_MESSAGE, ".google.protobuf.Any"),
+				field("published_at", 23, descriptorpb.FieldDescriptorProto_TYPE_MESSAGE, ".google.protobuf.Timestamp"),
+				field("read_time", 24, descriptorpb.FieldDescriptorProto_TYPE_MESSAGE, ".google.protobuf.Duration"),
+				field("update_mask", 25, descriptorpb.FieldDescriptorProto_TYPE_MESSAGE, ".google.protobuf.FieldMask"),
+				field("attributes", 26, descriptorpb.FieldDescriptorProto_TYPE_MESSAGE, ".google.protobuf.Struct"),
+				field("extra", 27, descriptorpb.FieldDescriptorProto_TYPE_MESSAGE, ".google.protobuf.Value"),
+				field("subtitle", 28, descriptorpb.FieldDescriptorProto_TYPE_MESSAGE, ".google.protobuf.StringValue"),
+				field("page_count", 29, descriptorpb.FieldDescriptorProto_TYPE_MESSAGE, ".google.protobuf.Int64Value"),
+				inOneof(optionalName, 1),
+				field("empty", 31, descriptorpb.FieldDescriptorProto_TYPE_MESSAGE, ".google.protobuf.Empty"),
+				repeated(field("anys", 32, descriptorpb.FieldDescriptorProto_TYPE_MESSAGE, ".google.protobuf.Any")),
+			},
+			NestedType: []*descriptorpb.DescriptorProto{
+				mapEntry("CountsEntry",
+					field("key", 1, descriptorpb.FieldDescriptorProto_TYPE_STRING, ""),
+					field("value", 2, descriptorpb.FieldDescriptorProto_TYPE_INT64, "")),
+				mapEntry("NamesEntry",
+					field("key", 1, descriptorpb.FieldDescriptorProto_TYPE_INT32, ""),
+					field("value", 2, descriptorpb.FieldDescriptorProto_TYPE_MESSAGE, ".wasm_test.KitchenSink")),
+				mapEntry("FlagsEntry",
+					field("key", 1, descriptorpb.FieldDescriptorProto_TYPE_BOOL, ""),
+					field("value", 2, descriptorpb.FieldDescriptorProto_TYPE_ENUM, ".wasm_test.Shelf")),
+			},
+			OneofDecl: []*descriptorpb.OneofDescriptorProto{
+				{Name: proto.String("identifier")},
+				{Name: proto.String("_optional_name")},
+			},
+		}},
+	}
+
+	fd, err := protodesc.NewFile(file, protoregistry.GlobalFiles)
+	if err != nil {
+		t.Fatalf("Failed to build test descriptor: %v", err)
+	}
+	return fd.Messages().Get(0)
+}
+
+// newKitchenSink fills a kitchen sink message with representative values
+func newKitchenSink(t *testing.T, desc protoreflect.MessageDescriptor) proto.Message {
+	t.Helper()
+
+	parse := func(m proto.Message, text string) {
+		if err := protojson.Unmarshal([]byte(text), m); err != nil {
+			t.Fatalf("Failed to build test message: %v", err)
+		}
+	}
+
+	anyBook, _ := anypb.New(&descriptorpb.EnumValueDescriptorProto{Name: proto.String("FICTION"), Number: proto.Int32(1)})
+	anyTime, _ := anypb.New(timestamppb.New(time.Date(2025, 1, 2, 3, 4, 5, 600000000, time.UTC)))
+
+	m := dynamicpb.NewMessage(desc)
+	parse(m, `{
+		"flag": true,
+		"int32Value": -42,
+		"sint32Value": -7,
+		"uint32Value": 4000000000,
+		"int64Value": "-9007199254740993",
+		"uint64Value": "18446744073709551615",
+		"floatValue": 1.1,
+		"doubleValue": 0.1,
+		"title": "Dune – ☃",
+		"cover": "AQID/w==",
+		"shelf": "SHELF_FICTION",
+		"child": {"title": "child", "ratings": ["NaN", "Infinity", "-Infinity", 2.5]},
+		"tags": ["a", "b"],
+		"shelves": ["SHELF_FICTION", 7],
+		"children": [{"flag": true}, {}],
+		"counts": {"b": "2", "a": "1"},
+		"names": {"10": {"title": "ten"}, "-2": {"title": "minus two"}, "3": {}},
+		"flags": {"true": "SHELF_FICTION", "false": "SHELF_UNSPECIFIED"},
+		"catalogId": "123",
+		"publishedAt": "2025-01-02T03:04:05.120Z",
+		"readTime": "-1.500s",
+		"updateMask": "title,publishedAt",
+		"attributes": {"pages": 412, "nested": {"list": [1, "two", null, false]}},
+		"extra": null,
+		"subtitle": "",
+		"pageCount": "412",
+		"optionalName": "",
+		"empty": {}
+	}`)
+
+	anys := m.Mutable(desc.Fields().ByName("anys")).List()
+	for _, a := range []*anypb.Any{anyBook, anyTime} {
+		anys.Append(protoreflect.ValueOfMessage(a.ProtoReflect()))
+	}
+	m.Set(desc.Fields().ByName("any"), protoreflect.ValueOfMessage(anyBook.ProtoReflect()))
+	return m
+}
+
+// protojsonValue returns the protojson encoding of m as decoded by encoding/json
+func protojsonValue(t *testing.T, m proto.Message, opts MarshalOptions) any {
+	t.Helper()
+
+	data, err := protojson.MarshalOptions{
+		UseProtoNames:   opts.UseProtoNames,
+		EmitUnpopulated: opts.EmitUnpopulated,
+		UseEnumNumbers:  opts.UseEnumNumbers,
+	}.Marshal(m)
+	if err != nil {
+		t.Fatalf("protojson failed: %v", err)
+	}
+	var value any
+	if err := json.Unmarshal(data, &value); err != nil {
+		t.Fatalf("Failed to decode protojson output: %v", err)
+	}
+	return value
+}
+
+// TestMessageToValue tests that the protoreflect converter matches protojson's JSON mapping
+func TestMessageToValue(t *testing.T) {
+	desc := newKitchenSinkDescriptor(t)
+	sink := newKitchenSink(t, desc)
+
+	optionSets := map[string]MarshalOptions{
+		"Default":          {},
+		"EmitUnpopulated":  {EmitUnpopulated: true},
+		"UseProtoNames":    {UseProtoNames: true},
+		"UseEnumNumbers":   {UseEnumNumbers: true},
+		"Generated export": {EmitUnpopulated: true, UseProtoNames: false, UseEnumNumbers: false},
+	}
+
+	for name, opts := range optionSets {
+		t.Run(name+" matches protojson", func(t *testing.T) {
+			for _, m := range []proto.Message{sink, dynamicpb.NewMessage(desc)} {
+				got, err := MessageToValue(m, opts)
+				if err != nil {
+					t.Fatalf("MessageToValue failed: %v", err)
+				}
+				if expected := protojsonValue(t, m, opts); !reflect.DeepEqual(got, expected) {
+					t.Errorf("Output differs from protojson\n got: %v\nwant: %v", got, expected)
+				}
+			}
+		})
+	}
+
+	t.Run("Invalid well-known values fail like protojson", func(t *testing.T) {
+		invalid := []proto.Message{
+			&timestamppb.Timestamp{Seconds: maxTimestampSeconds + 1},
+			&durationpb.Duration{Seconds: 1, Nanos: -1},
+			&structpb.Value{},
+			structpb.NewNumberValue(math.NaN()),
+			&fieldmaskpb.FieldMask{Paths: []string{"bad__path"}},
+			&anypb.Any{Value: []byte{1}},
+		}
+		for _, m := range invalid {
+			if _, err := MessageToValue(m, MarshalOptions{}); err == nil {
+				t.Errorf("Expected error converting %v", m)
+			}
+			if _, err := protojson.Marshal(m); err == nil {
+				t.Errorf("Expected protojson to reject %v too", m)
+			}
+		}
+	})
+}
+
+// TestMessageFromValue tests filling messages from untyped values
+func TestMessageFromValue(t *testing.T) {
+	desc := newKitchenSinkDescriptor(t)
+	sink := newKitchenSink(t, desc)
+
+	t.Run("Decodes protojson output like protojson for every option set", func(t *testing.T) {
+		for _, opts := range []MarshalOptions{{}, {EmitUnpopulated: true}, {UseProtoNames: true}, {UseEnumNumbers: true}} {
+			value := protojsonValue(t, sink, opts)
+
+			// protojson itself decodes an emitted null Value as NullValue, so compare with its decoding
+			data, _ := json.Marshal(value)
+			expected := dynamicpb.NewMessage(desc)
+			if err := protojson.Unmarshal(data, expected); err != nil {
+				t.Fatalf("protojson failed with %+v: %v", opts, err)
+			}
+
+			got := dynamicpb.NewMessage(desc)
+			if err := MessageFromValue(value, got, UnmarshalOptions{}); err != nil {
+				t.Fatalf("MessageFromValue failed with %+v: %v", opts, err)
+			}
+			if !proto.Equal(got, expected) {
+				t.Errorf("Decoding with %+v differs\n got: %v\nwant: %v", opts, got, expected)
+			}
+		}
+	})
+
+	t.Run("Accepts the same inputs as protojson", func(t *testing.T) {
+		inputs := []string{
+			`{"int32_value": "12", "int64Value": 5e3, "uint32Value": 1.0, "floatValue": "1.5"}`,
+			`{"shelf": 1, "cover": "AQID_w", "title": null, "child": null}`,
+			`{"extra": null, "attributes": {}, "anys": []}`,
+			`{"any": {"@type": "type.googleapis.com/google.protobuf.Duration", "value": "1s"}}`,
+			`{"readTime": "0.000000001s", "publishedAt": "2025-01-02T03:04:05+02:00"}`,
+		}
+		for _, input := range inputs {
+			expected := dynamicpb.NewMessage(desc)
+			if err := protojson.Unmarshal([]byte(input), expected); err != nil {
+				t.Fatalf("protojson rejected %s: %v", input, err)
+			}
+
+			var value any
+			if err := json.Unmarshal([]byte(input), &value); err != nil {
+				t.Fatal(err)
+			}
+			got := dynamicpb.NewMessage(desc)
+			if err := MessageFromValue(value, got, UnmarshalOptions{}); err != nil {
+				t.Fatalf("MessageFromValue rejected %s: %v", input, err)
+			}
+			if !proto.Equal(got, expected) {
+				t.Errorf("Input %s\n got: %v\nwant: %v", input, got, expected)
+			}
+		}
+	})
+
+	t.Run("Rejects invalid inputs", func(t *testing.T) {
+		inputs := []string{
+			`{"unknown": 1}`,
+			`{"int32Value": 1.5}`,
+			`{"int32Value": 3000000000}`,
+			`{"flag": "true"}`,
+			`{"shelf": "SHELF_UNKNOWN"}`,
+			`{"isbn": "x", "catalogId": "1"}`,
+			`{"tags": "a"}`,
+			`{"names": {"x": {}}}`,
+			`{"readTime": "1m"}`,
+			`[]`,
+		}
+		for _, input := range inputs {
+			var value any
+			if err := json.Unmarshal([]byte(input), &value); err != nil {
+				t.Fatal(err)
+			}
+			if err := MessageFromValue(value, dynamicpb.NewMessage(desc), UnmarshalOptions{}); err == nil {
+				t.Errorf("Expected error for %s", input)
+			}
+		}
+	})
+
+	t.Run("DiscardUnknown skips unknown fields and enum names", func(t *testing.T) {
+		value := map[string]any{"unknown": 1.0, "shelf": "SHELF_UNKNOWN", "title": "kept"}
+		got := dynamicpb.NewMessage(desc)
+		if err := MessageFromValue(value, got, UnmarshalOptions{DiscardUnknown: true}); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if title := got.Get(desc.Fields().ByName("title")).String(); title != "kept" {
+			t.Errorf("Expected title 'kept', got %q", title)
+		}
+	})
+
+	t.Run("Well-known types decode at the top level", func(t *testing.T) {
+		got := &wrapperspb.Int64Value{}
+		if err := MessageFromValue(json.Number("9007199254740993"), got, UnmarshalOptions{}); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if got.Value != 9007199254740993 {
+			t.Errorf("Expected exact 64-bit value, got %d", got.Value)
+		}
+		if err := MessageFromValue(map[string]any{}, &emptypb.Empty{}, UnmarshalOptions{}); err != nil {
+			t.Errorf("Unexpected error decoding Empty: %v", err)
+		}
+	})
+}
+
+// TestReflectMarshaller tests the protoreflect marshaller through the ProtoMarshaller interface
+func TestReflectMarshaller(t *testing.T) {
+	desc := newKitchenSinkDescriptor(t)
+	sink := newKitchenSink(t, desc)
+	marshaller := NewReflectMarshaller()
+
+	data, err := marshaller.Marshal(sink, MarshalOptions{})
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+
+	var got, expected any
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("Marshal produced invalid JSON: %v", err)
+	}
+	expected = protojsonValue(t, sink, MarshalOptions{})
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("Marshal output differs from protojson\n got: %s", data)
+	}
+
+	decoded := dynamicpb.NewMessage(desc)
+	if err := marshaller.Unmarshal(data, decoded, UnmarshalOptions{}); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if !proto.Equal(decoded, sink) {
+		t.Errorf("Round trip differs\n got: %v\nwant: %v", decoded, sink)
+	}
+}
@@ -255,6 +255,229 @@ func (x *AsyncMethodOptions) GetIsAsync() bool {
 	return false
 }
 
+// Configuration for method and service timeouts
+type TimeoutOptions struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Timeout in milliseconds (0 means not set at this level)
+	TimeoutMs uint32 `protobuf:"varint,1,opt,name=timeout_ms,json=timeoutMs,proto3" json:"timeout_ms,omitempty"`
+	// Disable the timeout entirely; the call runs until it completes or is cancelled
+	NoTimeout     bool `protobuf:"varint,2,opt,name=no_timeout,json=noTimeout,proto3" json:"no_timeout,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TimeoutOptions) Reset() {
+	*x = TimeoutOptions{}
+	mi := &file_wasmjs_v1_annotations_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TimeoutOptions) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TimeoutOptions) ProtoMessage() {}
+
+func (x *TimeoutOptions) ProtoReflect() protoreflect.Message {
+	mi := &file_wasmjs_v1_annotations_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TimeoutOptions.ProtoReflect.Descriptor instead.
+func (*TimeoutOptions) Descriptor() ([]byte, []int) {
+	return file_wasmjs_v1_annotations_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *TimeoutOptions) GetTimeoutMs() uint32 {
+	if x != nil {
+		return x.TimeoutMs
+	}
+	return 0
+}
+
+func (x *TimeoutOptions) GetNoTimeout() bool {
+	if x != nil {
+		return x.NoTimeout
+	}
+	return false
+}
+
+// JSON encoding options, mirroring protojson's MarshalOptions and UnmarshalOptions.
+// Unset fields inherit the value from the enclosing level.
+type JsonOptions struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Emit fields holding zero values instead of omitting them
+	EmitUnpopulated *bool `protobuf:"varint,2,opt,name=emit_unpopulated,json=emitUnpopulated,proto3,oneof" json:"emit_unpopulated,omitempty"`
+	// Emit enum values as numbers instead of their names
+	UseEnumNumbers *bool `protobuf:"varint,3,opt,name=use_enum_numbers,json=useEnumNumbers,proto3,oneof" json:"use_enum_numbers,omitempty"`
+	// Ignore unknown fields in requests instead of failing
+	DiscardUnknown *bool `protobuf:"varint,4,opt,name=discard_unknown,json=discardUnknown,proto3,oneof" json:"discard_unknown,omitempty"`
+	// Accept messages with missing required fields
+	AllowPartial  *bool `protobuf:"varint,5,opt,name=allow_partial,json=allowPartial,proto3,oneof" json:"allow_partial,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *JsonOptions) Reset() {
+	*x = JsonOptions{}
+	mi := &file_wasmjs_v1_annotations_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *JsonOptions) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*JsonOptions) ProtoMessage() {}
+
+func (x *JsonOptions) ProtoReflect() protoreflect.Message {
+	mi := &file_wasmjs_v1_annotations_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use JsonOptions.ProtoReflect.Descriptor instead.
+func (*JsonOptions) Descriptor() ([]byte, []int) {
+	return file_wasmjs_v1_annotations_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *JsonOptions) GetEmitUnpopulated() bool {
+	if x != nil && x.EmitUnpopulated != nil {
+		return *x.EmitUnpopulated
+	}
+	return false
+}
+
+func (x *JsonOptions) GetUseEnumNumbers() bool {
+	if x != nil && x.UseEnumNumbers != nil {
+		return *x.UseEnumNumbers
+	}
+	return false
+}
+
+func (x *JsonOptions) GetDiscardUnknown() bool {
+	if x != nil && x.DiscardUnknown != nil {
+		return *x.DiscardUnknown
+	}
+	return false
+}
+
+func (x *JsonOptions) GetAllowPartial() bool {
+	if x != nil && x.AllowPartial != nil {
+		return *x.AllowPartial
+	}
+	return false
+}
+
+// Retry policy for calls to browser-provided methods, with exponential backoff between attempts
+type RetryPolicy struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Total number of attempts including the first (below 2 disables retries)
+	MaxAttempts uint32 `protobuf:"varint,1,opt,name=max_attempts,json=maxAttempts,proto3" json:"max_attempts,omitempty"`
+	// Delay before the first retry in milliseconds (default 100)
+	InitialBackoffMs uint32 `protobuf:"varint,2,opt,name=initial_backoff_ms,json=initialBackoffMs,proto3" json:"initial_backoff_ms,omitempty"`
+	// Maximum delay between attempts in milliseconds (default 5000)
+	MaxBackoffMs uint32 `protobuf:"varint,3,opt,name=max_backoff_ms,json=maxBackoffMs,proto3" json:"max_backoff_ms,omitempty"`
+	// Factor the delay grows by after every retry (default 2)
+	BackoffMultiplier float64 `protobuf:"fixed64,4,opt,name=backoff_multiplier,json=backoffMultiplier,proto3" json:"backoff_multiplier,omitempty"`
+	// gRPC status code names worth retrying, e.g. "UNAVAILABLE" (default UNKNOWN,
+	// DEADLINE_EXCEEDED, RESOURCE_EXHAUSTED and UNAVAILABLE). Errors thrown by
+	// JavaScript are UNKNOWN and browser call timeouts are DEADLINE_EXCEEDED.
+	RetryableCodes []string `protobuf:"bytes,5,rep,name=retryable_codes,json=retryableCodes,proto3" json:"retryable_codes,omitempty"`
+	// Time each attempt waits for JavaScript to answer in milliseconds (default 30000,
+	// or 60000 for async methods). Attempts that run out fail with DEADLINE_EXCEEDED.
+	AttemptTimeoutMs uint32 `protobuf:"varint,6,opt,name=attempt_timeout_ms,json=attemptTimeoutMs,proto3" json:"attempt_timeout_ms,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *RetryPolicy) Reset() {
+	*x = RetryPolicy{}
+	mi := &file_wasmjs_v1_annotations_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RetryPolicy) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RetryPolicy) ProtoMessage() {}
+
+func (x *RetryPolicy) ProtoReflect() protoreflect.Message {
+	mi := &file_wasmjs_v1_annotations_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RetryPolicy.ProtoReflect.Descriptor instead.
+func (*RetryPolicy) Descriptor() ([]byte, []int) {
+	return file_wasmjs_v1_annotations_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *RetryPolicy) GetMaxAttempts() uint32 {
+	if x != nil {
+		return x.MaxAttempts
+	}
+	return 0
+}
+
+func (x *RetryPolicy) GetInitialBackoffMs() uint32 {
+	if x != nil {
+		return x.InitialBackoffMs
+	}
+	return 0
+}
+
+func (x *RetryPolicy) GetMaxBackoffMs() uint32 {
+	if x != nil {
+		return x.MaxBackoffMs
+	}
+	return 0
+}
+
+func (x *RetryPolicy) GetBackoffMultiplier() float64 {
+	if x != nil {
+		return x.BackoffMultiplier
+	}
+	return 0
+}
+
+func (x *RetryPolicy) GetRetryableCodes() []string {
+	if x != nil {
+		return x.RetryableCodes
+	}
+	return nil
+}
+
+func (x *RetryPolicy) GetAttemptTimeoutMs() uint32 {
+	if x != nil {
+		return x.AttemptTimeoutMs
+	}
+	return 0
+}
+
 var file_wasmjs_v1_annotations_proto_extTypes = []protoimpl.ExtensionInfo{
 	{
 		ExtendedType:  (*descriptorpb.MethodOptions)(nil),
@@ -328,6 +551,54 @@ var file_wasmjs_v1_annotations_proto_extTypes = []protoimpl.ExtensionInfo{
 		Tag:           "varint,50009,opt,name=ts_factory",
 		Filename:      "wasmjs/v1/annotations.proto",
 	},
+	{
+		ExtendedType:  (*descriptorpb.MethodOptions)(nil),
+		ExtensionType: (*TimeoutOptions)(nil),
+		Field:         50010,
+		Name:          "wasmjs.v1.method_timeout",
+		Tag:           "bytes,50010,opt,name=method_timeout",
+		Filename:      "wasmjs/v1/annotations.proto",
+	},
+	{
+		ExtendedType:  (*descriptorpb.ServiceOptions)(nil),
+		ExtensionType: (*TimeoutOptions)(nil),
+		Field:         50011,
+		Name:          "wasmjs.v1.service_timeout",
+		Tag:           "bytes,50011,opt,name=service_timeout",
+		Filename:      "wasmjs/v1/annotations.proto",
+	},
+	{
+		ExtendedType:  (*descriptorpb.MethodOptions)(nil),
+		ExtensionType: (*JsonOptions)(nil),
+		Field:         50012,
+		Name:          "wasmjs.v1.method_json",
+		Tag:           "bytes,50012,opt,name=method_json",
+		Filename:      "wasmjs/v1/annotations.proto",
+	},
+	{
+		ExtendedType:  (*descriptorpb.ServiceOptions)(nil),
+		ExtensionType: (*JsonOptions)(nil),
+		Field:         50013,
+		Name:          "wasmjs.v1.service_json",
+		Tag:           "bytes,50013,opt,name=service_json",
+		Filename:      "wasmjs/v1/annotations.proto",
+	},
+	{
+		ExtendedType:  (*descriptorpb.MethodOptions)(nil),
+		ExtensionType: (*RetryPolicy)(nil),
+		Field:         50014,
+		Name:          "wasmjs.v1.method_retry",
+		Tag:           "bytes,50014,opt,name=method_retry",
+		Filename:      "wasmjs/v1/annotations.proto",
+	},
+	{
+		ExtendedType:  (*descriptorpb.ServiceOptions)(nil),
+		ExtensionType: (*RetryPolicy)(nil),
+		Field:         50015,
+		Name:          "wasmjs.v1.service_retry",
+		Tag:           "bytes,50015,opt,name=service_retry",
+		Filename:      "wasmjs/v1/annotations.proto",
+	},
 }
 
 // Extension fields to descriptorpb.MethodOptions.
@@ -340,6 +611,12 @@ var (
 	E_StatefulMethod = &file_wasmjs_v1_annotations_proto_extTypes[5]
 	// optional wasmjs.v1.AsyncMethodOptions async_method = 50007;
 	E_AsyncMethod = &file_wasmjs_v1_annotations_proto_extTypes[6]
+	// optional wasmjs.v1.TimeoutOptions method_timeout = 50010;
+	E_MethodTimeout = &file_wasmjs_v1_annotations_proto_extTypes[9]
+	// optional wasmjs.v1.JsonOptions method_json = 50012;
+	E_MethodJson = &file_wasmjs_v1_annotations_proto_extTypes[11]
+	// optional wasmjs.v1.RetryPolicy method_retry = 50014;
+	E_MethodRetry = &file_wasmjs_v1_annotations_proto_extTypes[13]
 )
 
 // Extension fields to descriptorpb.ServiceOptions.
@@ -352,6 +629,12 @@ var (
 	E_Stateful = &file_wasmjs_v1_annotations_proto_extTypes[4]
 	// optional bool browser_provided = 50008;
 	E_BrowserProvided = &file_wasmjs_v1_annotations_proto_extTypes[7]
+	// optional wasmjs.v1.TimeoutOptions service_timeout = 50011;
+	E_ServiceTimeout = &file_wasmjs_v1_annotations_proto_extTypes[10]
+	// optional wasmjs.v1.JsonOptions service_json = 50013;
+	E_ServiceJson = &file_wasmjs_v1_annotations_proto_extTypes[12]
+	// optional wasmjs.v1.RetryPolicy service_retry = 50015;
+	E_ServiceRetry = &file_wasmjs_v1_annotations_proto_extTypes[14]
 )
 
 // Extension fields to descriptorpb.FileOptions.
@@ -375,7 +658,28 @@ const file_wasmjs_v1_annotations_proto_rawDesc = "" +
 	"broadcasts\x18\x02 \x01(\bR\n" +
 	"broadcasts\"/\n" +
 	"\x12AsyncMethodOptions\x12\x19\n" +
-	"\bis_async\x18\x01 \x01(\bR\aisAsync*X\n" +
+	"\bis_async\x18\x01 \x01(\bR\aisAsync\"N\n" +
+	"\x0eTimeoutOptions\x12\x1d\n" +
+	"\n" +
+	"timeout_ms\x18\x01 \x01(\rR\ttimeoutMs\x12\x1d\n" +
+	"\n" +
+	"no_timeout\x18\x02 \x01(\bR\tnoTimeout\"\x9a\x02\n" +
+	"\vJsonOptions\x12.\n" +
+	"\x10emit_unpopulated\x18\x02 \x01(\bH\x00R\x0femitUnpopulated\x88\x01\x01\x12-\n" +
+	"\x10use_enum_numbers\x18\x03 \x01(\bH\x01R\x0euseEnumNumbers\x88\x01\x01\x12,\n" +
+	"\x0fdiscard_unknown\x18\x04 \x01(\bH\x02R\x0ediscardUnknown\x88\x01\x01\x12(\n" +
+	"\rallow_partial\x18\x05 \x01(\bH\x03R\fallowPartial\x88\x01\x01B\x13\n" +
+	"\x11_emit_unpopulatedB\x13\n" +
+	"\x11_use_enum_numbersB\x12\n" +
+	"\x10_discard_unknownB\x10\n" +
+	"\x0e_allow_partialJ\x04\b\x01\x10\x02\"\x8a\x02\n" +
+	"\vRetryPolicy\x12!\n" +
+	"\fmax_attempts\x18\x01 \x01(\rR\vmaxAttempts\x12,\n" +
+	"\x12initial_backoff_ms\x18\x02 \x01(\rR\x10initialBackoffMs\x12$\n" +
+	"\x0emax_backoff_ms\x18\x03 \x01(\rR\fmaxBackoffMs\x12-\n" +
+	"\x12backoff_multiplier\x18\x04 \x01(\x01R\x11backoffMultiplier\x12'\n" +
+	"\x0fretryable_codes\x18\x05 \x03(\tR\x0eretryableCodes\x12,\n" +
+	"\x12attempt_timeout_ms\x18\x06 \x01(\rR\x10attemptTimeoutMs*X\n" +
 	"\x12ConflictResolution\x12\x17\n" +
 	"\x13CHANGE_NUMBER_BASED\x10\x00\x12\x13\n" +
 	"\x0fTIMESTAMP_BASED\x10\x01\x12\x14\n" +
@@ -389,7 +693,14 @@ const file_wasmjs_v1_annotations_proto_rawDesc = "" +
 	"\fasync_method\x12\x1e.google.protobuf.MethodOptions\x18׆\x03 \x01(\v2\x1d.wasmjs.v1.AsyncMethodOptionsR\vasyncMethod:L\n" +
 	"\x10browser_provided\x12\x1f.google.protobuf.ServiceOptions\x18؆\x03 \x01(\bR\x0fbrowserProvided:=\n" +
 	"\n" +
-	"ts_factory\x12\x1c.google.protobuf.FileOptions\x18ن\x03 \x01(\bR\ttsFactoryB\xae\x01\n" +
+	"ts_factory\x12\x1c.google.protobuf.FileOptions\x18ن\x03 \x01(\bR\ttsFactory:b\n" +
+	"\x0emethod_timeout\x12\x1e.google.protobuf.MethodOptions\x18چ\x03 \x01(\v2\x19.wasmjs.v1.TimeoutOptionsR\rmethodTimeout:e\n" +
+	"\x0fservice_timeout\x12\x1f.google.protobuf.ServiceOptions\x18ۆ\x03 \x01(\v2\x19.wasmjs.v1.TimeoutOptionsR\x0eserviceTimeout:Y\n" +
+	"\vmethod_json\x12\x1e.google.protobuf.MethodOptions\x18܆\x03 \x01(\v2\x16.wasmjs.v1.JsonOptionsR\n" +
+	"methodJson:\\\n" +
+	"\fservice_json\x12\x1f.google.protobuf.ServiceOptions\x18݆\x03 \x01(\v2\x16.wasmjs.v1.JsonOptionsR\vserviceJson:[\n" +
+	"\fmethod_retry\x12\x1e.google.protobuf.MethodOptions\x18ކ\x03 \x01(\v2\x16.wasmjs.v1.RetryPolicyR\vmethodRetry:^\n" +
+	"\rservice_retry\x12\x1f.google.protobuf.ServiceOptions\x18߆\x03 \x01(\v2\x16.wasmjs.v1.RetryPolicyR\fserviceRetryB\xae\x01\n" +
 	"\rcom.wasmjs.v1B\x10AnnotationsProtoP\x01ZFgithub.com/panyam/protoc-gen-go-wasmjs/proto/gen/go/wasmjs/v1;wasmjsv1\xa2\x02\x03WXX\xaa\x02\tWasmjs.V1\xca\x02\tWasmjs\\V1\xe2\x02\x15Wasmjs\\V1\\GPBMetadata\xea\x02\n" +
 	"Wasmjs::V1b\x06proto3"
 
@@ -406,34 +717,49 @@ func file_wasmjs_v1_annotations_proto_rawDescGZIP() []byte {
 }
 
 var file_wasmjs_v1_annotations_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
-var file_wasmjs_v1_annotations_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
+var file_wasmjs_v1_annotations_proto_msgTypes = make([]protoimpl.MessageInfo, 6)
 var file_wasmjs_v1_annotations_proto_goTypes = []any{
 	(ConflictResolution)(0),             // 0: wasmjs.v1.ConflictResolution
 	(*StatefulOptions)(nil),             // 1: wasmjs.v1.StatefulOptions
 	(*StatefulMethodOptions)(nil),       // 2: wasmjs.v1.StatefulMethodOptions
 	(*AsyncMethodOptions)(nil),          // 3: wasmjs.v1.AsyncMethodOptions
-	(*descriptorpb.MethodOptions)(nil),  // 4: google.protobuf.MethodOptions
-	(*descriptorpb.ServiceOptions)(nil), // 5: google.protobuf.ServiceOptions
-	(*descriptorpb.FileOptions)(nil),    // 6: google.protobuf.FileOptions
+	(*TimeoutOptions)(nil),              // 4: wasmjs.v1.TimeoutOptions
+	(*JsonOptions)(nil),                 // 5: wasmjs.v1.JsonOptions
+	(*RetryPolicy)(nil),                 // 6: wasmjs.v1.RetryPolicy
+	(*descriptorpb.MethodOptions)(nil),  // 7: google.protobuf.MethodOptions
+	(*descriptorpb.ServiceOptions)(nil), // 8: google.protobuf.ServiceOptions
+	(*descriptorpb.FileOptions)(nil),    // 9: google.protobuf.FileOptions
 }
 var file_wasmjs_v1_annotations_proto_depIdxs = []int32{
 	0,  // 0: wasmjs.v1.StatefulOptions.conflict_resolution:type_name -> wasmjs.v1.ConflictResolution
-	4,  // 1: wasmjs.v1.wasm_method_name:extendee -> google.protobuf.MethodOptions
-	5,  // 2: wasmjs.v1.wasm_service_exclude:extendee -> google.protobuf.ServiceOptions
-	4,  // 3: wasmjs.v1.wasm_method_exclude:extendee -> google.protobuf.MethodOptions
-	5,  // 4: wasmjs.v1.wasm_service_name:extendee -> google.protobuf.ServiceOptions
-	5,  // 5: wasmjs.v1.stateful:extendee -> google.protobuf.ServiceOptions
-	4,  // 6: wasmjs.v1.stateful_method:extendee -> google.protobuf.MethodOptions
-	4,  // 7: wasmjs.v1.async_method:extendee -> google.protobuf.MethodOptions
-	5,  // 8: wasmjs.v1.browser_provided:extendee -> google.protobuf.ServiceOptions
-	6,  // 9: wasmjs.v1.ts_factory:extendee -> google.protobuf.FileOptions
-	1,  // 10: wasmjs.v1.stateful:type_name -> wasmjs.v1.StatefulOptions
-	2,  // 11: wasmjs.v1.stateful_method:type_name -> wasmjs.v1.StatefulMethodOptions
-	3,  // 12: wasmjs.v1.async_method:type_name -> wasmjs.v1.AsyncMethodOptions
-	13, // [13:13] is the sub-list for method output_type
-	13, // [13:13] is the sub-list for method input_type
-	10, // [10:13] is the sub-list for extension type_name
-	1,  // [1:10] is the sub-list for extension extendee
+	7,  // 1: wasmjs.v1.wasm_method_name:extendee -> google.protobuf.MethodOptions
+	8,  // 2: wasmjs.v1.wasm_service_exclude:extendee -> google.protobuf.ServiceOptions
+	7,  // 3: wasmjs.v1.wasm_method_exclude:extendee -> google.protobuf.MethodOptions
+	8,  // 4: wasmjs.v1.wasm_service_name:extendee -> google.protobuf.ServiceOptions
+	8,  // 5: wasmjs.v1.stateful:extendee -> google.protobuf.ServiceOptions
+	7,  // 6: wasmjs.v1.stateful_method:extendee -> google.protobuf.MethodOptions
+	7,  // 7: wasmjs.v1.async_method:extendee -> google.protobuf.MethodOptions
+	8,  // 8: wasmjs.v1.browser_provided:extendee -> google.protobuf.ServiceOptions
+	9,  // 9: wasmjs.v1.ts_factory:extendee -> google.protobuf.FileOptions
+	7,  // 10: wasmjs.v1.method_timeout:extendee -> google.protobuf.MethodOptions
+	8,  // 11: wasmjs.v1.service_timeout:extendee -> google.protobuf.ServiceOptions
+	7,  // 12: wasmjs.v1.method_json:extendee -> google.protobuf.MethodOptions
+	8,  // 13: wasmjs.v1.service_json:extendee -> google.protobuf.ServiceOptions
+	7,  // 14: wasmjs.v1.method_retry:extendee -> google.protobuf.MethodOptions
+	8,  // 15: wasmjs.v1.service_retry:extendee -> google.protobuf.ServiceOptions
+	1,  // 16: wasmjs.v1.stateful:type_name -> wasmjs.v1.StatefulOptions
+	2,  // 17: wasmjs.v1.stateful_method:type_name -> wasmjs.v1.StatefulMethodOptions
+	3,  // 18: wasmjs.v1.async_method:type_name -> wasmjs.v1.AsyncMethodOptions
+	4,  // 19: wasmjs.v1.method_timeout:type_name -> wasmjs.v1.TimeoutOptions
+	4,  // 20: wasmjs.v1.service_timeout:type_name -> wasmjs.v1.TimeoutOptions
+	5,  // 21: wasmjs.v1.method_json:type_name -> wasmjs.v1.JsonOptions
+	5,  // 22: wasmjs.v1.service_json:type_name -> wasmjs.v1.JsonOptions
+	6,  // 23: wasmjs.v1.method_retry:type_name -> wasmjs.v1.RetryPolicy
+	6,  // 24: wasmjs.v1.service_retry:type_name -> wasmjs.v1.RetryPolicy
+	25, // [25:25] is the sub-list for method output_type
+	25, // [25:25] is the sub-list for method input_type
+	16, // [16:25] is the sub-list for extension type_name
+	1,  // [1:16] is the sub-list for extension extendee
 	0,  // [0:1] is the sub-list for field type_name
 }
 
@@ -442,14 +768,15 @@ func file_wasmjs_v1_annotations_proto_init() {
 	if File_wasmjs_v1_annotations_proto != nil {
 		return
 	}
+	file_wasmjs_v1_annotations_proto_msgTypes[4].OneofWrappers = []any{}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_wasmjs_v1_annotations_proto_rawDesc), len(file_wasmjs_v1_annotations_proto_rawDesc)),
 			NumEnums:      1,
-			NumMessages:   3,
-			NumExtensions: 9,
+			NumMessages:   6,
+			NumExtensions: 15,
 			NumServices:   0,
 		},
 		GoTypes:           file_wasmjs_v1_annotations_proto_goTypes,
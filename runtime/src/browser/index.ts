@@ -12,4 +12,4 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
-export { BrowserServiceManager } from './service-manager.js';
+export { BrowserServiceManager, type BrowserCallContext, type BrowserStreamCallContext } from './service-manager.js';
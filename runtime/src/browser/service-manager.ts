@@ -12,6 +12,26 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
+import { BinaryCodec } from '../schema/binary-codec.js';
+
+/**
+ * Context passed to browser service implementations for each call from WASM
+ */
+export interface BrowserCallContext {
+    /** Aborted when the Go caller's context is cancelled or the call times out */
+    signal: AbortSignal;
+}
+
+/**
+ * Context passed to implementations of server-streaming browser methods.
+ * Implementations either return an async iterable of messages, or call emit
+ * for every message and return a promise that resolves when the stream ends.
+ */
+export interface BrowserStreamCallContext<T = any> extends BrowserCallContext {
+    /** Send a message to the Go caller; ignored once the stream is cancelled */
+    emit(message: T): void;
+}
+
 /**
  * Browser Service Manager
  * Handles FIFO processing of browser service calls from WASM
@@ -19,14 +39,29 @@
  */
 export class BrowserServiceManager {
     private processing = false;
+    private drainScheduled = false;
+    private activeCalls = new Map<string, AbortController>();
     private serviceImplementations = new Map<string, any>();
     private wasmModule: any;
+    private codec: BinaryCodec | null = null;
 
-    constructor() {
+    /**
+     * @param channelName Name of the WASM browser channel to bind to, normally the module's
+     * JS namespace. The channel's globals are suffixed with `_${channelName}` so several
+     * WASM modules can share a page; without a name the unsuffixed globals are used.
+     */
+    constructor(private readonly channelName?: string) {
         // WASM will set up the global functions __wasmGetNextBrowserCall and __wasmDeliverBrowserResponse
         // We'll just use them when they're available
     }
 
+    /**
+     * Name of a global of this manager's browser channel
+     */
+    private globalName(base: string): string {
+        return this.channelName ? `${base}_${this.channelName}` : base;
+    }
+
     /**
      * Register a browser service implementation
      */
@@ -42,22 +77,54 @@ export class BrowserServiceManager {
     }
 
     /**
-     * Start processing browser service calls
+     * Set the codec of calls WASM makes with protobuf bytes (browser clients generated with
+     * target=tinygo), which name their request and response types
      */
-    async startProcessing(): Promise<void> {
+    setCodec(codec: BinaryCodec | null): void {
+        this.codec = codec;
+    }
+
+    /**
+     * Start processing browser service calls.
+     * Registers the dispatcher WASM invokes whenever a call is queued, so no polling is needed.
+     */
+    startProcessing(): void {
         if (this.processing) return;
         this.processing = true;
 
-        // Process calls in a loop
+        (globalThis as any)[this.globalName('__wasmBrowserCallDispatcher')] = () => this.scheduleDrain();
+        (globalThis as any)[this.globalName('__wasmBrowserCallCancelled')] = (callId: string, reason: string) => this.cancelCall(callId, reason);
+
+        // Pick up calls queued before the dispatcher was registered
+        this.scheduleDrain();
+    }
+
+    /**
+     * Schedule draining of the WASM call queue.
+     * The dispatcher is invoked synchronously from Go, so calls are fetched in a
+     * microtask instead of re-entering WASM from inside the notification.
+     */
+    private scheduleDrain(): void {
+        if (this.drainScheduled) return;
+        this.drainScheduled = true;
+
+        queueMicrotask(() => {
+            this.drainScheduled = false;
+            this.drainCalls();
+        });
+    }
+
+    /**
+     * Process every call currently queued by WASM
+     */
+    private drainCalls(): void {
         while (this.processing) {
             const call = this.getNextBrowserCall();
             if (!call) {
-                // No pending calls, wait a bit
-                await new Promise(resolve => setTimeout(resolve, 10));
-                continue;
+                return;
             }
 
-            // Process each call asynchronously without blocking the loop
+            // Process each call asynchronously without blocking the queue
             this.processCall(call);
         }
     }
@@ -66,35 +133,149 @@ export class BrowserServiceManager {
      * Process a single browser service call asynchronously
      */
     private async processCall(call: any): Promise<void> {
+        const controller = new AbortController();
+        this.activeCalls.set(call.id, controller);
+
         try {
-            // Get the service implementation
-            const service = this.serviceImplementations.get(call.service);
-            if (!service) {
-                throw new Error(`No implementation registered for service: ${call.service}`);
-            }
+            // Parse request
+            const request = this.decodeRequest(call);
 
-            // Get the method
-            const methodName = call.method.charAt(0).toLowerCase() + call.method.slice(1);
-            const method = service[methodName];
-            if (!method) {
-                throw new Error(`Method ${methodName} not found on service ${call.service}`);
+            if (call.streaming) {
+                await this.processStreamingCall(call, request, controller);
+                return;
             }
 
-            // Parse request
-            const request = JSON.parse(call.request);
-
             // Call the method (auto-await if async)
-            const response = await Promise.resolve(method.call(service, request));
+            const context: BrowserCallContext = { signal: controller.signal };
+            const response = await this.invoke(call.service, call.method, request, context);
+            if (controller.signal.aborted) {
+                console.warn(`Discarding response of cancelled browser call ${call.service}.${call.method} (${call.id})`);
+                return;
+            }
             
             console.log(`DEBUG: Browser service response for ${call.service}.${call.method}:`, response);
-            const jsonResponse = JSON.stringify(response);
-            console.log(`DEBUG: JSON stringified response:`, jsonResponse);
+            const encodedResponse = this.encodeResponse(call, response);
 
             // Deliver response
-            this.deliverBrowserResponse(call.id, jsonResponse, null);
+            this.deliverResult(call, encodedResponse, null);
         } catch (error: any) {
+            if (controller.signal.aborted) {
+                // Implementations reject with the abort reason once the call is cancelled
+                return;
+            }
             // Deliver error
-            this.deliverBrowserResponse(call.id, null, error.message || String(error));
+            this.deliverResult(call, null, error.message || String(error));
+        } finally {
+            this.activeCalls.delete(call.id);
+        }
+    }
+
+    /**
+     * Run a server-streaming browser method, forwarding every message it yields or emits
+     * to WASM, then end the stream once the method completes
+     */
+    private async processStreamingCall(call: any, request: any, controller: AbortController): Promise<void> {
+        const context: BrowserStreamCallContext = {
+            signal: controller.signal,
+            emit: (message: any) => {
+                if (controller.signal.aborted) return;
+                if (!this.deliverBrowserStreamMessage(call.id, this.encodeResponse(call, message))) {
+                    // WASM stopped listening; let the implementation release its resources
+                    controller.abort('stream closed by WASM');
+                }
+            }
+        };
+
+        await this.invoke(call.service, call.method, request, context);
+        if (controller.signal.aborted) {
+            return;
+        }
+
+        // End the stream
+        this.deliverResult(call, null, null);
+    }
+
+    /**
+     * Decode a call's request: protobuf bytes of its requestType, or a JSON string
+     */
+    private decodeRequest(call: any): any {
+        if (!call.requestType) {
+            return JSON.parse(call.request);
+        }
+        if (!this.codec) {
+            throw new Error(`Cannot decode ${call.requestType}: browser calls with protobuf bytes need the bundle's schemas`);
+        }
+        return this.codec.decode(call.requestType, call.request);
+    }
+
+    /**
+     * Encode a response or stream message the way the call's request was encoded
+     */
+    private encodeResponse(call: any, response: any): string | Uint8Array {
+        if (!call.responseType) {
+            return JSON.stringify(response);
+        }
+        if (!this.codec) {
+            throw new Error(`Cannot encode ${call.responseType}: browser calls with protobuf bytes need the bundle's schemas`);
+        }
+        return this.codec.encode(call.responseType, response ?? {});
+    }
+
+    /**
+     * Call a method of a registered implementation and return its response.
+     * With a streaming context (one with emit), messages of a returned async iterable
+     * are emitted until it is exhausted or the call is aborted.
+     * Also runs calls forwarded from a WASM module hosted in a Web Worker.
+     */
+    async invoke(serviceName: string, methodName: string, request: any, context: BrowserCallContext | BrowserStreamCallContext): Promise<any> {
+        // Get the service implementation
+        const service = this.serviceImplementations.get(serviceName);
+        if (!service) {
+            throw new Error(`No implementation registered for service: ${serviceName}`);
+        }
+
+        // Get the method
+        const name = methodName.charAt(0).toLowerCase() + methodName.slice(1);
+        const method = service[name];
+        if (!method) {
+            throw new Error(`Method ${name} not found on service ${serviceName}`);
+        }
+
+        // An async generator returns its iterable synchronously; other methods may return a promise
+        const result = await Promise.resolve(method.call(service, request, context));
+        if ('emit' in context && result && typeof result[Symbol.asyncIterator] === 'function') {
+            for await (const message of result as AsyncIterable<any>) {
+                if (context.signal.aborted) break;
+                context.emit(message);
+            }
+            return undefined;
+        }
+        return result;
+    }
+
+    /**
+     * Names of the registered service implementations
+     */
+    get serviceNames(): string[] {
+        return Array.from(this.serviceImplementations.keys());
+    }
+
+    /**
+     * Deliver a call's result, reporting results WASM no longer waits for
+     */
+    private deliverResult(call: any, response: string | Uint8Array | null, error: string | null): void {
+        if (!this.deliverBrowserResponse(call.id, response, error)) {
+            console.warn(`Late delivery for browser call ${call.service}.${call.method} (${call.id}): the call was cancelled or timed out`);
+        }
+    }
+
+    /**
+     * Abort a call that WASM cancelled or timed out
+     */
+    private cancelCall(callId: string, reason: string): void {
+        const controller = this.activeCalls.get(callId);
+        if (controller) {
+            controller.abort(reason);
         }
     }
 
@@ -103,6 +284,8 @@ export class BrowserServiceManager {
      */
     stopProcessing(): void {
         this.processing = false;
+        delete (globalThis as any)[this.globalName('__wasmBrowserCallDispatcher')];
+        delete (globalThis as any)[this.globalName('__wasmBrowserCallCancelled')];
     }
 
     /**
@@ -110,19 +293,32 @@ export class BrowserServiceManager {
      */
     private getNextBrowserCall(): any {
         // The __wasmGetNextBrowserCall function should be provided by WASM
-        if (typeof (window as any).__wasmGetNextBrowserCall === 'function') {
-            return (window as any).__wasmGetNextBrowserCall();
+        const getNextBrowserCall = (globalThis as any)[this.globalName('__wasmGetNextBrowserCall')];
+        if (typeof getNextBrowserCall === 'function') {
+            return getNextBrowserCall();
         }
         return null;
     }
 
+    /**
+     * Deliver a message of a server-streaming call to WASM (called internally)
+     */
+    private deliverBrowserStreamMessage(callId: string, message: string | Uint8Array): boolean {
+        const deliverBrowserStreamMessage = (globalThis as any)[this.globalName('__wasmDeliverBrowserStreamMessage')];
+        if (!deliverBrowserStreamMessage) {
+            return false;
+        }
+        return deliverBrowserStreamMessage(callId, message);
+    }
+
     /**
      * Deliver a response back to WASM (called internally)
      */
-    private deliverBrowserResponse(callId: string, response: string | null, error: string | null): boolean {
-        if (!(window as any).__wasmDeliverBrowserResponse) {
+    private deliverBrowserResponse(callId: string, response: string | Uint8Array | null, error: string | null): boolean {
+        const deliverBrowserResponse = (globalThis as any)[this.globalName('__wasmDeliverBrowserResponse')];
+        if (!deliverBrowserResponse) {
             return false;
         }
-        return (window as any).__wasmDeliverBrowserResponse(callId, response, error);
+        return deliverBrowserResponse(callId, response, error);
     }
 }
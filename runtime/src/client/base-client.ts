@@ -13,7 +13,7 @@
 // limitations under the License.
 
 import { BrowserServiceManager } from '../browser/service-manager.js';
-import { WasmError } from './types.js';
+import { WasmError, StatusError, WasmStatus, errorFromResponse } from './types.js';
 
 /**
  * Base WASM service client containing all non-template-dependent logic
@@ -71,7 +71,7 @@ export abstract class WASMServiceClient {
             const wasmResponse = wasmMethod(JSON.stringify(jsonReq));
 
             if (!wasmResponse.success) {
-                throw new WasmError(wasmResponse.message, methodPath);
+                throw errorFromResponse(wasmResponse, methodPath);
             }
 
             // Return response data directly
@@ -93,7 +93,7 @@ export abstract class WASMServiceClient {
     public callMethodWithCallback<TRequest>(
         methodPath: string,
         request: TRequest,
-        callback: (response: any, error?: string) => void
+        callback: (response: any, error?: string, status?: StatusError) => void
     ): Promise<void> {
         this.ensureWASMLoaded();
 
@@ -104,10 +104,13 @@ export abstract class WASMServiceClient {
             
             // Call WASM method with callback function
             // WASM now passes proper JS objects, not JSON strings
-            const wasmResponse = wasmMethod(JSON.stringify(jsonReq), callback);
+            const wrappedCallback = (response: any, error?: string, status?: WasmStatus): void => {
+                callback(response, error, status ? StatusError.fromStatus(status, methodPath) : undefined);
+            };
+            const wasmResponse = wasmMethod(JSON.stringify(jsonReq), wrappedCallback);
 
             if (!wasmResponse.success) {
-                throw new WasmError(wasmResponse.message, methodPath);
+                throw errorFromResponse(wasmResponse, methodPath);
             }
 
             // Async methods return immediately
@@ -129,7 +132,7 @@ export abstract class WASMServiceClient {
     public callStreamingMethod<TRequest, TResponse>(
         methodPath: string,
         request: TRequest,
-        callback: (response: TResponse | null, error: string | null, done: boolean) => boolean
+        callback: (response: TResponse | null, error: string | null, done: boolean, status?: StatusError) => boolean
     ): void {
         this.ensureWASMLoaded();
 
@@ -139,7 +142,7 @@ export abstract class WASMServiceClient {
             const wasmMethod = this.getWasmMethod(methodPath);
 
             // Wrap the callback to parse JSON responses
-            const wrappedCallback = (responseStr: string | null, error: string | null, done: boolean): boolean => {
+            const wrappedCallback = (responseStr: string | null, error: string | null, done: boolean, status?: WasmStatus): boolean => {
                 let response: TResponse | null = null;
                 if (responseStr && !error) {
                     try {
@@ -149,14 +152,14 @@ export abstract class WASMServiceClient {
                         response = responseStr as any;
                     }
                 }
-                return callback(response, error, done);
+                return callback(response, error, done, status ? StatusError.fromStatus(status, methodPath) : undefined);
             };
 
             // Call WASM streaming method with wrapped callback
             const wasmResponse = wasmMethod(JSON.stringify(jsonReq), wrappedCallback);
 
             if (!wasmResponse.success) {
-                throw new WasmError(wasmResponse.message, methodPath);
+                throw errorFromResponse(wasmResponse, methodPath);
             }
 
             // Streaming methods return immediately
@@ -0,0 +1,132 @@
+// Copyright 2025 Sri Panyam
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import { StreamCall } from './stream-call.js';
+import { Transport, MethodInfo } from './transport.js';
+import { CallOptions, StatusError } from './types.js';
+
+/**
+ * Calls the primary transport (normally a WASMBundle) once it is ready, and the fallback
+ * (normally an HttpTransport to the real backend) if it fails to become ready, such as
+ * when the WASM module fails to load on a low-memory device:
+ *
+ *     const bundle = new ExampleBundle();
+ *     const transport = new FallbackTransport(bundle, new HttpTransport({ baseUrl: 'https://api.example.com' }));
+ *     bundle.loadWasm('/example.wasm').catch(() => {}); // Failures switch calls to the server
+ *     const library = new LibraryServiceClient(transport);
+ *
+ * Calls made while the module loads wait for the outcome; start loading before the first
+ * call, as a module that is not loading counts as failed. Client and bidirectional
+ * streams, which start synchronously, go to the fallback until the module is ready.
+ */
+export class FallbackTransport implements Transport {
+    private settled: Promise<Transport> | null = null;
+    private usingFallback = false;
+
+    constructor(
+        private readonly primary: Transport,
+        private readonly fallback: Transport,
+        private readonly onFallback?: (error: unknown) => void // Told why calls switched to the fallback
+    ) {}
+
+    /**
+     * Whether calls go to the fallback because the primary transport failed
+     */
+    public get isUsingFallback(): boolean {
+        return this.usingFallback;
+    }
+
+    public isReady(): boolean {
+        return this.current().isReady();
+    }
+
+    public async waitUntilReady(): Promise<void> {
+        const transport = await this.settle();
+        return transport.waitUntilReady();
+    }
+
+    public async callMethod<TRequest, TResponse>(
+        methodPath: string,
+        request: TRequest,
+        options?: CallOptions,
+        method?: MethodInfo
+    ): Promise<TResponse> {
+        const transport = await this.settle();
+        return transport.callMethod(methodPath, request, options, method);
+    }
+
+    public async callMethodWithCallback<TRequest>(
+        methodPath: string,
+        request: TRequest,
+        callback: (response: any, error?: string, status?: StatusError) => void,
+        options?: CallOptions,
+        method?: MethodInfo
+    ): Promise<void> {
+        const transport = await this.settle();
+        return transport.callMethodWithCallback(methodPath, request, callback, options, method);
+    }
+
+    public callStreamingMethod<TRequest, TResponse>(
+        methodPath: string,
+        request: TRequest,
+        callback: (response: TResponse | null, error: string | null, done: boolean, status?: StatusError) => boolean,
+        options?: CallOptions,
+        method?: MethodInfo
+    ): void {
+        this.settle().then(transport => {
+            try {
+                transport.callStreamingMethod(methodPath, request, callback, options, method);
+            } catch (error) {
+                const status = error instanceof StatusError ? error : undefined;
+                callback(null, error instanceof Error ? error.message : String(error), true, status);
+            }
+        });
+    }
+
+    public openStream<TRequest, TResponse>(
+        methodPath: string,
+        options?: CallOptions,
+        method?: MethodInfo
+    ): StreamCall<TRequest, TResponse> {
+        return this.current().openStream(methodPath, options, method);
+    }
+
+    /**
+     * The transport to use without waiting: the primary once ready, otherwise the fallback
+     */
+    private current(): Transport {
+        return !this.usingFallback && this.primary.isReady() ? this.primary : this.fallback;
+    }
+
+    /**
+     * Wait for the primary transport to become ready, switching to the fallback for
+     * good if it fails
+     */
+    private settle(): Promise<Transport> {
+        if (this.primary.isReady() && !this.usingFallback) {
+            return Promise.resolve(this.primary);
+        }
+        if (!this.settled) {
+            this.settled = this.primary.waitUntilReady().then(
+                () => this.primary,
+                error => {
+                    this.usingFallback = true;
+                    this.onFallback?.(error);
+                    return this.fallback;
+                }
+            );
+        }
+        return this.settled;
+    }
+}
@@ -0,0 +1,462 @@
+// Copyright 2025 Sri Panyam
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import { StreamCall } from './stream-call.js';
+import { Transport, MethodInfo } from './transport.js';
+import { CallOptions, Metadata, StatusCode, StatusDetail, StatusError, WasmError } from './types.js';
+
+/**
+ * Configuration of an HttpTransport
+ */
+export interface HttpTransportConfig {
+    baseUrl: string; // Server URL; calls are posted to `${baseUrl}/${package}.${Service}/${Method}`
+    protocol?: 'connect' | 'grpc-web'; // Protocol the server speaks, with JSON messages (default: connect)
+    headers?: Record<string, string>; // Headers sent with every call (e.g., authorization)
+    credentials?: RequestCredentials; // Whether fetch sends cookies cross-origin
+    fetch?: typeof fetch; // fetch implementation (default: globalThis.fetch)
+}
+
+const textEncoder = new TextEncoder();
+const textDecoder = new TextDecoder();
+
+// Envelope flags of streamed messages
+const flagCompressed = 0x01;
+const flagConnectEndStream = 0x02;
+const flagGrpcWebTrailer = 0x80;
+
+/**
+ * Calls the server implementing the services over HTTP, with the Connect protocol or
+ * gRPC-Web and JSON messages, so generated clients can use the real backend instead of
+ * the WASM module (see FallbackTransport). connect-go servers speak both protocols;
+ * grpc-go servers need a gRPC-Web proxy such as Envoy.
+ *
+ * Messages are sent as the JSON of their TypeScript objects, which the server must
+ * accept as proto JSON (generated with the default json_use_proto_names=false).
+ * Over HTTP/1.1 fetch cannot stream requests, so client and bidirectional streams send
+ * their requests together when closeSend() is called, before any response arrives.
+ */
+export class HttpTransport implements Transport {
+    private readonly baseUrl: string;
+    private readonly protocol: 'connect' | 'grpc-web';
+
+    constructor(private readonly config: HttpTransportConfig) {
+        this.baseUrl = config.baseUrl.replace(/\/+$/, '');
+        this.protocol = config.protocol ?? 'connect';
+    }
+
+    /**
+     * Servers need no loading, so calls can be made at any time
+     */
+    public isReady(): boolean {
+        return true;
+    }
+
+    public async waitUntilReady(): Promise<void> {}
+
+    public async callMethod<TRequest, TResponse>(
+        methodPath: string,
+        request: TRequest,
+        options?: CallOptions,
+        method?: MethodInfo
+    ): Promise<TResponse> {
+        const responses: TResponse[] = [];
+        await this.exchange(methodPath, method, [request], false, options, response => {
+            responses.push(response);
+            return true;
+        });
+        if (responses.length !== 1) {
+            throw new StatusError(StatusCode.UNIMPLEMENTED, `Expected one response, got ${responses.length}`, [], methodPath);
+        }
+        return responses[0];
+    }
+
+    public async callMethodWithCallback<TRequest>(
+        methodPath: string,
+        request: TRequest,
+        callback: (response: any, error?: string, status?: StatusError) => void,
+        options?: CallOptions,
+        method?: MethodInfo
+    ): Promise<void> {
+        let response: any;
+        try {
+            response = await this.callMethod(methodPath, request, options, method);
+        } catch (error) {
+            const status = this.statusOf(error, methodPath);
+            callback(null, status.message, status);
+            return;
+        }
+        callback(response);
+    }
+
+    public callStreamingMethod<TRequest, TResponse>(
+        methodPath: string,
+        request: TRequest,
+        callback: (response: TResponse | null, error: string | null, done: boolean, status?: StatusError) => boolean,
+        options?: CallOptions,
+        method?: MethodInfo
+    ): void {
+        let stopped = false;
+        this.exchange(methodPath, method, [request], true, options, response => {
+            stopped = callback(response, null, false) === false;
+            return !stopped;
+        }).then(
+            () => {
+                if (!stopped) callback(null, null, true);
+            },
+            error => {
+                const status = this.statusOf(error, methodPath);
+                callback(null, status.message, true, status);
+            }
+        );
+    }
+
+    public openStream<TRequest, TResponse>(
+        methodPath: string,
+        options?: CallOptions,
+        method?: MethodInfo
+    ): StreamCall<TRequest, TResponse> {
+        const call = new StreamCall<TRequest, TResponse>(methodPath, request => request);
+        const requests: unknown[] = [];
+        const cancel = new AbortController();
+
+        const accepted = { success: true, message: 'Request queued', data: null };
+        call.attach({
+            send: (request: unknown) => {
+                requests.push(request);
+                return accepted;
+            },
+            closeSend: () => {
+                this.exchange(methodPath, method, requests, true, options, response => {
+                    call.push(response as TResponse);
+                    return !call.isFinished;
+                }, cancel.signal).then(
+                    () => call.finish(),
+                    error => call.finish(this.statusOf(error, methodPath))
+                );
+                return { success: true, message: 'Send closed', data: null };
+            },
+            cancel: () => {
+                cancel.abort();
+                call.finish(new StatusError(StatusCode.CANCELLED, 'Call cancelled', [], methodPath));
+            },
+        });
+        return call;
+    }
+
+    /**
+     * Post the requests of a call and pass every response message to onMessage, which
+     * returns false to stop reading. Rejects with a StatusError when the call fails.
+     */
+    private async exchange(
+        methodPath: string,
+        method: MethodInfo | undefined,
+        requests: unknown[],
+        streaming: boolean,
+        options: CallOptions | undefined,
+        onMessage: (message: any) => boolean,
+        stop?: AbortSignal
+    ): Promise<void> {
+        if (!method?.fullMethod) {
+            throw new WasmError('HttpTransport needs the full method name of the call; regenerate the client', methodPath);
+        }
+        if (options?.signal?.aborted) {
+            throw new StatusError(StatusCode.CANCELLED, 'Call cancelled', [], methodPath);
+        }
+
+        // Abort the request when the caller cancels, the timeout fires or reading stops
+        const controller = new AbortController();
+        const abort = () => controller.abort();
+        let deadlineExceeded = false;
+        let stopped = false;
+        options?.signal?.addEventListener('abort', abort, { once: true });
+        stop?.addEventListener('abort', abort, { once: true });
+        const timer = options?.timeoutMs
+            ? setTimeout(() => { deadlineExceeded = true; controller.abort(); }, options.timeoutMs)
+            : undefined;
+        const receive = (message: any): boolean => {
+            stopped = !onMessage(message);
+            if (stopped) controller.abort();
+            return !stopped;
+        };
+
+        try {
+            const unaryConnect = this.protocol === 'connect' && !streaming;
+            const fetchFn = this.config.fetch ?? globalThis.fetch;
+            let response: Response;
+            try {
+                response = await fetchFn(this.baseUrl + method.fullMethod, {
+                    method: 'POST',
+                    headers: this.requestHeaders(streaming, options),
+                    body: unaryConnect ? JSON.stringify(requests[0] ?? {}) : this.envelopes(requests),
+                    signal: controller.signal,
+                    credentials: this.config.credentials,
+                });
+            } catch (error) {
+                if (controller.signal.aborted) throw error;
+                throw new StatusError(StatusCode.UNAVAILABLE, `Failed to reach ${this.baseUrl}: ${error instanceof Error ? error.message : String(error)}`, [], methodPath);
+            }
+
+            if (unaryConnect) {
+                await this.readConnectUnary(response, methodPath, options, receive);
+            } else {
+                await this.readStream(response, methodPath, options, receive);
+            }
+        } catch (error) {
+            if (stopped) return;
+            if (controller.signal.aborted) {
+                throw deadlineExceeded
+                    ? new StatusError(StatusCode.DEADLINE_EXCEEDED, `Call timed out after ${options?.timeoutMs}ms`, [], methodPath)
+                    : new StatusError(StatusCode.CANCELLED, 'Call cancelled', [], methodPath);
+            }
+            throw error;
+        } finally {
+            clearTimeout(timer);
+            options?.signal?.removeEventListener('abort', abort);
+            stop?.removeEventListener('abort', abort);
+        }
+    }
+
+    /**
+     * Headers of a call: the protocol's content type and version, the timeout the server
+     * enforces, and the configured and per-call headers
+     */
+    private requestHeaders(streaming: boolean, options?: CallOptions): Headers {
+        const headers = new Headers(this.config.headers);
+        if (this.protocol === 'connect') {
+            headers.set('content-type', streaming ? 'application/connect+json' : 'application/json');
+            headers.set('connect-protocol-version', '1');
+            if (options?.timeoutMs) headers.set('connect-timeout-ms', String(options.timeoutMs));
+        } else {
+            headers.set('content-type', 'application/grpc-web+json');
+            headers.set('x-grpc-web', '1');
+            if (options?.timeoutMs) headers.set('grpc-timeout', `${options.timeoutMs}m`);
+        }
+        for (const [name, value] of Object.entries(options?.headers ?? {})) {
+            for (const item of Array.isArray(value) ? value : [value]) {
+                headers.append(name, item);
+            }
+        }
+        return headers;
+    }
+
+    /**
+     * Read a Connect unary response: the message as the body, or the error as JSON,
+     * with trailers sent as headers prefixed with "trailer-"
+     */
+    private async readConnectUnary(response: Response, methodPath: string, options: CallOptions | undefined, receive: (message: any) => boolean): Promise<void> {
+        const header: Metadata = {};
+        const trailer: Metadata = {};
+        response.headers.forEach((value, name) => {
+            if (name.startsWith('trailer-')) {
+                trailer[name.substring('trailer-'.length)] = [value];
+            } else {
+                header[name] = [value];
+            }
+        });
+        options?.onHeader?.(header);
+
+        const body = await response.text();
+        options?.onTrailer?.(trailer);
+        if (!response.ok) {
+            throw this.connectError(parseJSON(body), response.status, methodPath);
+        }
+        receive(JSON.parse(body));
+    }
+
+    /**
+     * Read an enveloped response (Connect streaming or gRPC-Web), passing its messages on
+     * and failing with the status of its end-of-stream message or trailers
+     */
+    private async readStream(response: Response, methodPath: string, options: CallOptions | undefined, receive: (message: any) => boolean): Promise<void> {
+        const header: Metadata = {};
+        response.headers.forEach((value, name) => { header[name] = [value]; });
+        options?.onHeader?.(header);
+
+        if (!response.ok && this.protocol === 'connect') {
+            throw this.connectError(parseJSON(await response.text()), response.status, methodPath);
+        }
+
+        let trailer: Metadata | null = null;
+        for await (const { flags, data } of readEnvelopes(response)) {
+            if (flags & flagCompressed) {
+                throw new StatusError(StatusCode.INTERNAL, 'Compressed messages are not supported', [], methodPath);
+            }
+            if (this.protocol === 'connect' && flags & flagConnectEndStream) {
+                const end = parseJSON(textDecoder.decode(data)) ?? {};
+                options?.onTrailer?.(end.metadata ?? {});
+                if (end.error) {
+                    throw this.connectError(end.error, 200, methodPath);
+                }
+                return;
+            }
+            if (this.protocol === 'grpc-web' && flags & flagGrpcWebTrailer) {
+                trailer = parseGrpcWebTrailer(textDecoder.decode(data));
+                continue;
+            }
+            if (!receive(JSON.parse(textDecoder.decode(data)))) {
+                return;
+            }
+        }
+
+        if (this.protocol === 'connect') {
+            throw new StatusError(StatusCode.INTERNAL, 'Stream ended without an end-of-stream message', [], methodPath);
+        }
+
+        // gRPC-Web sends the status in trailers, or in headers for responses without messages
+        const status = trailer ?? header;
+        options?.onTrailer?.(trailer ?? {});
+        const code = status['grpc-status']?.[0];
+        if (code === undefined) {
+            throw new StatusError(response.ok ? StatusCode.INTERNAL : httpStatusCode(response.status), `Missing grpc-status (HTTP ${response.status})`, [], methodPath);
+        }
+        if (Number(code) !== StatusCode.OK) {
+            throw new StatusError(Number(code), decodeGrpcMessage(status['grpc-message']?.[0] ?? ''), [], methodPath);
+        }
+    }
+
+    /**
+     * Create the StatusError of a Connect error ({ code, message, details }), falling back
+     * to the HTTP status when the body is not a Connect error
+     */
+    private connectError(error: any, httpStatus: number, methodPath: string): StatusError {
+        if (!error || typeof error.code !== 'string') {
+            return new StatusError(httpStatusCode(httpStatus), `HTTP ${httpStatus}`, [], methodPath);
+        }
+        const code = error.code === 'canceled' ? StatusCode.CANCELLED : StatusCode[error.code.toUpperCase() as keyof typeof StatusCode];
+        const details: StatusDetail[] = (error.details ?? []).map((detail: any) => ({
+            typeUrl: `type.googleapis.com/${detail.type}`,
+            value: base64Decode(detail.value ?? ''),
+            message: detail.debug,
+        }));
+        return new StatusError(code ?? StatusCode.UNKNOWN, error.message ?? '', details, methodPath);
+    }
+
+    /**
+     * The error passed to callbacks: a StatusError, or an UNKNOWN one wrapping other errors
+     */
+    private statusOf(error: unknown, methodPath: string): StatusError {
+        if (error instanceof StatusError) {
+            return error;
+        }
+        return new StatusError(StatusCode.UNKNOWN, error instanceof Error ? error.message : String(error), [], methodPath);
+    }
+
+    /**
+     * Enveloped request body: each message prefixed by a flags byte and its big-endian length
+     */
+    private envelopes(requests: unknown[]): Uint8Array {
+        const messages = requests.map(request => textEncoder.encode(JSON.stringify(request ?? {})));
+        const body = new Uint8Array(messages.reduce((size, message) => size + 5 + message.length, 0));
+        let offset = 0;
+        for (const message of messages) {
+            new DataView(body.buffer).setUint32(offset + 1, message.length);
+            body.set(message, offset + 5);
+            offset += 5 + message.length;
+        }
+        return body;
+    }
+}
+
+/**
+ * Read the envelopes of a response body as they arrive
+ */
+async function* readEnvelopes(response: Response): AsyncGenerator<{ flags: number; data: Uint8Array }> {
+    const reader = response.body?.getReader();
+    let buffer = reader ? new Uint8Array(0) : new Uint8Array(await response.arrayBuffer());
+    let ended = !reader;
+
+    for (;;) {
+        while (buffer.length >= 5) {
+            const length = new DataView(buffer.buffer, buffer.byteOffset).getUint32(1);
+            if (buffer.length < 5 + length) break;
+            yield { flags: buffer[0], data: buffer.subarray(5, 5 + length) };
+            buffer = buffer.subarray(5 + length);
+        }
+        if (ended) {
+            if (buffer.length > 0) {
+                throw new StatusError(StatusCode.DATA_LOSS, 'Response ended in the middle of a message');
+            }
+            return;
+        }
+
+        const { done, value } = await reader!.read();
+        if (done) {
+            ended = true;
+        } else {
+            const joined = new Uint8Array(buffer.length + value.length);
+            joined.set(buffer);
+            joined.set(value, buffer.length);
+            buffer = joined;
+        }
+    }
+}
+
+/**
+ * Parse gRPC-Web trailers, sent as HTTP/1 header lines in the last envelope
+ */
+function parseGrpcWebTrailer(text: string): Metadata {
+    const trailer: Metadata = {};
+    for (const line of text.split('\r\n')) {
+        const separator = line.indexOf(':');
+        if (separator <= 0) continue;
+        const name = line.substring(0, separator).trim().toLowerCase();
+        (trailer[name] ??= []).push(line.substring(separator + 1).trim());
+    }
+    return trailer;
+}
+
+/**
+ * Status code of a failed HTTP response without a gRPC status, as gRPC maps them
+ */
+function httpStatusCode(httpStatus: number): StatusCode {
+    switch (httpStatus) {
+        case 400: return StatusCode.INTERNAL;
+        case 401: return StatusCode.UNAUTHENTICATED;
+        case 403: return StatusCode.PERMISSION_DENIED;
+        case 404: return StatusCode.UNIMPLEMENTED;
+        case 429:
+        case 502:
+        case 503:
+        case 504: return StatusCode.UNAVAILABLE;
+        default: return StatusCode.UNKNOWN;
+    }
+}
+
+function decodeGrpcMessage(message: string): string {
+    try {
+        return decodeURIComponent(message);
+    } catch (e) {
+        return message;
+    }
+}
+
+function parseJSON(text: string): any {
+    try {
+        return JSON.parse(text);
+    } catch (e) {
+        return null;
+    }
+}
+
+/**
+ * Decode base64 as Connect sends it: standard or URL-safe, with or without padding
+ */
+function base64Decode(value: string): Uint8Array {
+    const binary = atob(value.replace(/-/g, '+').replace(/_/g, '/'));
+    const bytes = new Uint8Array(binary.length);
+    for (let i = 0; i < binary.length; i++) {
+        bytes[i] = binary.charCodeAt(i);
+    }
+    return bytes;
+}
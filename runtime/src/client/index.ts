@@ -14,9 +14,19 @@
 
 export {
   type WASMResponse,
+  type CallOptions,
+  type Metadata,
   WasmError,
+  StatusError,
+  StatusCode,
+  type StatusDetail,
+  type WasmStatus,
 } from './types.js';
 
 export { WASMServiceClient } from './base-client.js';
-export { WASMBundle, type WASMBundleConfig } from './wasm-bundle.js';
+export { WASMBundle, type WASMBundleConfig, type MethodTypes, type WasmLoader } from './wasm-bundle.js';
 export { ServiceClient } from './service-client.js';
+export { type Transport, type MethodInfo } from './transport.js';
+export { HttpTransport, type HttpTransportConfig } from './http-transport.js';
+export { FallbackTransport } from './fallback-transport.js';
+export { StreamCall, type StreamHandle } from './stream-call.js';
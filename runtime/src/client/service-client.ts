@@ -12,31 +12,34 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
-import { WASMBundle } from './wasm-bundle.js';
+import { StreamCall } from './stream-call.js';
+import { Transport, MethodInfo } from './transport.js';
+import { CallOptions, StatusError } from './types.js';
 
 /**
- * Base service client that references a shared WASM bundle
+ * Base service client that calls through a transport: normally the shared WASM bundle,
+ * or an HttpTransport or FallbackTransport to reach a server instead
  * Lightweight facade for service-specific method calls
  */
 export abstract class ServiceClient {
-    protected bundle: WASMBundle;
+    protected transport: Transport;
 
-    constructor(bundle: WASMBundle) {
-        this.bundle = bundle;
+    constructor(transport: Transport) {
+        this.transport = transport;
     }
 
     /**
-     * Check if the underlying WASM bundle is ready
+     * Check if the underlying transport is ready
      */
     public isReady(): boolean {
-        return this.bundle.isReady();
+        return this.transport.isReady();
     }
 
     /**
-     * Wait for the underlying WASM bundle to be ready
+     * Wait for the underlying transport to be ready
      */
     public async waitUntilReady(): Promise<void> {
-        return this.bundle.waitUntilReady();
+        return this.transport.waitUntilReady();
     }
 
     /**
@@ -44,9 +47,11 @@ export abstract class ServiceClient {
      */
     protected callMethod<TRequest, TResponse>(
         methodPath: string,
-        request: TRequest
+        request: TRequest,
+        options?: CallOptions,
+        method?: MethodInfo
     ): Promise<TResponse> {
-        return this.bundle.callMethod(methodPath, request);
+        return this.transport.callMethod(methodPath, request, options, method);
     }
 
     /**
@@ -55,9 +60,11 @@ export abstract class ServiceClient {
     protected callMethodWithCallback<TRequest>(
         methodPath: string,
         request: TRequest,
-        callback: (response: any, error?: string) => void
+        callback: (response: any, error?: string, status?: StatusError) => void,
+        options?: CallOptions,
+        method?: MethodInfo
     ): Promise<void> {
-        return this.bundle.callMethodWithCallback(methodPath, request, callback);
+        return this.transport.callMethodWithCallback(methodPath, request, callback, options, method);
     }
 
     /**
@@ -66,8 +73,21 @@ export abstract class ServiceClient {
     protected callStreamingMethod<TRequest, TResponse>(
         methodPath: string,
         request: TRequest,
-        callback: (response: TResponse | null, error: string | null, done: boolean) => boolean
+        callback: (response: TResponse | null, error: string | null, done: boolean, status?: StatusError) => boolean,
+        options?: CallOptions,
+        method?: MethodInfo
     ): void {
-        return this.bundle.callStreamingMethod(methodPath, request, callback);
+        return this.transport.callStreamingMethod(methodPath, request, callback, options, method);
+    }
+
+    /**
+     * Start a client streaming or bidirectional streaming WASM call
+     */
+    protected openStream<TRequest, TResponse>(
+        methodPath: string,
+        options?: CallOptions,
+        method?: MethodInfo
+    ): StreamCall<TRequest, TResponse> {
+        return this.transport.openStream(methodPath, options, method);
     }
 }
@@ -0,0 +1,166 @@
+// Copyright 2025 Sri Panyam
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import { WASMResponse, WasmError, errorFromResponse } from './types.js';
+
+/**
+ * Stream handle returned by the WASM module when a client or bidi streaming call starts
+ */
+export interface StreamHandle {
+    send(request: unknown): WASMResponse;
+    closeSend(): WASMResponse;
+    cancel(): void;
+}
+
+/**
+ * A client streaming or bidirectional streaming call.
+ *
+ * Send requests with send(), half-close with closeSend() and read responses
+ * with `for await (const response of call)`. Client streaming calls can use
+ * closeAndReceive() to close the request stream and wait for the single response.
+ * Iteration throws a StatusError if the call fails.
+ */
+export class StreamCall<TRequest, TResponse> implements AsyncIterable<TResponse> {
+    private handle: StreamHandle | null = null;
+    private responses: TResponse[] = [];
+    private waiters: Array<{ resolve: (result: IteratorResult<TResponse>) => void; reject: (error: WasmError) => void }> = [];
+    private error: WasmError | null = null;
+    private finished = false;
+    private sendClosed = false;
+
+    constructor(
+        public readonly methodPath: string,
+        private readonly encode: (request: TRequest) => unknown
+    ) {}
+
+    /**
+     * Send a request to the WASM method
+     */
+    public send(request: TRequest): void {
+        if (this.finished || this.sendClosed || !this.handle) {
+            throw new WasmError('Cannot send on a closed stream', this.methodPath);
+        }
+        const response = this.handle.send(this.encode(request));
+        if (!response.success) {
+            throw errorFromResponse(response, this.methodPath);
+        }
+    }
+
+    /**
+     * Close the request side of the stream; the WASM method's Recv returns io.EOF
+     */
+    public closeSend(): void {
+        if (this.finished || this.sendClosed || !this.handle) {
+            return;
+        }
+        this.sendClosed = true;
+        this.handle.closeSend();
+    }
+
+    /**
+     * Cancel the call, cancelling the WASM method's context
+     */
+    public cancel(): void {
+        if (this.finished || !this.handle) {
+            return;
+        }
+        this.handle.cancel();
+    }
+
+    /**
+     * Close the request stream and wait for the response (client streaming)
+     */
+    public async closeAndReceive(): Promise<TResponse> {
+        this.closeSend();
+        const result = await this.next();
+        if (!result.done) {
+            return result.value;
+        }
+        throw new WasmError('Stream completed without a response', this.methodPath);
+    }
+
+    public [Symbol.asyncIterator](): AsyncIterator<TResponse> {
+        return {
+            next: () => this.next(),
+            return: async () => {
+                // Stop the call when the consumer breaks out of iteration
+                this.cancel();
+                return { value: undefined, done: true };
+            },
+        };
+    }
+
+    /**
+     * Attach the stream handle returned by the WASM module
+     * @internal
+     */
+    public attach(handle: StreamHandle): void {
+        this.handle = handle;
+    }
+
+    /**
+     * Deliver a response from the WASM module
+     * @internal
+     */
+    public push(response: TResponse): void {
+        const waiter = this.waiters.shift();
+        if (waiter) {
+            waiter.resolve({ value: response, done: false });
+        } else {
+            this.responses.push(response);
+        }
+    }
+
+    /**
+     * Complete the stream, with an error if the call failed
+     * @internal
+     */
+    public finish(error?: WasmError): void {
+        if (this.finished) {
+            return;
+        }
+        this.finished = true;
+        this.handle = null; // The WASM module releases the handle's functions when the call ends
+        this.error = error ?? null;
+
+        const waiters = this.waiters;
+        this.waiters = [];
+        for (const waiter of waiters) {
+            if (this.error) {
+                waiter.reject(this.error);
+            } else {
+                waiter.resolve({ value: undefined, done: true });
+            }
+        }
+    }
+
+    /**
+     * Whether the call has completed
+     */
+    public get isFinished(): boolean {
+        return this.finished;
+    }
+
+    private next(): Promise<IteratorResult<TResponse>> {
+        if (this.responses.length > 0) {
+            return Promise.resolve({ value: this.responses.shift()!, done: false });
+        }
+        if (this.finished) {
+            return this.error ? Promise.reject(this.error) : Promise.resolve({ value: undefined, done: true });
+        }
+        return new Promise((resolve, reject) => {
+            this.waiters.push({ resolve, reject });
+        });
+    }
+}
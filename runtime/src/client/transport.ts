@@ -0,0 +1,87 @@
+// Copyright 2025 Sri Panyam
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import { MethodTypes } from './wasm-bundle.js';
+import { StreamCall } from './stream-call.js';
+import { CallOptions, StatusError } from './types.js';
+
+/**
+ * Proto identity of a method, passed by generated clients with every call.
+ * Remote transports route calls by fullMethod; the binary wire format encodes
+ * messages by their types.
+ */
+export interface MethodInfo extends MethodTypes {
+    fullMethod: string; // gRPC full method name (e.g., "/library.v1.LibraryService/FindBooks")
+}
+
+/**
+ * Carries the calls of generated service clients. WASMBundle calls the in-browser
+ * WASM module, HttpTransport a server speaking Connect or gRPC-Web with JSON, and
+ * FallbackTransport the server when the WASM module fails to load.
+ *
+ * Method paths name the WASM exports (following the bundle's API structure) and
+ * are ignored by remote transports, which use MethodInfo.fullMethod instead.
+ */
+export interface Transport {
+    /**
+     * Whether calls can be made without waiting
+     */
+    isReady(): boolean;
+
+    /**
+     * Wait until calls can be made
+     */
+    waitUntilReady(): Promise<void>;
+
+    /**
+     * Call a unary method
+     */
+    callMethod<TRequest, TResponse>(
+        methodPath: string,
+        request: TRequest,
+        options?: CallOptions,
+        method?: MethodInfo
+    ): Promise<TResponse>;
+
+    /**
+     * Call a unary method that reports its result through a callback (async WASM methods)
+     */
+    callMethodWithCallback<TRequest>(
+        methodPath: string,
+        request: TRequest,
+        callback: (response: any, error?: string, status?: StatusError) => void,
+        options?: CallOptions,
+        method?: MethodInfo
+    ): Promise<void>;
+
+    /**
+     * Call a server streaming method; returning false from the callback stops the stream
+     */
+    callStreamingMethod<TRequest, TResponse>(
+        methodPath: string,
+        request: TRequest,
+        callback: (response: TResponse | null, error: string | null, done: boolean, status?: StatusError) => boolean,
+        options?: CallOptions,
+        method?: MethodInfo
+    ): void;
+
+    /**
+     * Start a client streaming or bidirectional streaming call
+     */
+    openStream<TRequest, TResponse>(
+        methodPath: string,
+        options?: CallOptions,
+        method?: MethodInfo
+    ): StreamCall<TRequest, TResponse>;
+}
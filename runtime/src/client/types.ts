@@ -19,6 +19,64 @@ export interface WASMResponse<T = any> {
     success: boolean;
     message: string;
     data: T;
+    error?: WasmStatus; // Structured gRPC status of a failed service call
+}
+
+/**
+ * gRPC metadata returned by a WASM method, keyed by lowercase name
+ */
+export type Metadata = Record<string, string[]>;
+
+/**
+ * Per-call options accepted by generated client methods
+ */
+export interface CallOptions {
+    timeoutMs?: number; // Overrides the method's timeout for this call (0 disables the timeout)
+    signal?: AbortSignal; // Cancels the call (and stops server streams) when aborted
+    headers?: Record<string, string | string[]>; // Incoming gRPC metadata of the Go method (metadata.FromIncomingContext)
+    onHeader?: (header: Metadata) => void; // Receives metadata the Go method set with grpc.SetHeader/SendHeader
+    onTrailer?: (trailer: Metadata) => void; // Receives metadata the Go method set with grpc.SetTrailer
+}
+
+/**
+ * gRPC status codes
+ */
+export enum StatusCode {
+    OK = 0,
+    CANCELLED = 1,
+    UNKNOWN = 2,
+    INVALID_ARGUMENT = 3,
+    DEADLINE_EXCEEDED = 4,
+    NOT_FOUND = 5,
+    ALREADY_EXISTS = 6,
+    PERMISSION_DENIED = 7,
+    RESOURCE_EXHAUSTED = 8,
+    FAILED_PRECONDITION = 9,
+    ABORTED = 10,
+    OUT_OF_RANGE = 11,
+    UNIMPLEMENTED = 12,
+    INTERNAL = 13,
+    UNAVAILABLE = 14,
+    DATA_LOSS = 15,
+    UNAUTHENTICATED = 16,
+}
+
+/**
+ * A gRPC status detail (the contents of a google.protobuf.Any)
+ */
+export interface StatusDetail {
+    typeUrl: string;    // e.g., "type.googleapis.com/google.rpc.BadRequest"
+    value: Uint8Array;  // Serialized detail message
+    message?: any;      // Decoded detail message, when its type is known
+}
+
+/**
+ * Structured gRPC status as passed across the WASM boundary
+ */
+export interface WasmStatus {
+    code: StatusCode;
+    message: string;
+    details: StatusDetail[];
 }
 
 /**
@@ -30,3 +88,43 @@ export class WasmError extends Error {
         this.name = 'WasmError';
     }
 }
+
+/**
+ * Error thrown (or passed to callbacks) when a WASM service call fails with a gRPC status
+ */
+export class StatusError extends WasmError {
+    constructor(
+        public readonly code: StatusCode,
+        message: string,
+        public readonly details: StatusDetail[] = [],
+        methodPath?: string
+    ) {
+        super(message, methodPath);
+        this.name = 'StatusError';
+    }
+
+    /**
+     * Name of the status code (e.g., "NOT_FOUND")
+     */
+    get codeName(): string {
+        return StatusCode[this.code] ?? 'UNKNOWN';
+    }
+
+    /**
+     * Create a StatusError from the status object produced by the WASM module
+     */
+    static fromStatus(status: WasmStatus, methodPath?: string): StatusError {
+        return new StatusError(status.code, status.message, status.details || [], methodPath);
+    }
+}
+
+/**
+ * Create the error for a failed WASM response: a StatusError when the
+ * response carries a structured status, otherwise a plain WasmError
+ */
+export function errorFromResponse(response: WASMResponse, methodPath?: string): WasmError {
+    if (response.error) {
+        return StatusError.fromStatus(response.error, methodPath);
+    }
+    return new WasmError(response.message, methodPath);
+}
@@ -13,7 +13,13 @@
 // limitations under the License.
 
 import { BrowserServiceManager } from '../browser/service-manager.js';
-import { WasmError } from './types.js';
+import { BinaryCodec } from '../schema/binary-codec.js';
+import { MessageSchema } from '../schema/types.js';
+import { CallOptions, WASMResponse, WasmError, StatusError, StatusCode, WasmStatus } from './types.js';
+import { StreamCall, StreamHandle } from './stream-call.js';
+import { WorkerTransport } from '../worker/worker-transport.js';
+import { Transport } from './transport.js';
+import { MessageEndpoint } from '../worker/protocol.js';
 
 /**
  * Configuration for API structure and bundle behavior
@@ -22,22 +28,52 @@ export interface WASMBundleConfig {
     moduleName: string;
     apiStructure: 'namespaced' | 'flat' | 'service_based';
     jsNamespace: string;
+    wireFormat?: 'json' | 'binary'; // Must match the wire_format the WASM module was generated with
+    schemas?: Record<string, MessageSchema>; // Schemas used to encode/decode messages for the binary wire format
+    wasmExecPath?: string; // Where Go's wasm_exec.js is served from (default: /wasm_exec.js)
+    loader?: WasmLoader; // Starts the Go program outside browsers (e.g. nodeWasmLoader from the runtime's node entry)
+}
+
+/**
+ * Starts the Go program of a WASM module, given the path passed to loadWasm.
+ * Resolves once the program has registered its exports on globalThis.
+ */
+export type WasmLoader = (wasmPath: string) => Promise<void>;
+
+/**
+ * Fully qualified proto message types of a method.
+ * Required to encode requests and decode responses with the binary wire format.
+ */
+export interface MethodTypes {
+    requestType: string;
+    responseType: string;
 }
 
 /**
  * WASM Bundle - manages loading and shared access to a WASM module
  * One bundle per WASM file, shared by multiple service clients
+ * (it is their Transport to the in-browser module)
  */
-export class WASMBundle {
+export class WASMBundle implements Transport {
     private wasm: any = null;
     private wasmLoadPromise: Promise<void> | null = null;
     private wasmLoaded = false
     private browserServiceManager: BrowserServiceManager | null = null;
     private config: WASMBundleConfig;
+    private codec: BinaryCodec | null = null;
+    private transport: WorkerTransport | null = null;
 
     constructor(config: WASMBundleConfig) {
         this.config = config;
-        this.browserServiceManager = new BrowserServiceManager();
+        // Bind to this module's browser channel, whose globals are suffixed with its JS namespace
+        this.browserServiceManager = new BrowserServiceManager(config.jsNamespace);
+        if (config.wireFormat === 'binary') {
+            this.codec = new BinaryCodec(config.schemas || {});
+        }
+        // Browser clients of TinyGo builds pass protobuf bytes, decoded with the schemas
+        if (config.schemas) {
+            this.browserServiceManager.setCodec(this.codec || new BinaryCodec(config.schemas));
+        }
     }
 
     /**
@@ -48,13 +84,14 @@ export class WASMBundle {
             throw new Error('Browser service manager not initialized');
         }
         this.browserServiceManager.registerService(name, implementation);
+        this.transport?.registerBrowserService(name);
     }
 
     /**
      * Check if WASM is ready for operations
      */
     public isReady(): boolean {
-        return this.wasm !== null && this.wasm !== undefined;
+        return this.transport !== null || (this.wasm !== null && this.wasm !== undefined);
     }
 
     /**
@@ -90,6 +127,53 @@ export class WASMBundle {
         this.wasmLoaded = true
     }
 
+    /**
+     * Load the WASM module in a Web Worker running startWorkerHost (such as the worker.ts
+     * entry point generated with generate_worker=true), so its methods do not block the
+     * main thread. Clients call it as usual; browser services registered on this bundle
+     * keep running on the main thread.
+     *
+     *     bundle.loadWasmInWorker(new Worker(new URL('./worker.ts', import.meta.url), { type: 'module' }), '/app.wasm');
+     */
+    public async loadWasmInWorker(worker: MessageEndpoint & { terminate?(): void }, wasmPath: string): Promise<void> {
+        if (this.wasmLoadPromise) {
+            return this.wasmLoadPromise;
+        }
+
+        const transport = new WorkerTransport(worker, this.browserServiceManager!);
+        this.wasmLoadPromise = transport.load(wasmPath);
+        try {
+            await this.wasmLoadPromise;
+        } catch (error) {
+            transport.terminate();
+            this.wasmLoadPromise = null;
+            throw error;
+        }
+        this.transport = transport;
+        this.wasmLoaded = true;
+    }
+
+    /**
+     * Unregister the WASM module: releases its exported functions and globals, stops
+     * browser service processing and lets the Go program's main return.
+     * loadWasm() can then load a new module (e.g. when hot-swapping during development).
+     */
+    public unload(): void {
+        if (this.transport) {
+            // Unloads the module in the worker and stops it
+            this.transport.terminate();
+            this.transport = null;
+        }
+        const unregister = (globalThis as any)[`__wasmUnregister_${this.config.jsNamespace}`];
+        if (typeof unregister === 'function') {
+            unregister();
+        }
+        this.browserServiceManager?.stopProcessing();
+        this.wasm = null;
+        this.wasmLoadPromise = null;
+        this.wasmLoaded = false;
+    }
+
     /**
      * Get WASM method function by path
      */
@@ -135,24 +219,29 @@ export class WASMBundle {
     }
 
     /**
-     * Internal method to call WASM functions with JSON conversion
+     * Internal method to call WASM functions with JSON (or binary) conversion
      */
     public callMethod<TRequest, TResponse>(
         methodPath: string,
-        request: TRequest
+        request: TRequest,
+        options?: CallOptions,
+        types?: MethodTypes
     ): Promise<TResponse> {
         try {
-            // Convert request to JSON
-            const jsonReq = JSON.parse(JSON.stringify(request));
-            const wasmMethod = this.getWasmMethod(methodPath);
-            const wasmResponse = wasmMethod(JSON.stringify(jsonReq));
+            const cancelled = this.cancelledError(options, methodPath);
+            if (cancelled) {
+                throw cancelled;
+            }
 
-            if (!wasmResponse.success) {
-                throw new WasmError(wasmResponse.message, methodPath);
+            if (this.transport) {
+                return this.transport.call(methodPath, this.encodeRequest(methodPath, request, types), options)
+                    .then(wasmResponse => this.unaryResult<TResponse>(wasmResponse, methodPath, types));
             }
 
-            // Return response data directly
-            return wasmResponse.data;
+            const wasmMethod = this.getWasmMethod(methodPath);
+            const wasmResponse = wasmMethod(this.encodeRequest(methodPath, request, types), options);
+
+            return this.unaryResult(wasmResponse, methodPath, types);
         } catch (error) {
             if (error instanceof WasmError) {
                 throw error;
@@ -170,18 +259,40 @@ export class WASMBundle {
     public callMethodWithCallback<TRequest>(
         methodPath: string,
         request: TRequest,
-        callback: (response: any, error?: string) => void
+        callback: (response: any, error?: string, status?: StatusError) => void,
+        options?: CallOptions,
+        types?: MethodTypes
     ): Promise<void> {
         try {
-            // Convert request to JSON
-            const jsonReq = JSON.parse(JSON.stringify(request));
-            const wasmMethod = this.getWasmMethod(methodPath);
-            
+            const cancelled = this.cancelledError(options, methodPath);
+            if (cancelled) {
+                callback(null, cancelled.message, cancelled);
+                return Promise.resolve();
+            }
+
+            // Decode binary responses and error statuses before handing them to the caller
+            const wrappedCallback = (response: any, error?: string, status?: WasmStatus): void => {
+                if (response && !error) {
+                    response = this.decodeResponse(response, types);
+                }
+                callback(response, error, status ? this.statusError(status, methodPath) : undefined);
+            };
+
+            if (this.transport) {
+                return this.transport.callWithCallback(methodPath, this.encodeRequest(methodPath, request, types), wrappedCallback, options)
+                    .then(wasmResponse => {
+                        if (!wasmResponse.success) {
+                            throw this.responseError(wasmResponse, methodPath);
+                        }
+                    });
+            }
+
             // Call WASM method with callback function
-            const wasmResponse = wasmMethod(JSON.stringify(jsonReq), callback);
+            const wasmMethod = this.getWasmMethod(methodPath);
+            const wasmResponse = wasmMethod(this.encodeRequest(methodPath, request, types), wrappedCallback, options);
 
             if (!wasmResponse.success) {
-                throw new WasmError(wasmResponse.message, methodPath);
+                throw this.responseError(wasmResponse, methodPath);
             }
 
             // Async methods return immediately
@@ -203,32 +314,44 @@ export class WASMBundle {
     public callStreamingMethod<TRequest, TResponse>(
         methodPath: string,
         request: TRequest,
-        callback: (response: TResponse | null, error: string | null, done: boolean) => boolean
+        callback: (response: TResponse | null, error: string | null, done: boolean, status?: StatusError) => boolean,
+        options?: CallOptions,
+        types?: MethodTypes
     ): void {
         try {
-            // Convert request to JSON
-            const jsonReq = JSON.parse(JSON.stringify(request));
-            const wasmMethod = this.getWasmMethod(methodPath);
+            const cancelled = this.cancelledError(options, methodPath);
+            if (cancelled) {
+                callback(null, cancelled.message, true, cancelled);
+                return;
+            }
 
-            // Wrap the callback to parse JSON responses
-            const wrappedCallback = (responseStr: string | null, error: string | null, done: boolean): boolean => {
+            // Wrap the callback to parse JSON (or decode binary) responses
+            const wrappedCallback = (responseData: any, error: string | null, done: boolean, status?: WasmStatus): boolean => {
                 let response: TResponse | null = null;
-                if (responseStr && !error) {
-                    try {
-                        response = JSON.parse(responseStr);
-                    } catch (e) {
-                        // If parsing fails, pass the raw string
-                        response = responseStr as any;
-                    }
+                if (responseData && !error) {
+                    response = this.decodeStreamResponse(responseData, types);
                 }
-                return callback(response, error, done);
+                return callback(response, error, done, status ? this.statusError(status, methodPath) : undefined);
             };
 
+            if (this.transport) {
+                // The worker reports failures to start asynchronously, so they end the stream
+                this.transport.callWithCallback(methodPath, this.encodeRequest(methodPath, request, types), wrappedCallback, options)
+                    .then(wasmResponse => {
+                        if (!wasmResponse.success) {
+                            const error = this.responseError(wasmResponse, methodPath);
+                            callback(null, error.message, true, error instanceof StatusError ? error : undefined);
+                        }
+                    });
+                return;
+            }
+
             // Call WASM streaming method with wrapped callback
-            const wasmResponse = wasmMethod(JSON.stringify(jsonReq), wrappedCallback);
+            const wasmMethod = this.getWasmMethod(methodPath);
+            const wasmResponse = wasmMethod(this.encodeRequest(methodPath, request, types), wrappedCallback, options);
 
             if (!wasmResponse.success) {
-                throw new WasmError(wasmResponse.message, methodPath);
+                throw this.responseError(wasmResponse, methodPath);
             }
 
             // Streaming methods return immediately
@@ -243,6 +366,165 @@ export class WASMBundle {
         }
     }
 
+    /**
+     * Start a client streaming or bidirectional streaming WASM call.
+     * Requests are sent through the returned StreamCall, which also yields the responses.
+     */
+    public openStream<TRequest, TResponse>(
+        methodPath: string,
+        options?: CallOptions,
+        types?: MethodTypes
+    ): StreamCall<TRequest, TResponse> {
+        const call = new StreamCall<TRequest, TResponse>(
+            methodPath,
+            (request: TRequest) => this.encodeRequest(methodPath, request, types)
+        );
+
+        const cancelled = this.cancelledError(options, methodPath);
+        if (cancelled) {
+            call.finish(cancelled);
+            return call;
+        }
+
+        try {
+            // Deliver decoded responses to the call; returning false stops the WASM side
+            const responseCallback = (responseData: any, error: string | null, done: boolean, status?: WasmStatus): boolean => {
+                if (responseData && !error) {
+                    call.push(this.decodeStreamResponse<TResponse>(responseData, types));
+                }
+                if (done) {
+                    if (error) {
+                        call.finish(status ? this.statusError(status, methodPath) : new WasmError(error, methodPath));
+                    } else {
+                        call.finish();
+                    }
+                }
+                return !call.isFinished;
+            };
+
+            if (this.transport) {
+                call.attach(this.transport.openStream(methodPath, responseCallback, options));
+                return call;
+            }
+
+            const wasmMethod = this.getWasmMethod(methodPath);
+            const handle = wasmMethod(responseCallback, options);
+            if (!handle.success) {
+                throw this.responseError(handle, methodPath);
+            }
+            call.attach(handle as StreamHandle);
+        } catch (error) {
+            if (error instanceof WasmError) {
+                throw error;
+            }
+            throw new WasmError(
+                `Streaming call error: ${error instanceof Error ? error.message : String(error)}`,
+                methodPath
+            );
+        }
+
+        return call;
+    }
+
+    /**
+     * Extract the response of a synchronous call, or throw its error
+     */
+    private unaryResult<TResponse>(wasmResponse: WASMResponse, methodPath: string, types?: MethodTypes): TResponse {
+        if (!wasmResponse.success) {
+            throw this.responseError(wasmResponse, methodPath);
+        }
+
+        // Return response data directly
+        return this.decodeResponse(wasmResponse.data, types);
+    }
+
+    /**
+     * Create the CANCELLED error for a call whose AbortSignal has already fired.
+     * Signals that fire during a call are handled by the WASM module, which cancels
+     * the method's context.
+     */
+    private cancelledError(options: CallOptions | undefined, methodPath: string): StatusError | null {
+        if (!options?.signal?.aborted) {
+            return null;
+        }
+        return new StatusError(StatusCode.CANCELLED, 'Call cancelled', [], methodPath);
+    }
+
+    /**
+     * Create the error for a failed WASM response, preferring the structured status when present
+     */
+    private responseError(wasmResponse: WASMResponse, methodPath: string): WasmError {
+        if (wasmResponse.error) {
+            return this.statusError(wasmResponse.error, methodPath);
+        }
+        return new WasmError(wasmResponse.message, methodPath);
+    }
+
+    /**
+     * Create a StatusError from a WASM status, decoding details whose schemas are
+     * known when the WASM module did not already decode them
+     */
+    private statusError(status: WasmStatus, methodPath: string): StatusError {
+        const details = (status.details || []).map(detail => {
+            if (detail.message !== undefined || !this.codec) {
+                return detail;
+            }
+            const typeName = detail.typeUrl.substring(detail.typeUrl.lastIndexOf('/') + 1);
+            try {
+                return { ...detail, message: this.codec.decode(typeName, detail.value) };
+            } catch (e) {
+                // Unknown detail type - leave it undecoded
+                return detail;
+            }
+        });
+        return new StatusError(status.code, status.message, details, methodPath);
+    }
+
+    /**
+     * Encode a request for the configured wire format:
+     * the request object by default, which the module converts with its marshaller,
+     * or protobuf bytes for the binary wire format
+     */
+    private encodeRequest<TRequest>(methodPath: string, request: TRequest, types?: MethodTypes): TRequest | Uint8Array {
+        if (!this.codec) {
+            return request;
+        }
+        if (!types) {
+            throw new WasmError('Message types are required for the binary wire format', methodPath);
+        }
+        return this.codec.encode(types.requestType, request);
+    }
+
+    /**
+     * Decode response data for the configured wire format.
+     * JSON responses arrive already parsed; binary responses are Uint8Array protobuf bytes.
+     */
+    private decodeResponse<TResponse>(data: any, types?: MethodTypes): TResponse {
+        if (!this.codec || !types) {
+            return data;
+        }
+        return this.codec.decode<TResponse>(types.responseType, data);
+    }
+
+    /**
+     * Decode a streamed response: protobuf bytes for the binary wire format,
+     * otherwise an object (or a JSON string from modules generated before objects were passed)
+     */
+    private decodeStreamResponse<TResponse>(responseData: any, types?: MethodTypes): TResponse {
+        if (this.codec) {
+            return this.decodeResponse(responseData, types);
+        }
+        if (typeof responseData !== 'string') {
+            return responseData;
+        }
+        try {
+            return JSON.parse(responseData as string);
+        } catch (e) {
+            // If parsing fails, pass the raw string
+            return responseData as any;
+        }
+    }
+
     /**
      * Ensure WASM module is loaded (synchronous version for service calls)
      */
@@ -264,20 +546,35 @@ export class WASMBundle {
             return;
         }
 
-        // Load Go's WASM support
-        if (!(window as any).Go) {
-            const script = document.createElement('script');
-            script.src = '/wasm_exec.js';
-            document.head.appendChild(script);
+        if (this.config.loader) {
+            await this.config.loader(wasmPath);
+        } else {
+            await this.runInBrowser(wasmPath);
+        }
 
-            await new Promise<void>((resolve, reject) => {
-                script.onload = () => resolve();
-                script.onerror = () => reject(new Error('Failed to load wasm_exec.js'));
-            });
+        // Start browser service manager
+        if (this.browserServiceManager) {
+            this.browserServiceManager.setWasmModule(globalThis);
+            this.browserServiceManager.startProcessing();
+        }
+
+        // Verify WASM APIs are available
+        this.verifyWASMLoaded();
+
+        console.log(`${this.config.moduleName} WASM module loaded successfully`);
+    }
+
+    /**
+     * Fetch and run the WASM module with Go's browser support
+     */
+    private async runInBrowser(wasmPath: string): Promise<void> {
+        // Load Go's WASM support
+        if (!(globalThis as any).Go) {
+            await this.loadWasmExec(this.config.wasmExecPath || '/wasm_exec.js');
         }
 
         // Initialize Go WASM runtime
-        const go = new (window as any).Go();
+        const go = new (globalThis as any).Go();
         const wasmModule = await WebAssembly.instantiateStreaming(
             fetch(wasmPath),
             go.importObject
@@ -285,17 +582,35 @@ export class WASMBundle {
 
         // Run the WASM module
         go.run(wasmModule.instance);
+    }
 
-        // Start browser service manager
-        if (this.browserServiceManager) {
-            this.browserServiceManager.setWasmModule(window);
-            this.browserServiceManager.startProcessing();
-        }
+    /**
+     * Load Go's wasm_exec.js: with a script tag on a page, with importScripts in a
+     * classic worker, or as a module in a module worker
+     */
+    private async loadWasmExec(wasmExecPath: string): Promise<void> {
+        if (typeof document !== 'undefined') {
+            const script = document.createElement('script');
+            script.src = wasmExecPath;
+            document.head.appendChild(script);
 
-        // Verify WASM APIs are available
-        this.verifyWASMLoaded();
+            await new Promise<void>((resolve, reject) => {
+                script.onload = () => resolve();
+                script.onerror = () => reject(new Error(`Failed to load ${wasmExecPath}`));
+            });
+            return;
+        }
 
-        console.log(`${this.config.moduleName} WASM module loaded successfully`);
+        const importScripts = (globalThis as any).importScripts;
+        if (typeof importScripts === 'function') {
+            try {
+                importScripts(wasmExecPath);
+                return;
+            } catch (e) {
+                // Module workers cannot use importScripts
+            }
+        }
+        await import(/* @vite-ignore */ wasmExecPath);
     }
 
     /**
@@ -304,8 +619,8 @@ export class WASMBundle {
     private checkIfPreLoaded(): boolean {
         switch (this.config.apiStructure) {
             case 'namespaced':
-                if ((window as any)[this.config.jsNamespace]) {
-                    this.wasm = (window as any)[this.config.jsNamespace];
+                if ((globalThis as any)[this.config.jsNamespace]) {
+                    this.wasm = (globalThis as any)[this.config.jsNamespace];
                     return true;
                 }
                 return false;
@@ -313,15 +628,15 @@ export class WASMBundle {
             case 'flat':
                 // For flat structure, we need to check for any method existence
                 // This is a simplified check - in reality we'd check for a known method
-                if ((window as any)[this.config.jsNamespace + 'LoadUserData']) {
-                    this.wasm = window as any;
+                if ((globalThis as any)[this.config.jsNamespace + 'LoadUserData']) {
+                    this.wasm = globalThis as any;
                     return true;
                 }
                 return false;
 
             case 'service_based':
-                if ((window as any).services) {
-                    this.wasm = (window as any).services;
+                if ((globalThis as any).services) {
+                    this.wasm = (globalThis as any).services;
                     return true;
                 }
                 return false;
@@ -337,25 +652,25 @@ export class WASMBundle {
     private verifyWASMLoaded(): void {
         switch (this.config.apiStructure) {
             case 'namespaced':
-                if (!(window as any)[this.config.jsNamespace]) {
+                if (!(globalThis as any)[this.config.jsNamespace]) {
                     throw new Error('WASM APIs not found - module may not have loaded correctly');
                 }
-                this.wasm = (window as any)[this.config.jsNamespace];
+                this.wasm = (globalThis as any)[this.config.jsNamespace];
                 break;
 
             case 'flat':
                 // For flat structure, check for a known method
-                if (!(window as any)[this.config.jsNamespace + 'LoadUserData']) {
+                if (!(globalThis as any)[this.config.jsNamespace + 'LoadUserData']) {
                     throw new Error('WASM APIs not found - module may not have loaded correctly');
                 }
-                this.wasm = window as any;
+                this.wasm = globalThis as any;
                 break;
 
             case 'service_based':
-                if (!(window as any).services) {
+                if (!(globalThis as any).services) {
                     throw new Error('WASM APIs not found - module may not have loaded correctly');
                 }
-                this.wasm = (window as any).services;
+                this.wasm = (globalThis as any).services;
                 break;
 
             default:
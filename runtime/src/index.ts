@@ -13,7 +13,7 @@
 // limitations under the License.
 
 // Browser utilities
-export { BrowserServiceManager } from './browser/index.js';
+export { BrowserServiceManager, type BrowserCallContext, type BrowserStreamCallContext } from './browser/index.js';
 
 // Schema types
 export {
@@ -22,19 +22,43 @@ export {
   type MessageSchema,
   BaseDeserializer,
   BaseSchemaRegistry,
+  BinaryCodec,
   type MessageTypeProvider, type MessageTypeConstructor, 
 } from './schema/index.js';
 
 // Client types
 export {
   type WASMResponse,
+  type CallOptions,
+  type Metadata,
   WasmError,
+  StatusError,
+  StatusCode,
+  type StatusDetail,
+  type WasmStatus,
   WASMServiceClient,
   WASMBundle,
   type WASMBundleConfig,
+  type WasmLoader,
+  type MethodTypes,
   ServiceClient,
+  type Transport,
+  type MethodInfo,
+  HttpTransport,
+  type HttpTransportConfig,
+  FallbackTransport,
+  StreamCall,
+  type StreamHandle,
 } from './client/index.js';
 
+// Web Worker hosting
+export {
+  WorkerTransport,
+  startWorkerHost,
+  type WorkerHostConfig,
+  type MessageEndpoint,
+} from './worker/index.js';
+
 // Factory and patch types
 export {
   type FactoryResult,
@@ -0,0 +1,68 @@
+// Copyright 2025 Sri Panyam
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+
+import { WasmLoader } from '../client/wasm-bundle.js';
+
+/**
+ * Options of the Node.js WASM loader
+ */
+export interface NodeWasmLoaderOptions {
+    // Path of Go's wasm_exec.js (default: wasm_exec.js next to the WASM file, where the
+    // generated build script copies it)
+    wasmExecPath?: string;
+}
+
+/**
+ * Create a loader that runs WASM modules in Node.js, for server-side rendering, CLI tools
+ * and tests. It sets up the globals Go's wasm_exec.js expects (as Go's wasm_exec_node.js
+ * does), reads the WASM file from disk and runs it; exports and browser channel globals
+ * are then registered on globalThis as in a browser.
+ *
+ *     const bundle = new WASMBundle({ ...config, loader: nodeWasmLoader() });
+ *     await bundle.loadWasm('./dist/app.wasm');
+ *
+ * Browser-provided services are implemented in Node by registering them on the bundle.
+ */
+export function nodeWasmLoader(options: NodeWasmLoaderOptions = {}): WasmLoader {
+    return async (wasmPath: string) => {
+        const [fs, path, url, crypto] = await Promise.all([
+            import('node:fs'),
+            import('node:path'),
+            import('node:url'),
+            import('node:crypto'),
+        ]);
+
+        const g = globalThis as any;
+        g.fs ??= fs;
+        g.path ??= path;
+        g.crypto ??= crypto.webcrypto;
+
+        // Load Go's WASM support
+        if (!g.Go) {
+            const wasmExecPath = options.wasmExecPath ?? path.join(path.dirname(wasmPath), 'wasm_exec.js');
+            await import(url.pathToFileURL(path.resolve(wasmExecPath)).href);
+            if (!g.Go) {
+                throw new Error(`Go WASM runtime not found in ${wasmExecPath}`);
+            }
+        }
+
+        // Initialize Go WASM runtime
+        const go = new g.Go();
+        const wasmModule = await WebAssembly.instantiate(await fs.promises.readFile(wasmPath), go.importObject);
+
+        // Run the WASM module; it returns once the program blocks after registering its exports
+        go.run(wasmModule.instance);
+    };
+}
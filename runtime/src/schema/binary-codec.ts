@@ -0,0 +1,647 @@
+// Copyright 2025 Sri Panyam
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import { FieldType, FieldSchema, MessageSchema } from './types.js';
+
+/**
+ * Protobuf wire types
+ */
+enum WireType {
+  VARINT = 0,
+  FIXED64 = 1,
+  LENGTH_DELIMITED = 2,
+  START_GROUP = 3,
+  END_GROUP = 4,
+  FIXED32 = 5,
+}
+
+/**
+ * Scalar kinds that can be packed in repeated fields
+ */
+const PACKABLE_KINDS = new Set([
+  'int32', 'int64', 'uint32', 'uint64', 'sint32', 'sint64',
+  'fixed32', 'fixed64', 'sfixed32', 'sfixed64',
+  'float', 'double', 'bool', 'enum',
+]);
+
+const textEncoder = new TextEncoder();
+const textDecoder = new TextDecoder();
+
+/**
+ * Growable byte buffer for encoding protobuf messages
+ */
+class BinaryWriter {
+  private buf = new Uint8Array(64);
+  private pos = 0;
+  private view = new DataView(this.buf.buffer);
+
+  private ensure(size: number): void {
+    if (this.pos + size <= this.buf.length) {
+      return;
+    }
+    let capacity = this.buf.length * 2;
+    while (capacity < this.pos + size) {
+      capacity *= 2;
+    }
+    const next = new Uint8Array(capacity);
+    next.set(this.buf.subarray(0, this.pos));
+    this.buf = next;
+    this.view = new DataView(next.buffer);
+  }
+
+  tag(fieldNumber: number, wireType: WireType): void {
+    this.uint32(((fieldNumber << 3) | wireType) >>> 0);
+  }
+
+  uint32(value: number): void {
+    this.ensure(5);
+    value >>>= 0;
+    while (value > 0x7f) {
+      this.buf[this.pos++] = (value & 0x7f) | 0x80;
+      value >>>= 7;
+    }
+    this.buf[this.pos++] = value;
+  }
+
+  uint64(value: bigint): void {
+    this.ensure(10);
+    value = BigInt.asUintN(64, value);
+    while (value > 0x7fn) {
+      this.buf[this.pos++] = Number(value & 0x7fn) | 0x80;
+      value >>= 7n;
+    }
+    this.buf[this.pos++] = Number(value);
+  }
+
+  fixed32(value: number, signed: boolean): void {
+    this.ensure(4);
+    if (signed) {
+      this.view.setInt32(this.pos, value, true);
+    } else {
+      this.view.setUint32(this.pos, value >>> 0, true);
+    }
+    this.pos += 4;
+  }
+
+  fixed64(value: bigint, signed: boolean): void {
+    this.ensure(8);
+    if (signed) {
+      this.view.setBigInt64(this.pos, BigInt.asIntN(64, value), true);
+    } else {
+      this.view.setBigUint64(this.pos, BigInt.asUintN(64, value), true);
+    }
+    this.pos += 8;
+  }
+
+  float(value: number): void {
+    this.ensure(4);
+    this.view.setFloat32(this.pos, value, true);
+    this.pos += 4;
+  }
+
+  double(value: number): void {
+    this.ensure(8);
+    this.view.setFloat64(this.pos, value, true);
+    this.pos += 8;
+  }
+
+  bytes(value: Uint8Array): void {
+    this.uint32(value.length);
+    this.ensure(value.length);
+    this.buf.set(value, this.pos);
+    this.pos += value.length;
+  }
+
+  finish(): Uint8Array {
+    return this.buf.slice(0, this.pos);
+  }
+}
+
+/**
+ * Cursor over protobuf encoded bytes
+ */
+class BinaryReader {
+  private pos = 0;
+  private view: DataView;
+
+  constructor(private buf: Uint8Array, private end: number = buf.length) {
+    this.view = new DataView(buf.buffer, buf.byteOffset, buf.byteLength);
+  }
+
+  hasMore(): boolean {
+    return this.pos < this.end;
+  }
+
+  uint32(): number {
+    let result = 0;
+    for (let shift = 0; shift < 64; shift += 7) {
+      if (this.pos >= this.end) {
+        throw new Error('Unexpected end of protobuf data');
+      }
+      const byte = this.buf[this.pos++];
+      if (shift < 32) {
+        result |= (byte & 0x7f) << shift;
+      }
+      if ((byte & 0x80) === 0) {
+        return result >>> 0;
+      }
+    }
+    throw new Error('Malformed protobuf varint');
+  }
+
+  uint64(): bigint {
+    let result = 0n;
+    let shift = 0n;
+    while (true) {
+      if (this.pos >= this.end) {
+        throw new Error('Unexpected end of protobuf data');
+      }
+      const byte = this.buf[this.pos++];
+      result |= BigInt(byte & 0x7f) << shift;
+      if ((byte & 0x80) === 0) {
+        return result;
+      }
+      shift += 7n;
+    }
+  }
+
+  fixed32(signed: boolean): number {
+    const value = signed ? this.view.getInt32(this.pos, true) : this.view.getUint32(this.pos, true);
+    this.pos += 4;
+    return value;
+  }
+
+  fixed64(signed: boolean): bigint {
+    const value = signed ? this.view.getBigInt64(this.pos, true) : this.view.getBigUint64(this.pos, true);
+    this.pos += 8;
+    return value;
+  }
+
+  float(): number {
+    const value = this.view.getFloat32(this.pos, true);
+    this.pos += 4;
+    return value;
+  }
+
+  double(): number {
+    const value = this.view.getFloat64(this.pos, true);
+    this.pos += 8;
+    return value;
+  }
+
+  bytes(): Uint8Array {
+    const length = this.uint32();
+    const start = this.pos;
+    this.pos += length;
+    if (this.pos > this.end) {
+      throw new Error('Unexpected end of protobuf data');
+    }
+    return this.buf.subarray(start, this.pos);
+  }
+
+  skip(wireType: WireType): void {
+    switch (wireType) {
+      case WireType.VARINT:
+        this.uint64();
+        break;
+      case WireType.FIXED64:
+        this.pos += 8;
+        break;
+      case WireType.LENGTH_DELIMITED:
+        this.bytes();
+        break;
+      case WireType.FIXED32:
+        this.pos += 4;
+        break;
+      case WireType.START_GROUP:
+        while (this.hasMore()) {
+          const tag = this.uint32();
+          if ((tag & 7) === WireType.END_GROUP) {
+            return;
+          }
+          this.skip(tag & 7);
+        }
+        break;
+      default:
+        throw new Error(`Unsupported protobuf wire type: ${wireType}`);
+    }
+  }
+}
+
+/**
+ * Schema-driven protobuf binary codec.
+ * Encodes the plain objects used by generated TypeScript interfaces into protobuf
+ * wire format and back, so they can cross the WASM boundary as Uint8Array when
+ * the generators are run with wire_format=binary.
+ *
+ * 64-bit integers are decoded as numbers to match the generated TypeScript types.
+ * Fields of message types without a registered schema (e.g. google.protobuf
+ * well-known types, which have no generated schemas) fail encoding and decoding.
+ */
+export class BinaryCodec {
+  constructor(protected schemaRegistry: Record<string, MessageSchema>) {}
+
+  /**
+   * Encode a message object into protobuf bytes
+   * @param messageType The fully qualified message type (e.g., "library.v1.Book")
+   */
+  encode(messageType: string, message: any): Uint8Array {
+    const schema = this.requireSchema(messageType);
+    const writer = new BinaryWriter();
+    this.writeMessage(writer, schema, message ?? {});
+    return writer.finish();
+  }
+
+  /**
+   * Decode protobuf bytes into a message object
+   * @param messageType The fully qualified message type (e.g., "library.v1.Book")
+   */
+  decode<T = any>(messageType: string, bytes: Uint8Array | null | undefined): T {
+    const schema = this.requireSchema(messageType);
+    const data = bytes ?? new Uint8Array(0);
+    return this.readMessage(new BinaryReader(data), schema) as T;
+  }
+
+  /**
+   * Look up the schema for a message type.
+   * Nested types are registered with flattened names (e.g., "utils.v1.Parent_Nested")
+   * while field references use the proto full name ("utils.v1.Parent.Nested"),
+   * so both forms are tried.
+   */
+  protected resolveSchema(messageType: string): MessageSchema | undefined {
+    let candidate = messageType;
+    while (true) {
+      const schema = this.schemaRegistry[candidate];
+      if (schema) {
+        return schema;
+      }
+      const lastDot = candidate.lastIndexOf('.');
+      if (lastDot < 0) {
+        return undefined;
+      }
+      candidate = candidate.substring(0, lastDot) + '_' + candidate.substring(lastDot + 1);
+    }
+  }
+
+  private requireSchema(messageType: string): MessageSchema {
+    const schema = this.resolveSchema(messageType);
+    if (!schema) {
+      throw new Error(`No schema registered for message type: ${messageType}`);
+    }
+    return schema;
+  }
+
+  private requireFieldSchema(field: FieldSchema, messageType: string): MessageSchema {
+    const schema = this.resolveSchema(messageType);
+    if (!schema) {
+      throw new Error(
+        `No schema registered for message type ${messageType} of field ${field.name}` +
+        ' (google.protobuf well-known types are not supported in binary wire format)',
+      );
+    }
+    return schema;
+  }
+
+  private writeMessage(writer: BinaryWriter, schema: MessageSchema, message: any): void {
+    for (const field of schema.fields) {
+      const value = message[field.name];
+      if (value === undefined || value === null) {
+        continue;
+      }
+
+      if (field.type === FieldType.MAP) {
+        this.writeMap(writer, field, value);
+      } else if (field.repeated) {
+        this.writeRepeated(writer, field, value);
+      } else if (field.type === FieldType.MESSAGE) {
+        this.writeNestedMessage(writer, field, field.id, field.messageType!, value);
+      } else {
+        const kind = this.scalarKind(schema, field);
+        // Proto3 implicit presence: defaults are not written unless presence is tracked
+        if (!field.optional && !field.oneofGroup && this.isDefaultScalar(kind, value)) {
+          continue;
+        }
+        this.writeScalar(writer, field.id, kind, value);
+      }
+    }
+  }
+
+  private writeRepeated(writer: BinaryWriter, field: FieldSchema, values: any[]): void {
+    if (!Array.isArray(values) || values.length === 0) {
+      return;
+    }
+
+    if (field.type === FieldType.MESSAGE) {
+      for (const value of values) {
+        this.writeNestedMessage(writer, field, field.id, field.messageType!, value);
+      }
+      return;
+    }
+
+    const kind = field.protoKind!;
+    if (PACKABLE_KINDS.has(kind)) {
+      const packed = new BinaryWriter();
+      for (const value of values) {
+        this.writeScalarValue(packed, kind, value);
+      }
+      writer.tag(field.id, WireType.LENGTH_DELIMITED);
+      writer.bytes(packed.finish());
+      return;
+    }
+
+    for (const value of values) {
+      this.writeScalar(writer, field.id, kind, value);
+    }
+  }
+
+  private writeMap(writer: BinaryWriter, field: FieldSchema, value: Record<string, any>): void {
+    const keyKind = field.mapKeyKind!;
+    const valueKind = field.mapValueKind!;
+    for (const [key, entryValue] of Object.entries(value)) {
+      const entry = new BinaryWriter();
+      this.writeScalar(entry, 1, keyKind, this.parseMapKey(keyKind, key));
+      if (valueKind === 'message') {
+        this.writeNestedMessage(entry, field, 2, field.mapValueType as string, entryValue ?? {});
+      } else {
+        this.writeScalar(entry, 2, valueKind, entryValue);
+      }
+      writer.tag(field.id, WireType.LENGTH_DELIMITED);
+      writer.bytes(entry.finish());
+    }
+  }
+
+  private writeNestedMessage(writer: BinaryWriter, field: FieldSchema, fieldNumber: number, messageType: string, value: any): void {
+    const schema = this.requireFieldSchema(field, messageType);
+    const nested = new BinaryWriter();
+    this.writeMessage(nested, schema, value);
+    writer.tag(fieldNumber, WireType.LENGTH_DELIMITED);
+    writer.bytes(nested.finish());
+  }
+
+  private writeScalar(writer: BinaryWriter, fieldNumber: number, kind: string, value: any): void {
+    writer.tag(fieldNumber, this.wireTypeFor(kind));
+    this.writeScalarValue(writer, kind, value);
+  }
+
+  private writeScalarValue(writer: BinaryWriter, kind: string, value: any): void {
+    switch (kind) {
+      case 'int32':
+      case 'enum':
+        writer.uint64(BigInt(Number(value) | 0));
+        break;
+      case 'uint32':
+        writer.uint32(Number(value));
+        break;
+      case 'sint32': {
+        const n = Number(value) | 0;
+        writer.uint32((n << 1) ^ (n >> 31));
+        break;
+      }
+      case 'int64':
+      case 'uint64':
+        writer.uint64(BigInt(value));
+        break;
+      case 'sint64': {
+        const n = BigInt.asIntN(64, BigInt(value));
+        writer.uint64((n << 1n) ^ (n >> 63n));
+        break;
+      }
+      case 'bool':
+        writer.uint32(value ? 1 : 0);
+        break;
+      case 'fixed32':
+        writer.fixed32(Number(value), false);
+        break;
+      case 'sfixed32':
+        writer.fixed32(Number(value), true);
+        break;
+      case 'fixed64':
+        writer.fixed64(BigInt(value), false);
+        break;
+      case 'sfixed64':
+        writer.fixed64(BigInt(value), true);
+        break;
+      case 'float':
+        writer.float(Number(value));
+        break;
+      case 'double':
+        writer.double(Number(value));
+        break;
+      case 'string':
+        writer.bytes(textEncoder.encode(String(value)));
+        break;
+      case 'bytes':
+        writer.bytes(value instanceof Uint8Array ? value : Uint8Array.from(value));
+        break;
+      default:
+        throw new Error(`Unsupported protobuf field kind: ${kind}`);
+    }
+  }
+
+  private readMessage(reader: BinaryReader, schema: MessageSchema): any {
+    const message: any = {};
+    const fieldsById = new Map<number, FieldSchema>();
+    for (const field of schema.fields) {
+      fieldsById.set(field.id, field);
+    }
+
+    while (reader.hasMore()) {
+      const tag = reader.uint32();
+      const fieldNumber = tag >>> 3;
+      const wireType = (tag & 7) as WireType;
+      const field = fieldsById.get(fieldNumber);
+      if (!field) {
+        reader.skip(wireType);
+        continue;
+      }
+
+      if (field.type === FieldType.MAP) {
+        this.readMapEntry(reader, field, message);
+      } else if (field.type === FieldType.MESSAGE) {
+        const value = this.readNestedMessage(reader, field, field.messageType!);
+        if (field.repeated) {
+          (message[field.name] ??= []).push(value);
+        } else {
+          message[field.name] = value;
+        }
+      } else {
+        const kind = this.scalarKind(schema, field);
+        if (field.repeated) {
+          const values = (message[field.name] ??= []);
+          if (wireType === WireType.LENGTH_DELIMITED && PACKABLE_KINDS.has(kind)) {
+            const packed = reader.bytes();
+            const packedReader = new BinaryReader(packed);
+            while (packedReader.hasMore()) {
+              values.push(this.readScalarValue(packedReader, kind));
+            }
+          } else {
+            values.push(this.readScalarValue(reader, kind));
+          }
+        } else {
+          message[field.name] = this.readScalarValue(reader, kind);
+        }
+      }
+    }
+
+    this.applyDefaults(schema, message);
+    return message;
+  }
+
+  private readMapEntry(reader: BinaryReader, field: FieldSchema, message: any): void {
+    const entryReader = new BinaryReader(reader.bytes());
+    const keyKind = field.mapKeyKind!;
+    const valueKind = field.mapValueKind!;
+    let key: any = this.defaultScalar(keyKind);
+    let value: any = valueKind === 'message' ? {} : this.defaultScalar(valueKind);
+
+    while (entryReader.hasMore()) {
+      const tag = entryReader.uint32();
+      const fieldNumber = tag >>> 3;
+      if (fieldNumber === 1) {
+        key = this.readScalarValue(entryReader, keyKind);
+      } else if (fieldNumber === 2) {
+        value = valueKind === 'message'
+          ? this.readNestedMessage(entryReader, field, field.mapValueType as string)
+          : this.readScalarValue(entryReader, valueKind);
+      } else {
+        entryReader.skip((tag & 7) as WireType);
+      }
+    }
+
+    (message[field.name] ??= {})[String(key)] = value;
+  }
+
+  private readNestedMessage(reader: BinaryReader, field: FieldSchema, messageType: string): any {
+    const schema = this.requireFieldSchema(field, messageType);
+    const bytes = reader.bytes();
+    return this.readMessage(new BinaryReader(bytes), schema);
+  }
+
+  private readScalarValue(reader: BinaryReader, kind: string): any {
+    switch (kind) {
+      case 'int32':
+      case 'enum':
+        return Number(BigInt.asIntN(32, reader.uint64()));
+      case 'uint32':
+        return reader.uint32();
+      case 'sint32': {
+        const n = reader.uint32();
+        return (n >>> 1) ^ -(n & 1);
+      }
+      case 'int64':
+        return Number(BigInt.asIntN(64, reader.uint64()));
+      case 'uint64':
+        return Number(reader.uint64());
+      case 'sint64': {
+        const n = reader.uint64();
+        return Number((n >> 1n) ^ -(n & 1n));
+      }
+      case 'bool':
+        return reader.uint64() !== 0n;
+      case 'fixed32':
+        return reader.fixed32(false);
+      case 'sfixed32':
+        return reader.fixed32(true);
+      case 'fixed64':
+        return Number(reader.fixed64(false));
+      case 'sfixed64':
+        return Number(reader.fixed64(true));
+      case 'float':
+        return reader.float();
+      case 'double':
+        return reader.double();
+      case 'string':
+        return textDecoder.decode(reader.bytes());
+      case 'bytes':
+        return reader.bytes().slice();
+      default:
+        throw new Error(`Unsupported protobuf field kind: ${kind}`);
+    }
+  }
+
+  /**
+   * Fill in proto3 default values for fields missing from the wire,
+   * matching what the JSON wire format emits for unpopulated fields
+   */
+  private applyDefaults(schema: MessageSchema, message: any): void {
+    for (const field of schema.fields) {
+      if (message[field.name] !== undefined || field.optional || field.oneofGroup) {
+        continue;
+      }
+      if (field.type === FieldType.MAP) {
+        message[field.name] = {};
+      } else if (field.repeated) {
+        message[field.name] = [];
+      } else if (field.type !== FieldType.MESSAGE) {
+        message[field.name] = this.defaultScalar(this.scalarKind(schema, field));
+      }
+    }
+  }
+
+  private scalarKind(schema: MessageSchema, field: FieldSchema): string {
+    if (!field.protoKind) {
+      throw new Error(`Schema for ${schema.name}.${field.name} has no protoKind - regenerate schemas to use the binary wire format`);
+    }
+    return field.protoKind;
+  }
+
+  private wireTypeFor(kind: string): WireType {
+    switch (kind) {
+      case 'fixed64':
+      case 'sfixed64':
+      case 'double':
+        return WireType.FIXED64;
+      case 'fixed32':
+      case 'sfixed32':
+      case 'float':
+        return WireType.FIXED32;
+      case 'string':
+      case 'bytes':
+        return WireType.LENGTH_DELIMITED;
+      default:
+        return WireType.VARINT;
+    }
+  }
+
+  private defaultScalar(kind: string): any {
+    switch (kind) {
+      case 'string':
+        return '';
+      case 'bool':
+        return false;
+      case 'bytes':
+        return new Uint8Array(0);
+      default:
+        return 0;
+    }
+  }
+
+  private isDefaultScalar(kind: string, value: any): boolean {
+    switch (kind) {
+      case 'string':
+        return value === '';
+      case 'bool':
+        return value === false;
+      case 'bytes':
+        return value.length === 0;
+      default:
+        return Number(value) === 0;
+    }
+  }
+
+  private parseMapKey(kind: string, key: string): any {
+    // Object keys are always strings; numeric kinds accept their string form
+    return kind === 'bool' ? key === 'true' : key;
+  }
+}
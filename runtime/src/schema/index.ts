@@ -20,3 +20,4 @@ export {
 
 export { type MessageTypeProvider, type MessageTypeConstructor, BaseDeserializer } from './base-deserializer.js';
 export { BaseSchemaRegistry } from './base-registry.js';
+export { BinaryCodec } from './binary-codec.js';
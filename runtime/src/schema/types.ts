@@ -33,9 +33,12 @@ export interface FieldSchema {
   type: FieldType;
   id: number; // Proto field number (e.g., text_query = 1)
   messageType?: string; // For MESSAGE type fields
+  protoKind?: string; // Proto scalar kind (e.g., "int32", "sint64", "enum") for binary encoding
   repeated?: boolean; // For array fields
   mapKeyType?: FieldType; // For MAP type fields
-  mapValueType?: FieldType | string; // For MAP type fields
+  mapValueType?: FieldType | string; // For MAP type fields (message type name for message values)
+  mapKeyKind?: string; // Proto kind of MAP keys
+  mapValueKind?: string; // Proto kind of MAP values ("message" for message values)
   oneofGroup?: string; // For ONEOF fields
   optional?: boolean;
 }
@@ -12,8 +12,8 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
-import { describe, it, expect, beforeEach } from 'vitest';
-import { WASMServiceClient, BrowserServiceManager, WasmError } from '../index.js';
+import { describe, it, expect, beforeEach, afterEach } from 'vitest';
+import { WASMServiceClient, WASMBundle, BrowserServiceManager, WasmError, StatusError, StatusCode, BinaryCodec, FieldType, MessageSchema, MessageEndpoint, startWorkerHost, HttpTransport, FallbackTransport, Transport } from '../index.js';
 
 // Mock WASM service client for testing inheritance
 class TestWASMClient extends WASMServiceClient {
@@ -26,6 +26,15 @@ class TestWASMClient extends WASMServiceClient {
                 success: true,
                 message: 'Success',
                 data: { result: 'test-data' }
+            }),
+            notFoundMethod: () => ({
+                success: false,
+                message: 'Service call failed: rpc error: code = NotFound desc = book not found',
+                error: {
+                    code: 5,
+                    message: 'book not found',
+                    details: [{ typeUrl: 'type.googleapis.com/google.protobuf.StringValue', value: new Uint8Array([10, 1, 120]), message: 'x' }]
+                }
             })
         };
     }
@@ -96,6 +105,22 @@ describe('Framework Runtime Integration Tests', () => {
             }
         });
 
+        it('should throw StatusError for failed calls with a gRPC status', async () => {
+            try {
+                await client.callMethod('notFoundMethod', {});
+                expect.fail('Should have thrown error');
+            } catch (error) {
+                expect(error).toBeInstanceOf(StatusError);
+                expect(error).toBeInstanceOf(WasmError);
+                const statusError = error as StatusError;
+                expect(statusError.code).toBe(StatusCode.NOT_FOUND);
+                expect(statusError.codeName).toBe('NOT_FOUND');
+                expect(statusError.message).toBe('book not found');
+                expect(statusError.details[0].message).toBe('x');
+                expect(statusError.methodPath).toBe('notFoundMethod');
+            }
+        });
+
         it('should handle callback methods', async () => {
             let callbackResult: any = null;
             let callbackError: string | undefined = undefined;
@@ -235,5 +260,417 @@ describe('BrowserServiceManager Tests', () => {
             manager.stopProcessing();
             expect(manager['processing']).toBe(false);
         });
+
+        it('should drain queued calls when WASM invokes the dispatcher', async () => {
+            const queued = [{ id: 'call_1' }, { id: 'call_2' }];
+            const processed: string[] = [];
+            (manager as any).getNextBrowserCall = () => queued.shift() ?? null;
+            (manager as any).processCall = async (call: any) => { processed.push(call.id); };
+
+            manager.startProcessing();
+            await Promise.resolve();
+            expect(processed).toEqual(['call_1', 'call_2']);
+
+            // WASM notifies the dispatcher when a new call is queued
+            queued.push({ id: 'call_3' });
+            (globalThis as any).__wasmBrowserCallDispatcher();
+            await Promise.resolve();
+            expect(processed).toEqual(['call_1', 'call_2', 'call_3']);
+
+            manager.stopProcessing();
+            expect((globalThis as any).__wasmBrowserCallDispatcher).toBeUndefined();
+        });
+
+        it('should abort browser calls cancelled by WASM', async () => {
+            let receivedSignal: AbortSignal | undefined;
+            manager.registerService('TestService', {
+                slowMethod: (_request: any, context: { signal: AbortSignal }) => {
+                    receivedSignal = context.signal;
+                    return new Promise((_resolve, reject) => {
+                        context.signal.addEventListener('abort', () => reject(new Error('aborted')));
+                    });
+                }
+            });
+            const delivered: string[] = [];
+            (manager as any).deliverBrowserResponse = (callId: string) => { delivered.push(callId); return true; };
+
+            manager.startProcessing();
+            const pending = (manager as any).processCall({ id: 'call_1', service: 'TestService', method: 'SlowMethod', request: '{}' });
+
+            (globalThis as any).__wasmBrowserCallCancelled('call_1', 'context canceled');
+            await pending;
+
+            expect(receivedSignal?.aborted).toBe(true);
+            expect(delivered).toEqual([]);
+            manager.stopProcessing();
+        });
+
+        it('should forward messages of streaming browser calls then end the stream', async () => {
+            manager.registerService('TestService', {
+                async *watch(request: any) {
+                    yield { value: `${request.name}-1` };
+                    yield { value: `${request.name}-2` };
+                },
+                listen: async (_request: any, context: { emit: (message: any) => void }) => {
+                    context.emit({ value: 'emitted' });
+                }
+            });
+            const messages: string[] = [];
+            const results: any[] = [];
+            (manager as any).deliverBrowserStreamMessage = (callId: string, message: string) => { messages.push(`${callId}:${message}`); return true; };
+            (manager as any).deliverBrowserResponse = (callId: string, response: any, error: any) => { results.push([callId, response, error]); return true; };
+
+            await (manager as any).processCall({ id: 'call_1', service: 'TestService', method: 'Watch', request: '{"name":"pos"}', streaming: true });
+            await (manager as any).processCall({ id: 'call_2', service: 'TestService', method: 'Listen', request: '{}', streaming: true });
+
+            expect(messages).toEqual(['call_1:{"value":"pos-1"}', 'call_1:{"value":"pos-2"}', 'call_2:{"value":"emitted"}']);
+            expect(results).toEqual([['call_1', null, null], ['call_2', null, null]]);
+        });
+
+        it('should decode and encode browser calls made with protobuf bytes', async () => {
+            const codec = new BinaryCodec({
+                'test.v1.KeyRequest': { name: 'KeyRequest', fields: [{ name: 'key', type: FieldType.STRING, id: 1, protoKind: 'string' }] },
+                'test.v1.KeyResponse': { name: 'KeyResponse', fields: [{ name: 'value', type: FieldType.STRING, id: 1, protoKind: 'string' }] },
+            });
+            manager.setCodec(codec);
+            manager.registerService('TestService', {
+                getItem: (request: any) => ({ value: `${request.key}-value` })
+            });
+            const results: any[] = [];
+            (manager as any).deliverBrowserResponse = (callId: string, response: any, error: any) => { results.push([callId, response, error]); return true; };
+
+            await (manager as any).processCall({
+                id: 'call_1', service: 'TestService', method: 'GetItem',
+                request: codec.encode('test.v1.KeyRequest', { key: 'theme' }),
+                requestType: 'test.v1.KeyRequest', responseType: 'test.v1.KeyResponse',
+            });
+
+            expect(results[0][2]).toBeNull();
+            expect(results[0][1]).toBeInstanceOf(Uint8Array);
+            expect(codec.decode('test.v1.KeyResponse', results[0][1])).toEqual({ value: 'theme-value' });
+        });
+
+        it('should bind to the globals of its named channel', () => {
+            const named = new BrowserServiceManager('myApp');
+            (globalThis as any).__wasmGetNextBrowserCall_myApp = () => ({ id: 'call_1' });
+            (globalThis as any).__wasmDeliverBrowserResponse_myApp = () => true;
+
+            expect(named['getNextBrowserCall']()).toEqual({ id: 'call_1' });
+            expect(named['deliverBrowserResponse']('call_1', '{}', null)).toBe(true);
+            expect(manager['getNextBrowserCall']()).toBeNull();
+
+            named.startProcessing();
+            expect(typeof (globalThis as any).__wasmBrowserCallDispatcher_myApp).toBe('function');
+            expect((globalThis as any).__wasmBrowserCallDispatcher).toBeUndefined();
+            named.stopProcessing();
+            expect((globalThis as any).__wasmBrowserCallDispatcher_myApp).toBeUndefined();
+
+            delete (globalThis as any).__wasmGetNextBrowserCall_myApp;
+            delete (globalThis as any).__wasmDeliverBrowserResponse_myApp;
+        });
+    });
+});
+
+describe('BinaryCodec Tests', () => {
+    const schemas: Record<string, MessageSchema> = {
+        'test.v1.Item': {
+            name: 'Item',
+            fields: [
+                { name: 'id', type: FieldType.NUMBER, id: 1, protoKind: 'int32' },
+                { name: 'label', type: FieldType.STRING, id: 2, protoKind: 'string' },
+                { name: 'delta', type: FieldType.NUMBER, id: 3, protoKind: 'sint64' },
+                { name: 'scores', type: FieldType.REPEATED, id: 4, protoKind: 'double', repeated: true },
+                { name: 'child', type: FieldType.MESSAGE, id: 5, messageType: 'test.v1.Item.Child' },
+                { name: 'counts', type: FieldType.MAP, id: 6, mapKeyKind: 'string', mapValueKind: 'int32' },
+            ],
+        },
+        'test.v1.Item_Child': {
+            name: 'Item_Child',
+            fields: [
+                { name: 'flag', type: FieldType.BOOLEAN, id: 1, protoKind: 'bool' },
+            ],
+        },
+    };
+
+    it('should encode scalars using the protobuf wire format', () => {
+        const codec = new BinaryCodec(schemas);
+        const bytes = codec.encode('test.v1.Item', { id: 150 });
+
+        expect(Array.from(bytes)).toEqual([0x08, 0x96, 0x01]);
+    });
+
+    it('should round trip messages through encode and decode', () => {
+        const codec = new BinaryCodec(schemas);
+        const item = {
+            id: -7,
+            label: 'héllo',
+            delta: -123456789012,
+            scores: [1.5, -2.25],
+            child: { flag: true },
+            counts: { a: 1, b: -1 },
+        };
+
+        const decoded = codec.decode('test.v1.Item', codec.encode('test.v1.Item', item));
+
+        expect(decoded).toEqual(item);
+    });
+
+    it('should fill proto3 defaults for missing fields', () => {
+        const codec = new BinaryCodec(schemas);
+        const decoded = codec.decode<any>('test.v1.Item', new Uint8Array(0));
+
+        expect(decoded).toEqual({ id: 0, label: '', delta: 0, scores: [], counts: {} });
+    });
+
+    it('should reject unknown message types', () => {
+        const codec = new BinaryCodec(schemas);
+
+        expect(() => codec.encode('test.v1.Missing', {})).toThrow();
+    });
+
+    it('should reject fields of message types without a schema', () => {
+        const codec = new BinaryCodec({
+            'test.v1.Event': {
+                name: 'Event',
+                fields: [
+                    { name: 'createdAt', type: FieldType.MESSAGE, id: 1, messageType: 'google.protobuf.Timestamp' },
+                ],
+            },
+        });
+
+        expect(() => codec.encode('test.v1.Event', { createdAt: {} }))
+            .toThrow('No schema registered for message type google.protobuf.Timestamp of field createdAt');
+        expect(() => codec.decode('test.v1.Event', new Uint8Array([0x0a, 0x00])))
+            .toThrow('No schema registered for message type google.protobuf.Timestamp of field createdAt');
+    });
+});
+
+// Connected endpoints standing in for a Worker and its global scope; messages are
+// structured-cloned and delivered asynchronously, as postMessage does
+function endpointPair(): [MessageEndpoint, MessageEndpoint] {
+    const listeners = [new Set<(event: MessageEvent) => void>(), new Set<(event: MessageEvent) => void>()];
+    const endpoint = (self: number, other: number): MessageEndpoint => ({
+        postMessage: (message: any) => {
+            const data = structuredClone(message);
+            setTimeout(() => listeners[other].forEach(listener => listener({ data } as MessageEvent)));
+        },
+        addEventListener: (_type, listener) => listeners[self].add(listener),
+        removeEventListener: (_type, listener) => listeners[self].delete(listener),
+    });
+    return [endpoint(0, 1), endpoint(1, 0)];
+}
+
+describe('Worker Transport Tests', () => {
+    const config = { moduleName: 'workerTest', apiStructure: 'namespaced' as const, jsNamespace: 'workerTest' };
+    let bundle: WASMBundle;
+    let worker: MessageEndpoint;
+    let scope: MessageEndpoint;
+
+    beforeEach(async () => {
+        // Exports the worker's bundle picks up as a pre-loaded module
+        (globalThis as any).workerTest = {
+            echoService: {
+                echo: (request: any) => ({ success: true, message: 'Success', data: { text: request.text } }),
+                missing: () => ({
+                    success: false,
+                    message: 'Service call failed: rpc error: code = NotFound desc = not found',
+                    error: { code: 5, message: 'not found', details: [] }
+                }),
+                count: (request: any, callback: Function) => {
+                    for (let i = 1; i <= request.n; i++) {
+                        callback({ n: i }, null, false);
+                    }
+                    callback(null, null, true);
+                    return { success: true, message: 'Stream started', data: null };
+                },
+            }
+        };
+
+        [worker, scope] = endpointPair();
+        startWorkerHost(config, scope);
+        bundle = new WASMBundle(config);
+        await bundle.loadWasmInWorker(worker, '/test.wasm');
+    });
+
+    afterEach(() => {
+        bundle.unload();
+        delete (globalThis as any).workerTest;
+    });
+
+    it('should call methods in the worker', async () => {
+        expect(bundle.isReady()).toBe(true);
+
+        const response = await bundle.callMethod<any, any>('echoService.echo', { text: 'hi' });
+
+        expect(response).toEqual({ text: 'hi' });
+    });
+
+    it('should reject with the status of failed calls', async () => {
+        const error = await bundle.callMethod('echoService.missing', {}).catch(e => e);
+
+        expect(error).toBeInstanceOf(StatusError);
+        expect(error.code).toBe(StatusCode.NOT_FOUND);
+    });
+
+    it('should stream responses from the worker', async () => {
+        const received = await new Promise<any[]>(resolve => {
+            const responses: any[] = [];
+            bundle.callStreamingMethod<any, any>('echoService.count', { n: 3 }, (response, error, done) => {
+                if (response) responses.push(response);
+                if (done) resolve(responses);
+                return true;
+            });
+        });
+
+        expect(received).toEqual([{ n: 1 }, { n: 2 }, { n: 3 }]);
+    });
+
+    it('should run browser services on the main thread', async () => {
+        bundle.registerBrowserService('BrowserAPI', {
+            getItem: async (request: any) => ({ value: `value of ${request.key}` })
+        });
+
+        const result = await new Promise<any>(resolve => {
+            scope.addEventListener('message', event => {
+                if (event.data.type === 'browserResult') resolve(event.data);
+            });
+            scope.postMessage({ type: 'browserCall', id: 1, service: 'BrowserAPI', method: 'GetItem', request: { key: 'k' }, streaming: false });
+        });
+
+        expect(result).toEqual({ type: 'browserResult', id: 1, response: { value: 'value of k' } });
+    });
+});
+
+// A streamed message: flags byte, big-endian length and the message's text
+function envelope(flags: number, text: string): Uint8Array {
+    const data = new TextEncoder().encode(text);
+    const frame = new Uint8Array(5 + data.length);
+    frame[0] = flags;
+    new DataView(frame.buffer).setUint32(1, data.length);
+    frame.set(data, 5);
+    return frame;
+}
+
+function concat(...frames: Uint8Array[]): Uint8Array {
+    const body = new Uint8Array(frames.reduce((size, frame) => size + frame.length, 0));
+    let offset = 0;
+    for (const frame of frames) {
+        body.set(frame, offset);
+        offset += frame.length;
+    }
+    return body;
+}
+
+describe('HTTP Transport Tests', () => {
+    const method = { fullMethod: '/test.v1.EchoService/Echo', requestType: 'test.v1.EchoRequest', responseType: 'test.v1.EchoResponse' };
+
+    it('should post Connect unary calls as JSON', async () => {
+        let url = '';
+        let init: RequestInit = {};
+        const transport = new HttpTransport({
+            baseUrl: 'https://api.example.com/',
+            fetch: async (input, options) => {
+                url = String(input);
+                init = options!;
+                return new Response(JSON.stringify({ text: 'hi' }), { headers: { 'trailer-x-count': '1' } });
+            },
+        });
+        let trailer = {};
+
+        const response = await transport.callMethod('echoService.echo', { text: 'hi' }, { timeoutMs: 500, onTrailer: t => trailer = t }, method);
+
+        expect(response).toEqual({ text: 'hi' });
+        expect(url).toBe('https://api.example.com/test.v1.EchoService/Echo');
+        expect(init.body).toBe('{"text":"hi"}');
+        expect((init.headers as Headers).get('connect-timeout-ms')).toBe('500');
+        expect(trailer).toEqual({ 'x-count': ['1'] });
+    });
+
+    it('should reject with the status of Connect errors', async () => {
+        const transport = new HttpTransport({
+            baseUrl: 'https://api.example.com',
+            fetch: async () => new Response(JSON.stringify({ code: 'not_found', message: 'not found' }), { status: 404 }),
+        });
+
+        const error = await transport.callMethod('echoService.echo', {}, undefined, method).catch(e => e);
+
+        expect(error).toBeInstanceOf(StatusError);
+        expect(error.code).toBe(StatusCode.NOT_FOUND);
+        expect(error.message).toBe('not found');
+    });
+
+    it('should stream gRPC-Web responses and read the status from trailers', async () => {
+        const transport = new HttpTransport({
+            baseUrl: 'https://api.example.com',
+            protocol: 'grpc-web',
+            fetch: async () => new Response(concat(
+                envelope(0x00, '{"n":1}'),
+                envelope(0x00, '{"n":2}'),
+                envelope(0x80, 'grpc-status: 5\r\ngrpc-message: no%20more\r\n')
+            )),
+        });
+
+        const [received, status] = await new Promise<[any[], StatusError | undefined]>(resolve => {
+            const responses: any[] = [];
+            transport.callStreamingMethod<any, any>('echoService.count', {}, (response, error, done, status) => {
+                if (response) responses.push(response);
+                if (done) resolve([responses, status]);
+                return true;
+            }, undefined, method);
+        });
+
+        expect(received).toEqual([{ n: 1 }, { n: 2 }]);
+        expect(status?.code).toBe(StatusCode.NOT_FOUND);
+        expect(status?.message).toBe('no more');
+    });
+
+    it('should fail calls without the full method name', async () => {
+        const transport = new HttpTransport({ baseUrl: 'https://api.example.com', fetch: async () => new Response('{}') });
+
+        await expect(transport.callMethod('echoService.echo', {})).rejects.toThrow(WasmError);
+    });
+});
+
+describe('Fallback Transport Tests', () => {
+    const method = { fullMethod: '/test.v1.EchoService/Echo', requestType: 'test.v1.EchoRequest', responseType: 'test.v1.EchoResponse' };
+    const server: Transport = new HttpTransport({
+        baseUrl: 'https://api.example.com',
+        fetch: async () => new Response(JSON.stringify({ text: 'from server' })),
+    });
+
+    afterEach(() => {
+        delete (globalThis as any).fallbackTest;
+    });
+
+    it('should call the bundle once it loads', async () => {
+        (globalThis as any).fallbackTest = {
+            echoService: { echo: () => ({ success: true, message: 'Success', data: { text: 'from wasm' } }) }
+        };
+        const bundle = new WASMBundle({ moduleName: 'fallbackTest', apiStructure: 'namespaced', jsNamespace: 'fallbackTest' });
+        const transport = new FallbackTransport(bundle, server);
+        bundle.loadWasm('/test.wasm');
+
+        const response = await transport.callMethod('echoService.echo', {}, undefined, method);
+
+        expect(response).toEqual({ text: 'from wasm' });
+        expect(transport.isUsingFallback).toBe(false);
+    });
+
+    it('should call the server when the bundle fails to load', async () => {
+        const bundle = new WASMBundle({
+            moduleName: 'fallbackTest',
+            apiStructure: 'namespaced',
+            jsNamespace: 'fallbackTest',
+            loader: async () => { throw new Error('Out of memory'); },
+        });
+        let reason: unknown;
+        const transport = new FallbackTransport(bundle, server, error => reason = error);
+        bundle.loadWasm('/test.wasm').catch(() => {});
+
+        const response = await transport.callMethod('echoService.echo', {}, undefined, method);
+
+        expect(response).toEqual({ text: 'from server' });
+        expect(transport.isUsingFallback).toBe(true);
+        expect(reason).toBeInstanceOf(Error);
     });
 });
@@ -0,0 +1,74 @@
+// Copyright 2025 Sri Panyam
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import { Metadata, WASMResponse } from '../client/types.js';
+
+/**
+ * Minimal postMessage endpoint: a Worker on the main thread, the worker's global scope
+ * inside it, or either end of a MessageChannel
+ */
+export interface MessageEndpoint {
+    postMessage(message: any): void;
+    addEventListener(type: 'message', listener: (event: MessageEvent) => void): void;
+    removeEventListener(type: 'message', listener: (event: MessageEvent) => void): void;
+}
+
+/**
+ * How a WASM export is invoked, following the calling conventions of the generated exports:
+ * - unary: fn(request, options) returns the response envelope
+ * - callback: fn(request, callback, options) for async and server-streaming methods
+ * - stream: fn(callback, options) returns a stream handle (client and bidi streaming)
+ */
+export type WorkerCallKind = 'unary' | 'callback' | 'stream';
+
+/**
+ * The structured-clone-safe part of CallOptions. The AbortSignal and metadata
+ * callbacks stay on the main thread; flags tell the worker to proxy them.
+ */
+export interface WorkerCallOptions {
+    timeoutMs?: number;
+    headers?: Record<string, string | string[]>;
+    hasSignal?: boolean;
+    hasOnHeader?: boolean;
+    hasOnTrailer?: boolean;
+}
+
+/**
+ * Messages posted from the main thread to the worker
+ */
+export type ToWorkerMessage =
+    | { type: 'load'; wasmPath: string }
+    | { type: 'unload' }
+    | { type: 'registerBrowserService'; name: string }
+    | { type: 'call'; id: number; kind: WorkerCallKind; methodPath: string; request?: unknown; options?: WorkerCallOptions }
+    | { type: 'abort'; id: number }          // The call's AbortSignal fired
+    | { type: 'stop'; id: number }           // A streaming callback returned false
+    | { type: 'send'; id: number; request: unknown }
+    | { type: 'closeSend'; id: number }
+    | { type: 'cancel'; id: number }
+    | { type: 'browserResult'; id: number; response?: unknown; error?: string }
+    | { type: 'browserStreamMessage'; id: number; message: unknown };
+
+/**
+ * Messages posted from the worker to the main thread
+ */
+export type FromWorkerMessage =
+    | { type: 'loaded' }
+    | { type: 'loadFailed'; error: string }
+    | { type: 'returned'; id: number; response: WASMResponse }     // What the WASM export returned
+    | { type: 'callback'; id: number; args: unknown[] }            // Arguments the export passed to its callback
+    | { type: 'header' | 'trailer'; id: number; metadata: Metadata }
+    | { type: 'sendFailed'; id: number; response: WASMResponse }
+    | { type: 'browserCall'; id: number; service: string; method: string; request: unknown; streaming: boolean }
+    | { type: 'browserCancel'; id: number; reason: string };   // WASM cancelled the call or stopped reading its stream
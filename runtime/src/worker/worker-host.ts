@@ -0,0 +1,252 @@
+// Copyright 2025 Sri Panyam
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import { BrowserCallContext, BrowserStreamCallContext } from '../browser/service-manager.js';
+import { WASMBundle, WASMBundleConfig } from '../client/wasm-bundle.js';
+import { StreamHandle } from '../client/stream-call.js';
+import { WASMResponse } from '../client/types.js';
+import { FromWorkerMessage, MessageEndpoint, ToWorkerMessage, WorkerCallOptions } from './protocol.js';
+
+/**
+ * Configuration of a worker hosting a WASM module. It must match the bundle on the main
+ * thread; the wire format does not matter here, as requests and responses pass through
+ * the worker as the main thread encoded them. Schemas are needed only to decode browser
+ * service calls made with protobuf bytes (browser clients generated with target=tinygo).
+ */
+export type WorkerHostConfig = Pick<WASMBundleConfig, 'moduleName' | 'apiStructure' | 'jsNamespace' | 'wasmExecPath' | 'schemas'>;
+
+/**
+ * A call of a WASM export running in the worker
+ */
+interface HostedCall {
+    controller?: AbortController;
+    stopped: boolean;
+    handle?: StreamHandle;
+}
+
+/**
+ * A browser service call forwarded to the main thread
+ */
+interface ForwardedBrowserCall {
+    resolve: (response: any) => void;
+    reject: (error: Error) => void;
+    emit?: (message: any) => void;
+}
+
+/**
+ * Host a WASM module inside a Web Worker, serving the main thread's WorkerTransport
+ * (created by WASMBundle.loadWasmInWorker). Call it from the worker entry point:
+ *
+ *     // worker.ts (generated with generate_worker=true)
+ *     import { startWorkerHost } from '@protoc-gen-go-wasmjs/runtime';
+ *     startWorkerHost({ moduleName: 'app', apiStructure: 'namespaced', jsNamespace: 'app' });
+ *
+ * CPU-heavy Go methods then run off the main thread, while browser-provided services
+ * are forwarded to the implementations registered on the main thread.
+ */
+export function startWorkerHost(config: WorkerHostConfig, scope: MessageEndpoint = globalThis as any): void {
+    new WorkerHost(config, scope);
+}
+
+/**
+ * Worker side of the worker transport: invokes the WASM exports for the main thread
+ */
+class WorkerHost {
+    private readonly bundle: WASMBundle;
+    private calls = new Map<number, HostedCall>();
+    private nextBrowserCallId = 1;
+    private browserCalls = new Map<number, ForwardedBrowserCall>();
+
+    constructor(config: WorkerHostConfig, private readonly scope: MessageEndpoint) {
+        // Pass payloads through unchanged: the main thread encodes and decodes them
+        this.bundle = new WASMBundle({ ...config, wireFormat: 'json' });
+        scope.addEventListener('message', event => this.handle(event.data as ToWorkerMessage));
+    }
+
+    private handle(message: ToWorkerMessage): void {
+        switch (message.type) {
+            case 'load':
+                this.bundle.loadWasm(message.wasmPath)
+                    .then(() => this.post({ type: 'loaded' }))
+                    .catch(error => this.post({ type: 'loadFailed', error: error?.message || String(error) }));
+                return;
+
+            case 'unload':
+                this.bundle.unload();
+                return;
+
+            case 'registerBrowserService':
+                this.bundle.registerBrowserService(message.name, this.browserServiceProxy(message.name));
+                return;
+
+            case 'call':
+                this.invoke(message);
+                return;
+
+            case 'abort':
+                this.calls.get(message.id)?.controller?.abort();
+                return;
+
+            case 'stop': {
+                const call = this.calls.get(message.id);
+                if (call) call.stopped = true;
+                return;
+            }
+
+            case 'send': {
+                const handle = this.calls.get(message.id)?.handle;
+                if (!handle) return;
+                const response = handle.send(message.request);
+                if (!response.success) {
+                    this.post({ type: 'sendFailed', id: message.id, response: this.cloneable(response) });
+                }
+                return;
+            }
+
+            case 'closeSend':
+                this.calls.get(message.id)?.handle?.closeSend();
+                return;
+
+            case 'cancel':
+                this.calls.get(message.id)?.handle?.cancel();
+                return;
+
+            case 'browserResult': {
+                const call = this.browserCalls.get(message.id);
+                if (!call) return;
+                this.browserCalls.delete(message.id);
+                if (message.error !== undefined) {
+                    call.reject(new Error(message.error));
+                } else {
+                    call.resolve(message.response);
+                }
+                return;
+            }
+
+            case 'browserStreamMessage':
+                this.browserCalls.get(message.id)?.emit?.(message.message);
+                return;
+        }
+    }
+
+    /**
+     * Invoke a WASM export with the calling convention of its kind, posting back what it
+     * returns and every call of its callback
+     */
+    private invoke(message: Extract<ToWorkerMessage, { type: 'call' }>): void {
+        const { id } = message;
+        const call: HostedCall = { stopped: false };
+        this.calls.set(id, call);
+
+        const callback = (...args: any[]): boolean => {
+            // Async callbacks are called once; streaming callbacks until done
+            const done = typeof args[2] === 'boolean' ? args[2] : true;
+            if (done) {
+                this.calls.delete(id);
+            }
+            this.post({ type: 'callback', id, args });
+            return !call.stopped;
+        };
+
+        let response: WASMResponse;
+        try {
+            const wasmMethod = this.bundle.getWasmMethod(message.methodPath);
+            const options = this.callOptions(id, call, message.options);
+            switch (message.kind) {
+                case 'unary':
+                    response = wasmMethod(message.request, options);
+                    this.calls.delete(id);
+                    break;
+                case 'callback':
+                    response = wasmMethod(message.request, callback, options);
+                    break;
+                case 'stream':
+                    response = wasmMethod(callback, options);
+                    if (response.success) {
+                        call.handle = response as unknown as StreamHandle;
+                    }
+                    break;
+            }
+        } catch (error: any) {
+            response = { success: false, message: error?.message || String(error), data: null };
+        }
+
+        if (!response.success) {
+            this.calls.delete(id);
+        }
+        this.post({ type: 'returned', id, response: this.cloneable(response) });
+    }
+
+    /**
+     * Rebuild the call options on this side: an AbortSignal aborted by 'abort' messages,
+     * and metadata callbacks that post the metadata back
+     */
+    private callOptions(id: number, call: HostedCall, options?: WorkerCallOptions): any {
+        if (!options) {
+            return undefined;
+        }
+        if (options.hasSignal) {
+            call.controller = new AbortController();
+        }
+        return {
+            timeoutMs: options.timeoutMs,
+            headers: options.headers,
+            signal: call.controller?.signal,
+            onHeader: options.hasOnHeader ? (metadata: any) => this.post({ type: 'header', id, metadata }) : undefined,
+            onTrailer: options.hasOnTrailer ? (metadata: any) => this.post({ type: 'trailer', id, metadata }) : undefined,
+        };
+    }
+
+    /**
+     * Keep the fields of an envelope that can be posted; stream handles carry functions
+     */
+    private cloneable(response: WASMResponse): WASMResponse {
+        return { success: response.success, message: response.message, data: response.data ?? null, error: response.error };
+    }
+
+    /**
+     * A stand-in for a main-thread browser service implementation. The worker's
+     * BrowserServiceManager calls its methods, which forward the call to the main thread.
+     */
+    private browserServiceProxy(service: string): any {
+        return new Proxy({}, {
+            // Not thenable, so the proxy is never mistaken for a promise
+            get: (_target, method) => typeof method === 'string' && method !== 'then'
+                ? (request: any, context: BrowserCallContext | BrowserStreamCallContext) => this.forwardBrowserCall(service, method, request, context)
+                : undefined,
+        });
+    }
+
+    private forwardBrowserCall(service: string, method: string, request: any, context: BrowserCallContext | BrowserStreamCallContext): Promise<any> {
+        const id = this.nextBrowserCallId++;
+        const streaming = 'emit' in context;
+
+        return new Promise((resolve, reject) => {
+            this.browserCalls.set(id, { resolve, reject, emit: streaming ? context.emit : undefined });
+
+            // WASM cancelled the call, timed it out or stopped reading its stream
+            context.signal.addEventListener('abort', () => {
+                if (!this.browserCalls.delete(id)) return;
+                this.post({ type: 'browserCancel', id, reason: String(context.signal.reason ?? 'cancelled') });
+                reject(context.signal.reason);
+            }, { once: true });
+
+            this.post({ type: 'browserCall', id, service, method, request, streaming });
+        });
+    }
+
+    private post(message: FromWorkerMessage): void {
+        this.scope.postMessage(message);
+    }
+}
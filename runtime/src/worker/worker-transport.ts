@@ -0,0 +1,268 @@
+// Copyright 2025 Sri Panyam
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//  http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import { BrowserServiceManager, BrowserCallContext, BrowserStreamCallContext } from '../browser/service-manager.js';
+import { CallOptions, WASMResponse } from '../client/types.js';
+import { StreamHandle } from '../client/stream-call.js';
+import { FromWorkerMessage, MessageEndpoint, ToWorkerMessage, WorkerCallKind, WorkerCallOptions } from './protocol.js';
+
+/**
+ * A call in flight in the worker
+ */
+interface PendingCall {
+    kind: WorkerCallKind;
+    options?: CallOptions;
+    returned: (response: WASMResponse) => void;
+    callback?: (...args: any[]) => any;
+    removeAbortListener?: () => void;
+}
+
+/**
+ * Main-thread side of a WASM module hosted in a Web Worker (see startWorkerHost).
+ *
+ * Calls are posted to the worker, which invokes the WASM exports with the same arguments
+ * a main-thread bundle would and posts back what they return and pass to their callbacks.
+ * Browser-provided services keep running on the main thread: the worker forwards their
+ * calls here, where they run on the bundle's BrowserServiceManager.
+ *
+ * WASMBundle.loadWasmInWorker creates the transport; generated clients are unchanged.
+ */
+export class WorkerTransport {
+    private nextId = 1;
+    private calls = new Map<number, PendingCall>();
+    private browserCalls = new Map<number, AbortController>();
+    private loadResult: { resolve: () => void; reject: (error: Error) => void } | null = null;
+
+    constructor(
+        private readonly worker: MessageEndpoint & { terminate?(): void },
+        private readonly browserServices: BrowserServiceManager
+    ) {
+        this.worker.addEventListener('message', this.onMessage);
+    }
+
+    /**
+     * Load the WASM module in the worker
+     */
+    public load(wasmPath: string): Promise<void> {
+        return new Promise((resolve, reject) => {
+            this.loadResult = { resolve, reject };
+            for (const name of this.browserServices.serviceNames) {
+                this.post({ type: 'registerBrowserService', name });
+            }
+            this.post({ type: 'load', wasmPath });
+        });
+    }
+
+    /**
+     * Let the worker forward calls of a browser service registered after loading
+     */
+    public registerBrowserService(name: string): void {
+        this.post({ type: 'registerBrowserService', name });
+    }
+
+    /**
+     * Call a synchronous export; resolves with the response envelope it returned
+     */
+    public call(methodPath: string, request: unknown, options?: CallOptions): Promise<WASMResponse> {
+        return new Promise(resolve => this.start('unary', methodPath, request, options, resolve));
+    }
+
+    /**
+     * Call an async or server-streaming export. The callback receives the arguments the
+     * export passes to its callback; returning false from a streaming callback stops the
+     * stream. Resolves with the envelope the export returned when starting the call.
+     */
+    public callWithCallback(methodPath: string, request: unknown, callback: (...args: any[]) => any, options?: CallOptions): Promise<WASMResponse> {
+        return new Promise(resolve => this.start('callback', methodPath, request, options, resolve, callback));
+    }
+
+    /**
+     * Start a client-streaming or bidirectional export. The returned handle posts requests
+     * without waiting for the worker; a request the export rejects, or a failure to start
+     * the call, ends the call through the callback.
+     */
+    public openStream(methodPath: string, callback: (responseData: any, error: string | null, done: boolean, status?: any) => boolean, options?: CallOptions): StreamHandle {
+        const id = this.start('stream', methodPath, undefined, options, response => {
+            if (!response.success) {
+                this.finishCall(id);
+                callback(null, response.message, true, response.error);
+            }
+        }, callback);
+
+        const sent: WASMResponse = { success: true, message: 'Request sent', data: null };
+        return {
+            send: (request: unknown) => {
+                this.post({ type: 'send', id, request });
+                return sent;
+            },
+            closeSend: () => {
+                this.post({ type: 'closeSend', id });
+                return { success: true, message: 'Send closed', data: null };
+            },
+            cancel: () => this.post({ type: 'cancel', id }),
+        };
+    }
+
+    /**
+     * Unload the WASM module and stop the worker
+     */
+    public terminate(): void {
+        this.post({ type: 'unload' });
+        this.worker.removeEventListener('message', this.onMessage);
+        this.worker.terminate?.();
+        for (const controller of this.browserCalls.values()) {
+            controller.abort('worker terminated');
+        }
+        this.browserCalls.clear();
+        this.calls.clear();
+    }
+
+    private start(kind: WorkerCallKind, methodPath: string, request: unknown, options: CallOptions | undefined, returned: (response: WASMResponse) => void, callback?: (...args: any[]) => any): number {
+        const id = this.nextId++;
+        const call: PendingCall = { kind, options, returned, callback };
+        this.calls.set(id, call);
+
+        // The signal stays here; the worker aborts its own controller when told to
+        const signal = options?.signal;
+        if (signal) {
+            const onAbort = () => this.post({ type: 'abort', id });
+            signal.addEventListener('abort', onAbort, { once: true });
+            call.removeAbortListener = () => signal.removeEventListener('abort', onAbort);
+        }
+
+        this.post({ type: 'call', id, kind, methodPath, request, options: this.workerOptions(options) });
+        return id;
+    }
+
+    /**
+     * Strip the parts of CallOptions that cannot be cloned, flagging them for the worker to proxy
+     */
+    private workerOptions(options?: CallOptions): WorkerCallOptions | undefined {
+        if (!options) {
+            return undefined;
+        }
+        return {
+            timeoutMs: options.timeoutMs,
+            headers: options.headers,
+            hasSignal: !!options.signal,
+            hasOnHeader: !!options.onHeader,
+            hasOnTrailer: !!options.onTrailer,
+        };
+    }
+
+    private finishCall(id: number): void {
+        const call = this.calls.get(id);
+        if (call) {
+            call.removeAbortListener?.();
+            this.calls.delete(id);
+        }
+    }
+
+    private readonly onMessage = (event: MessageEvent): void => {
+        const message = event.data as FromWorkerMessage;
+        switch (message.type) {
+            case 'loaded':
+                this.loadResult?.resolve();
+                this.loadResult = null;
+                return;
+
+            case 'loadFailed':
+                this.loadResult?.reject(new Error(message.error));
+                this.loadResult = null;
+                return;
+
+            case 'returned': {
+                const call = this.calls.get(message.id);
+                if (!call) return;
+                if (call.kind === 'unary' || !message.response.success) {
+                    this.finishCall(message.id);
+                }
+                call.returned(message.response);
+                return;
+            }
+
+            case 'callback': {
+                const call = this.calls.get(message.id);
+                if (!call?.callback) return;
+                // Async callbacks are called once; streaming callbacks until done
+                const done = typeof message.args[2] === 'boolean' ? message.args[2] : true;
+                if (done) {
+                    this.finishCall(message.id);
+                }
+                if (call.callback(...message.args) === false && !done) {
+                    this.post({ type: 'stop', id: message.id });
+                }
+                return;
+            }
+
+            case 'header':
+            case 'trailer': {
+                const options = this.calls.get(message.id)?.options;
+                const onMetadata = message.type === 'header' ? options?.onHeader : options?.onTrailer;
+                onMetadata?.(message.metadata);
+                return;
+            }
+
+            case 'sendFailed': {
+                const call = this.calls.get(message.id);
+                if (!call?.callback) return;
+                this.finishCall(message.id);
+                call.callback(null, message.response.message, true, message.response.error);
+                this.post({ type: 'cancel', id: message.id });
+                return;
+            }
+
+            case 'browserCall':
+                this.runBrowserCall(message);
+                return;
+
+            case 'browserCancel':
+                this.browserCalls.get(message.id)?.abort(message.reason);
+                return;
+        }
+    };
+
+    /**
+     * Run a browser service call forwarded by the worker and post back its result
+     */
+    private async runBrowserCall(message: Extract<FromWorkerMessage, { type: 'browserCall' }>): Promise<void> {
+        const controller = new AbortController();
+        this.browserCalls.set(message.id, controller);
+
+        const context: BrowserCallContext | BrowserStreamCallContext = message.streaming
+            ? {
+                signal: controller.signal,
+                emit: (streamMessage: any) => {
+                    if (controller.signal.aborted) return;
+                    this.post({ type: 'browserStreamMessage', id: message.id, message: streamMessage });
+                },
+            }
+            : { signal: controller.signal };
+
+        try {
+            const response = await this.browserServices.invoke(message.service, message.method, message.request, context);
+            if (controller.signal.aborted) return;
+            this.post({ type: 'browserResult', id: message.id, response: message.streaming ? undefined : response });
+        } catch (error: any) {
+            if (controller.signal.aborted) return;
+            this.post({ type: 'browserResult', id: message.id, error: error?.message || String(error) });
+        } finally {
+            this.browserCalls.delete(message.id);
+        }
+    }
+
+    private post(message: ToWorkerMessage): void {
+        this.worker.postMessage(message);
+    }
+}
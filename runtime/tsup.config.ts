@@ -7,6 +7,8 @@ export default defineConfig({
     'schema/index': 'src/schema/index.ts',
     'client/index': 'src/client/index.ts',
     'types/index': 'src/types/index.ts',
+    'worker/index': 'src/worker/index.ts',
+    'node/index': 'src/node/index.ts',
   },
   format: ['cjs', 'esm'],
   dts: true,
@@ -17,5 +19,5 @@ export default defineConfig({
   minify: false, // Keep readable for debugging
   target: 'es2020',
   platform: 'browser',
-  external: [],
+  external: [/^node:/], // Node built-ins used by the node entry only
 });